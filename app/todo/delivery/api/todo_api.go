@@ -1,7 +1,11 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/ariefsn/go-resik/common"
 	"github.com/ariefsn/go-resik/domain"
@@ -20,14 +24,27 @@ func NewTodoApi(todoSvc domain.TodoService) *fiber.App {
 		todoSvc: todoSvc,
 	}
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: helper.ErrorHandler,
+	})
 
 	app.Post("/", api.Create).Name("todoCreate")
 	app.Get("/", api.Get).Name("todoGet")
+	app.Get("/search", api.Search).Name("todoSearch")
+	app.Get("/tags", api.GetTags).Name("todoGetTags")
+	app.Get("/overdue", api.GetOverdue).Name("todoGetOverdue")
+	app.Get("/trash", api.GetTrash).Name("todoGetTrash")
+	app.Delete("/trash", api.Purge)
 	app.Get("/:id", api.GetByID).Name("todoGetById")
 	app.Put("/:id", api.Update)
 	app.Patch("/:id", api.UpdateStatus)
 	app.Delete("/:id", api.Delete)
+	app.Get("/:id/subtree", api.GetSubtree).Name("todoGetSubtree")
+	app.Post("/:id/tags", api.AddTags)
+	app.Delete("/:id/tags/:tag", api.RemoveTag)
+	app.Post("/:id/restore", api.Restore)
+	app.Get("/:id/history", api.GetHistory).Name("todoGetHistory")
+	app.Post("/:id/history/:revisionId/revert", api.Revert)
 
 	return app
 }
@@ -37,56 +54,208 @@ func (a *TodoApi) Create(c *fiber.Ctx) error {
 
 	if err := c.BodyParser(&payload); err != nil {
 		logger.Error(err)
-		return c.Status(http.StatusBadRequest).JSON(helper.JsonError(err))
+		return domain.NewValidationError(err.Error(), err)
+	}
+
+	if err := helper.Validate(&payload); err != nil {
+		return err
 	}
 
 	res, err := a.todoSvc.Create(c.UserContext(), &payload)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.JsonError(err))
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
 	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
 }
 
 func (a *TodoApi) GetByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	res, err := a.todoSvc.GetByID(c.UserContext(), id)
+	fields, err := helper.ParseFields(c.Query("fields"), domain.TodoFields)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.JsonError(err))
+		return err
 	}
 
-	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+	// the version is read along with the selected fields for the entity tag
+	selected := fields
+	if len(fields) > 0 && !slices.Contains(fields, "version") {
+		selected = append(slices.Clone(fields), "version")
+	}
+
+	res, err := a.todoSvc.GetByID(c.UserContext(), id, selected...)
+
+	if err != nil {
+		return err
+	}
+
+	// the body of a projection differs from the whole todo of the same version, its entity tag is weak
+	etag := helper.ETag(res.Version)
+	if len(fields) > 0 {
+		etag = helper.WeakETag(res.Version)
+	}
+
+	c.Set(fiber.HeaderETag, etag)
+
+	if helper.IfNoneMatch(c, res.Version) {
+		return c.SendStatus(http.StatusNotModified)
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(helper.SelectFields(res, fields)))
 }
 
 func (a *TodoApi) Get(c *fiber.Ctx) error {
 	skip := c.QueryInt("skip", 0)
 	limit := c.QueryInt("limit", 10)
 
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
 	title := c.Query("title")
 	description := c.Query("description")
 
-	filter := common.M{}
+	filter, err := helper.ParseFilter(c.Queries(), domain.TodoFilterFields)
+
+	if err != nil {
+		return err
+	}
 
 	if title != "" {
-		filter["title"] = title
+		filter.And = append(filter.And, domain.Where("title", domain.FoContains, title))
 	}
 
 	if description != "" {
-		filter["description"] = description
+		filter.And = append(filter.And, domain.Where("description", domain.FoContains, description))
 	}
 
-	res, total, err := a.todoSvc.Get(c.UserContext(), filter, int64(skip), int64(limit))
+	sort, err := helper.ParseSort(c.Query("sort"), domain.TodoSortFields)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.JsonError(err))
+		return err
 	}
 
+	fields, err := helper.ParseFields(c.Query("fields"), domain.TodoFields)
+
+	if err != nil {
+		return err
+	}
+
+	// the cursor query switches to keyset pagination, an empty cursor reads the first page
+	if c.Context().QueryArgs().Has("cursor") {
+		return a.getByCursor(c, query, filter, sort, fields, int64(limit))
+	}
+
+	res, total, err := a.todoSvc.Get(c.UserContext(), filter, sort, int64(skip), int64(limit), fields...)
+
+	if err != nil {
+		return err
+	}
+
+	links := helper.PageLinks(c.Path(), query, int64(skip), int64(limit), total)
+
+	c.Links(links.ToLinks()...)
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.PageModel{
+		Items:   helper.SelectFields(res, fields),
+		Skip:    int64(skip),
+		Limit:   int64(limit),
+		Total:   total,
+		HasMore: int64(skip+len(res)) < total,
+		Links:   links,
+	}))
+}
+
+func (a *TodoApi) getByCursor(c *fiber.Ctx, query url.Values, filter *domain.Filter, sort []domain.Sort, fields []string, limit int64) error {
+	cursor, err := helper.DecodeCursor(c.Query("cursor"), sort, domain.TodoFilterFields)
+
+	if err != nil {
+		return err
+	}
+
+	page, err := a.todoSvc.GetByCursor(c.UserContext(), filter, sort, cursor, limit, fields...)
+
+	if err != nil {
+		return err
+	}
+
+	next := helper.EncodeCursor(page.Next, sort)
+	prev := helper.EncodeCursor(page.Prev, sort)
+	links := helper.CursorLinks(c.Path(), query, next, prev)
+
+	c.Links(links.ToLinks()...)
+
 	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.M{
-		"items": res,
-		"total": total,
+		"items":      helper.SelectFields(page.Items, fields),
+		"nextCursor": next,
+		"prevCursor": prev,
+		"links":      links,
+	}))
+}
+
+func (a *TodoApi) Search(c *fiber.Ctx) error {
+	skip := c.QueryInt("skip", 0)
+	limit := c.QueryInt("limit", 10)
+
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	res, total, err := a.todoSvc.Search(c.UserContext(), c.Query("q"), int64(skip), int64(limit))
+
+	if err != nil {
+		return err
+	}
+
+	links := helper.PageLinks(c.Path(), query, int64(skip), int64(limit), total)
+
+	c.Links(links.ToLinks()...)
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.PageModel{
+		Items:   res,
+		Skip:    int64(skip),
+		Limit:   int64(limit),
+		Total:   total,
+		HasMore: int64(skip+len(res)) < total,
+		Links:   links,
+	}))
+}
+
+func (a *TodoApi) GetOverdue(c *fiber.Ctx) error {
+	skip := c.QueryInt("skip", 0)
+	limit := c.QueryInt("limit", 10)
+
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	sort, err := helper.ParseSort(c.Query("sort"), domain.TodoSortFields)
+
+	if err != nil {
+		return err
+	}
+
+	fields, err := helper.ParseFields(c.Query("fields"), domain.TodoFields)
+
+	if err != nil {
+		return err
+	}
+
+	res, total, err := a.todoSvc.GetOverdue(c.UserContext(), sort, int64(skip), int64(limit), fields...)
+
+	if err != nil {
+		return err
+	}
+
+	links := helper.PageLinks(c.Path(), query, int64(skip), int64(limit), total)
+
+	c.Links(links.ToLinks()...)
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.PageModel{
+		Items:   helper.SelectFields(res, fields),
+		Skip:    int64(skip),
+		Limit:   int64(limit),
+		Total:   total,
+		HasMore: int64(skip+len(res)) < total,
+		Links:   links,
 	}))
 }
 
@@ -97,47 +266,276 @@ func (a *TodoApi) Update(c *fiber.Ctx) error {
 
 	if err := c.BodyParser(&payload); err != nil {
 		logger.Error(err)
-		return c.Status(http.StatusBadRequest).JSON(helper.JsonError(err))
+		return domain.NewValidationError(err.Error(), err)
+	}
+
+	if err := helper.Validate(&payload); err != nil {
+		return err
 	}
 
-	res, err := a.todoSvc.Update(c.UserContext(), id, &payload)
+	version, err := helper.IfMatch(c)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.JsonError(err))
+		return err
+	}
+
+	// with If-None-Match: * the todo is created with the id when it's missing, it's replaced otherwise
+	if helper.IfNoneMatchAny(c) {
+		return a.replace(c, id, &payload, version)
+	}
+
+	res, err := a.todoSvc.Update(c.UserContext(), id, &payload, version)
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoApi) replace(c *fiber.Ctx, id string, payload *domain.TodoDto, version int64) error {
+	if version > 0 {
+		return domain.NewFieldValidationError(domain.FieldError{
+			Field:   fiber.HeaderIfMatch,
+			Rule:    "excluded_with",
+			Message: fmt.Sprintf("%s can't be used along with %s: *", fiber.HeaderIfMatch, fiber.HeaderIfNoneMatch),
+		})
+	}
+
+	// the created todo keeps the id, it must be one like the generated ones
+	params := struct {
+		ID string `json:"id" validate:"mongodb"`
+	}{ID: id}
+
+	if err := helper.Validate(&params); err != nil {
+		return err
+	}
+
+	// the param is only valid during the request, a created todo keeps the id
+	res, created, err := a.todoSvc.Replace(c.UserContext(), strings.Clone(id), payload)
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
+	if created {
+		return c.Status(http.StatusCreated).JSON(helper.JsonSuccess(res))
 	}
 
 	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
 }
 
 func (a *TodoApi) UpdateStatus(c *fiber.Ctx) error {
-	payload := struct {
-		IsCompleted bool `json:"isCompleted"`
-	}{}
+	payload := domain.TodoStatusDto{}
 
 	id := c.Params("id")
 
 	if err := c.BodyParser(&payload); err != nil {
 		logger.Error(err)
-		return c.Status(http.StatusBadRequest).JSON(helper.JsonError(err))
+		return domain.NewValidationError(err.Error(), err)
 	}
 
-	res, err := a.todoSvc.UpdateStatus(c.UserContext(), id, payload.IsCompleted)
+	version, err := helper.IfMatch(c)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.JsonError(err))
+		return err
 	}
 
+	// completing reports the incomplete subtasks, or completes them as well with cascade
+	if payload.IsCompleted {
+		res, err := a.todoSvc.Complete(c.UserContext(), id, payload.Cascade, version)
+
+		if err != nil {
+			return err
+		}
+
+		c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
+		return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+	}
+
+	res, err := a.todoSvc.UpdateStatus(c.UserContext(), id, false, version)
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
 	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
 }
 
 func (a *TodoApi) Delete(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	err := a.todoSvc.Delete(c.UserContext(), id)
+	version, err := helper.IfMatch(c)
+
+	if err != nil {
+		return err
+	}
+
+	err = a.todoSvc.Delete(c.UserContext(), id, version)
 
 	if err != nil {
-		return c.Status(http.StatusInternalServerError).JSON(helper.JsonError(err))
+		return err
 	}
 
 	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(id))
 }
+
+func (a *TodoApi) GetSubtree(c *fiber.Ctx) error {
+	res, err := a.todoSvc.GetSubtree(c.UserContext(), c.Params("id"))
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoApi) GetTags(c *fiber.Ctx) error {
+	res, err := a.todoSvc.GetTags(c.UserContext())
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoApi) AddTags(c *fiber.Ctx) error {
+	payload := domain.TodoTagsDto{}
+
+	id := c.Params("id")
+
+	if err := c.BodyParser(&payload); err != nil {
+		logger.Error(err)
+		return domain.NewValidationError(err.Error(), err)
+	}
+
+	if err := helper.Validate(&payload); err != nil {
+		return err
+	}
+
+	res, err := a.todoSvc.AddTags(c.UserContext(), id, payload.Tags)
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoApi) RemoveTag(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	tag, err := url.PathUnescape(c.Params("tag"))
+
+	if err != nil {
+		return domain.NewValidationError(err.Error(), err)
+	}
+
+	res, err := a.todoSvc.RemoveTags(c.UserContext(), id, []string{tag})
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoApi) GetTrash(c *fiber.Ctx) error {
+	skip := c.QueryInt("skip", 0)
+	limit := c.QueryInt("limit", 10)
+
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	res, total, err := a.todoSvc.GetTrash(c.UserContext(), int64(skip), int64(limit))
+
+	if err != nil {
+		return err
+	}
+
+	links := helper.PageLinks(c.Path(), query, int64(skip), int64(limit), total)
+
+	c.Links(links.ToLinks()...)
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.PageModel{
+		Items:   res,
+		Skip:    int64(skip),
+		Limit:   int64(limit),
+		Total:   total,
+		HasMore: int64(skip+len(res)) < total,
+		Links:   links,
+	}))
+}
+
+func (a *TodoApi) Restore(c *fiber.Ctx) error {
+	res, err := a.todoSvc.Restore(c.UserContext(), c.Params("id"))
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoApi) Purge(c *fiber.Ctx) error {
+	res, err := a.todoSvc.Purge(c.UserContext())
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.M{
+		"purged": res,
+	}))
+}
+
+func (a *TodoApi) GetHistory(c *fiber.Ctx) error {
+	skip := c.QueryInt("skip", 0)
+	limit := c.QueryInt("limit", 10)
+
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	res, total, err := a.todoSvc.GetHistory(c.UserContext(), c.Params("id"), int64(skip), int64(limit))
+
+	if err != nil {
+		return err
+	}
+
+	links := helper.PageLinks(c.Path(), query, int64(skip), int64(limit), total)
+
+	c.Links(links.ToLinks()...)
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.PageModel{
+		Items:   res,
+		Skip:    int64(skip),
+		Limit:   int64(limit),
+		Total:   total,
+		HasMore: int64(skip+len(res)) < total,
+		Links:   links,
+	}))
+}
+
+func (a *TodoApi) Revert(c *fiber.Ctx) error {
+	res, err := a.todoSvc.Revert(c.UserContext(), c.Params("id"), c.Params("revisionId"))
+
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, helper.ETag(res.Version))
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
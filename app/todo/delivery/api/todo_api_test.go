@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -15,6 +16,7 @@ import (
 	"github.com/ariefsn/go-resik/domain"
 	"github.com/ariefsn/go-resik/domain/mocks"
 	"github.com/ariefsn/go-resik/helper"
+	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -159,9 +161,10 @@ func TestCreate(t *testing.T) {
 
 func TestGet(t *testing.T) {
 	cases := []struct {
-		name    string
-		success bool
-		filter  common.M
+		name     string
+		success  bool
+		filter   common.M
+		expected *domain.Filter
 	}{
 		{
 			name:    "Success",
@@ -170,11 +173,16 @@ func TestGet(t *testing.T) {
 				"title":       "Title 1",
 				"description": "Description 1",
 			},
+			expected: domain.And(
+				domain.Where("title", domain.FoContains, "Title 1"),
+				domain.Where("description", domain.FoContains, "Description 1"),
+			),
 		},
 		{
-			name:    "Failed",
-			success: false,
-			filter:  common.M{},
+			name:     "Failed",
+			success:  false,
+			filter:   common.M{},
+			expected: domain.And(),
 		},
 	}
 
@@ -182,9 +190,9 @@ func TestGet(t *testing.T) {
 
 	for _, c := range cases {
 		if c.success {
-			svc.On("Get", MOCK_CTX, c.filter, int64(0), int64(10)).Return([]domain.Todo{MOCK_DATA_SINGLE}, int64(1), nil).Once()
+			svc.On("Get", MOCK_CTX, c.expected, []domain.Sort{}, int64(0), int64(10)).Return([]domain.Todo{MOCK_DATA_SINGLE}, int64(1), nil).Once()
 		} else {
-			svc.On("Get", MOCK_CTX, c.filter, int64(0), int64(10)).Return([]domain.Todo{}, int64(0), errors.New("some error")).Once()
+			svc.On("Get", MOCK_CTX, c.expected, []domain.Sort{}, int64(0), int64(10)).Return([]domain.Todo{}, int64(0), errors.New("some error")).Once()
 		}
 
 		params := url.Values{}
@@ -205,9 +213,19 @@ func TestGet(t *testing.T) {
 		if c.success {
 			assert.True(t, result.Status)
 			assert.Equal(t, "", result.Message)
+			self := "/?" + params.Encode()
+
 			assert.EqualValues(t, map[string]interface{}{
-				"items": []interface{}{MOCK_DATA_SINGLE_M},
-				"total": float64(1),
+				"items":   []interface{}{MOCK_DATA_SINGLE_M},
+				"skip":    float64(0),
+				"limit":   float64(10),
+				"total":   float64(1),
+				"hasMore": false,
+				"links": map[string]interface{}{
+					"self":  self,
+					"first": self,
+					"last":  self,
+				},
 			}, result.Data)
 		} else {
 			assert.False(t, result.Status)
@@ -304,12 +322,12 @@ func TestUpdate(t *testing.T) {
 
 	for _, c := range cases {
 		if c.success {
-			svc.On("Update", MOCK_CTX, "1", &MOCK_DTO_UPDATE).Return(&MOCK_DATA_SINGLE, nil).Once()
+			svc.On("Update", MOCK_CTX, "1", &MOCK_DTO_UPDATE, int64(0)).Return(&MOCK_DATA_SINGLE, nil).Once()
 		} else {
 			if c.payload == nil {
-				svc.On("Update", MOCK_CTX, "1", nil).Return(nil, errors.New("unexpected end of JSON input")).Once()
+				svc.On("Update", MOCK_CTX, "1", nil, int64(0)).Return(nil, errors.New("unexpected end of JSON input")).Once()
 			} else {
-				svc.On("Update", MOCK_CTX, "1", &MOCK_DTO_UPDATE).Return(nil, errors.New("some error")).Once()
+				svc.On("Update", MOCK_CTX, "1", &MOCK_DTO_UPDATE, int64(0)).Return(nil, errors.New("some error")).Once()
 			}
 		}
 
@@ -378,12 +396,12 @@ func TestUpdateStatus(t *testing.T) {
 
 	for _, c := range cases {
 		if c.success {
-			svc.On("UpdateStatus", MOCK_CTX, "1", true).Return(&MOCK_DATA_SINGLE, nil).Once()
+			svc.On("Complete", MOCK_CTX, "1", false, int64(0)).Return(&domain.TodoCompletion{Todo: MOCK_DATA_SINGLE}, nil).Once()
 		} else {
 			if c.payload == nil {
-				svc.On("UpdateStatus", MOCK_CTX, "1", nil).Return(nil, errors.New("unexpected end of JSON input")).Once()
+				svc.On("Complete", MOCK_CTX, "1", nil, int64(0)).Return(nil, errors.New("unexpected end of JSON input")).Once()
 			} else {
-				svc.On("UpdateStatus", MOCK_CTX, "1", true).Return(nil, errors.New("some error")).Once()
+				svc.On("Complete", MOCK_CTX, "1", false, int64(0)).Return(nil, errors.New("some error")).Once()
 			}
 		}
 
@@ -415,6 +433,45 @@ func TestUpdateStatus(t *testing.T) {
 			assert.Equal(t, msg, result.Message)
 		}
 	}
+
+	t.Run("Success - Reopen", func(t *testing.T) {
+		svc.On("UpdateStatus", MOCK_CTX, "1", false, int64(0)).Return(&MOCK_DATA_SINGLE, nil).Once()
+
+		body, _ := helper.ToJsonBody(common.M{"isCompleted": false})
+
+		req := httptest.NewRequest(http.MethodPatch, "/1", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, MOCK_DATA_SINGLE_M, result.Data)
+	})
+
+	t.Run("Success - Cascade", func(t *testing.T) {
+		svc.On("Complete", MOCK_CTX, "1", true, int64(0)).Return(&domain.TodoCompletion{
+			Todo:              MOCK_DATA_SINGLE_STATUS_UPDATED,
+			CompletedSubtasks: []string{"2", "3"},
+		}, nil).Once()
+
+		body, _ := helper.ToJsonBody(common.M{"isCompleted": true, "cascade": true})
+
+		req := httptest.NewRequest(http.MethodPatch, "/1", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		data := result.Data.(map[string]interface{})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, true, data["isCompleted"])
+		assert.Equal(t, []interface{}{"2", "3"}, data["completedSubtasks"])
+		assert.Nil(t, data["incompleteSubtasks"])
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -439,9 +496,9 @@ func TestDelete(t *testing.T) {
 
 	for _, c := range cases {
 		if c.success {
-			svc.On("Delete", MOCK_CTX, "1").Return(nil).Once()
+			svc.On("Delete", MOCK_CTX, "1", int64(0)).Return(nil).Once()
 		} else {
-			svc.On("Delete", MOCK_CTX, "1").Return(errors.New("some error")).Once()
+			svc.On("Delete", MOCK_CTX, "1", int64(0)).Return(errors.New("some error")).Once()
 		}
 
 		req := httptest.NewRequest(http.MethodDelete, "/"+c.id, nil)
@@ -463,3 +520,922 @@ func TestDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestErrorStatus(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	t.Run("Not Found", func(t *testing.T) {
+		svc.On("GetByID", MOCK_CTX, "2").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/2", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.False(t, result.Status)
+		assert.Equal(t, "todo not found", result.Message)
+	})
+
+	t.Run("Invalid Payload", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString("{"))
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.False(t, result.Status)
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		svc.On("Delete", MOCK_CTX, "2", int64(0)).Return(domain.NewConflictError("todo is locked")).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/2", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+		assert.Equal(t, "todo is locked", result.Message)
+	})
+
+	t.Run("Internal Server Error", func(t *testing.T) {
+		svc.On("Delete", MOCK_CTX, "3", int64(0)).Return(errors.New("some error")).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/3", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("Route Not Found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/1/unknown", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.False(t, result.Status)
+	})
+}
+
+func TestValidation(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	cases := []struct {
+		name   string
+		method string
+		target string
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			target: "/",
+		},
+		{
+			name:   "Update",
+			method: http.MethodPut,
+			target: "/1",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			body, _ := helper.ToJsonBody(domain.TodoDto{
+				Title:       "",
+				Description: "Description 1",
+			})
+
+			req := httptest.NewRequest(c.method, c.target, body)
+			req.Header.Set("Content-Type", "application/json")
+
+			res, _ := app.Test(req)
+
+			result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			assert.False(t, result.Status)
+			assert.Equal(t, "validation failed: title (required)", result.Message)
+			assert.Equal(t, "VALIDATION", result.Code)
+			assert.Equal(t, []common.ResponseError{
+				{Field: "title", Code: "required", Message: "title is required"},
+			}, result.Errors)
+		})
+	}
+
+	t.Run("Priority", func(t *testing.T) {
+		body, _ := helper.ToJsonBody(domain.TodoDto{
+			Title:       "Title 1",
+			Description: "Description 1",
+			Priority:    "asap",
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, []common.ResponseError{
+			{Field: "priority", Code: "oneof", Message: "priority must be one of [low, medium, high, urgent]"},
+		}, result.Errors)
+	})
+}
+
+func TestProblemDetails(t *testing.T) {
+	helper.Env().App.ErrorFormat = helper.ErrorFormatProblem
+	defer func() {
+		helper.Env().App.ErrorFormat = helper.ErrorFormatEnvelope
+	}()
+
+	app := api.NewTodoApi(svc)
+
+	svc.On("GetByID", MOCK_CTX, "2").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/2", nil)
+
+	res, _ := app.Test(req)
+
+	result, _ := helper.FromResponseBody[common.ProblemModel](res.Body)
+
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	assert.Equal(t, helper.ContentTypeProblemJson, res.Header.Get("Content-Type"))
+	assert.Equal(t, common.ProblemModel{
+		Type:     "about:blank",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Detail:   "todo not found",
+		Instance: "/2",
+		Code:     "NOT_FOUND",
+	}, result)
+}
+
+func TestGetSort(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	t.Run("Success", func(t *testing.T) {
+		sort := []domain.Sort{
+			{Field: "isCompleted", Order: domain.SortAsc},
+			{Field: "createdAt", Order: domain.SortDesc},
+		}
+
+		svc.On("Get", MOCK_CTX, domain.And(), sort, int64(0), int64(10)).Return([]domain.Todo{MOCK_DATA_SINGLE}, int64(1), nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/?sort="+url.QueryEscape("isCompleted,createdAt*desc"), nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Failed - Unknown Field", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?sort=password", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "sort", result.Errors[0].Field)
+	})
+}
+
+func TestGetFilter(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	t.Run("Success", func(t *testing.T) {
+		filter := domain.And(
+			domain.Where("createdAt", domain.FoGte, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)),
+			domain.Where("isCompleted", domain.FoEq, true),
+		)
+
+		svc.On("Get", MOCK_CTX, filter, []domain.Sort{}, int64(0), int64(10)).Return([]domain.Todo{MOCK_DATA_SINGLE}, int64(1), nil).Once()
+
+		params := url.Values{}
+		params.Add("filter[createdAt][$gte]", "2023-10-01")
+		params.Add("filter[isCompleted][$eq]", "true")
+
+		req := httptest.NewRequest(http.MethodGet, "/?"+params.Encode(), nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	cases := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{name: "Failed - Unknown Field", key: "filter[password][$eq]", value: "secret"},
+		{name: "Failed - Unknown Operator", key: "filter[title][$where]", value: "true"},
+		{name: "Failed - Invalid Regex", key: "filter[title][$regex]", value: "(a+"},
+		{name: "Failed - Nested Regex", key: "filter[title][$regex]", value: "(a+)+$"},
+		{name: "Failed - Unsupported Operator", key: "filter[isCompleted][$contains]", value: "true"},
+		{name: "Failed - Invalid Value", key: "filter[createdAt][$gte]", value: "yesterday"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			params := url.Values{}
+			params.Add(c.key, c.value)
+
+			req := httptest.NewRequest(http.MethodGet, "/?"+params.Encode(), nil)
+
+			res, _ := app.Test(req)
+
+			result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			assert.Equal(t, c.key, result.Errors[0].Field)
+		})
+	}
+}
+
+func TestGetCursor(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	sort := []domain.Sort{{Field: "title", Order: domain.SortDesc}}
+	next := domain.NewTodoCursor(MOCK_DATA_SINGLE, sort, false)
+
+	t.Run("Success", func(t *testing.T) {
+		svc.On("GetByCursor", MOCK_CTX, domain.And(), sort, (*domain.Cursor)(nil), int64(1)).Return(&domain.TodoPage{
+			Items: []domain.Todo{MOCK_DATA_SINGLE},
+			Next:  next,
+		}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/?cursor=&limit=1&sort="+url.QueryEscape("title*desc"), nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, map[string]interface{}{
+			"items":      []interface{}{MOCK_DATA_SINGLE_M},
+			"nextCursor": helper.EncodeCursor(next, sort),
+			"prevCursor": "",
+			"links": map[string]interface{}{
+				"self":  "/?cursor=&limit=1&sort=title%2Adesc",
+				"first": "/?cursor=&limit=1&sort=title%2Adesc",
+				"next":  "/?cursor=" + helper.EncodeCursor(next, sort) + "&limit=1&sort=title%2Adesc",
+			},
+		}, result.Data)
+		assert.Equal(t, `</?cursor=&limit=1&sort=title%2Adesc>; rel="self",</?cursor=&limit=1&sort=title%2Adesc>; rel="first",</?cursor=`+helper.EncodeCursor(next, sort)+`&limit=1&sort=title%2Adesc>; rel="next"`, res.Header.Get("Link"))
+	})
+
+	t.Run("Success - Next Page", func(t *testing.T) {
+		svc.On("GetByCursor", MOCK_CTX, domain.And(), sort, next, int64(1)).Return(&domain.TodoPage{
+			Items: []domain.Todo{},
+		}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/?limit=1&sort="+url.QueryEscape("title*desc")+"&cursor="+helper.EncodeCursor(next, sort), nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Failed - Invalid Cursor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/?cursor="+helper.EncodeCursor(next, sort), nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "cursor", result.Errors[0].Field)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		svc.On("GetByCursor", MOCK_CTX, domain.And(), []domain.Sort{}, (*domain.Cursor)(nil), int64(10)).Return(nil, errors.New("some error")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/?cursor=", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+}
+
+func TestGetPage(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	filter := domain.And(domain.Where("title", domain.FoContains, "Title"))
+
+	svc.On("Get", MOCK_CTX, filter, []domain.Sort{}, int64(4), int64(2)).Return([]domain.Todo{MOCK_DATA_SINGLE, MOCK_DATA_SINGLE}, int64(7), nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/?title=Title&skip=4&limit=2", nil)
+
+	res, _ := app.Test(req)
+
+	result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+	data := result.Data.(map[string]interface{})
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, true, data["hasMore"])
+	assert.EqualValues(t, map[string]interface{}{
+		"self":  "/?limit=2&skip=4&title=Title",
+		"first": "/?limit=2&skip=0&title=Title",
+		"prev":  "/?limit=2&skip=2&title=Title",
+		"next":  "/?limit=2&skip=6&title=Title",
+		"last":  "/?limit=2&skip=6&title=Title",
+	}, data["links"])
+	assert.Equal(t, `</?limit=2&skip=4&title=Title>; rel="self",</?limit=2&skip=0&title=Title>; rel="first",</?limit=2&skip=2&title=Title>; rel="prev",</?limit=2&skip=6&title=Title>; rel="next",</?limit=2&skip=6&title=Title>; rel="last"`, res.Header.Get("Link"))
+}
+
+func TestFields(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	t.Run("Success - Get", func(t *testing.T) {
+		svc.On("Get", MOCK_CTX, domain.And(), []domain.Sort{}, int64(0), int64(10), "title", "isCompleted").Return([]domain.Todo{MOCK_DATA_SINGLE}, int64(1), nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/?fields=title,isCompleted", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []interface{}{map[string]interface{}{
+			"id":          MOCK_DATA_SINGLE.ID,
+			"title":       MOCK_DATA_SINGLE.Title,
+			"isCompleted": MOCK_DATA_SINGLE.IsCompleted,
+		}}, result.Data.(map[string]interface{})["items"])
+	})
+
+	t.Run("Success - GetByID", func(t *testing.T) {
+		svc.On("GetByID", MOCK_CTX, "1", "description", "version").Return(&MOCK_DATA_SINGLE, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/1?fields=description", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, helper.WeakETag(MOCK_DATA_SINGLE.Version), res.Header.Get(fiber.HeaderETag))
+		assert.Equal(t, map[string]interface{}{
+			"id":          MOCK_DATA_SINGLE.ID,
+			"description": MOCK_DATA_SINGLE.Description,
+		}, result.Data)
+	})
+
+	t.Run("Failed - Unknown Field", func(t *testing.T) {
+		for _, path := range []string{"/?fields=title,password", "/1?fields=password"} {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+
+			res, _ := app.Test(req)
+
+			result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			assert.Equal(t, "fields", result.Errors[0].Field)
+		}
+	})
+}
+
+func TestSearch(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	t.Run("Success", func(t *testing.T) {
+		svc.On("Search", MOCK_CTX, "title 1", int64(0), int64(10)).Return([]domain.TodoSearchResult{
+			{
+				Todo:       MOCK_DATA_SINGLE,
+				Score:      2,
+				Highlights: map[string]string{"title": "<mark>Title</mark> <mark>1</mark>"},
+			},
+		}, int64(1), nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/search?q=title+1", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		data := result.Data.(map[string]interface{})
+		items := data["items"].([]interface{})
+		item := items[0].(map[string]interface{})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 1, data["total"])
+		assert.Equal(t, MOCK_DATA_SINGLE.ID, item["id"])
+		assert.Equal(t, MOCK_DATA_SINGLE.Title, item["title"])
+		assert.EqualValues(t, 2, item["score"])
+		assert.Equal(t, map[string]interface{}{"title": "<mark>Title</mark> <mark>1</mark>"}, item["highlights"])
+	})
+
+	t.Run("Failed - Empty Query", func(t *testing.T) {
+		svc.On("Search", MOCK_CTX, "", int64(0), int64(10)).Return(nil, int64(0), domain.NewFieldValidationError(domain.FieldError{
+			Field:   "q",
+			Rule:    "required",
+			Message: "q is required",
+		})).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/search", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, []common.ResponseError{
+			{Field: "q", Code: "required", Message: "q is required"},
+		}, result.Errors)
+	})
+}
+
+func TestGetOverdue(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	due := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+	overdue := MOCK_DATA_SINGLE
+	overdue.Priority = domain.PriorityUrgent
+	overdue.DueAt = &due
+
+	t.Run("Success", func(t *testing.T) {
+		sort := []domain.Sort{{Field: "priority", Order: domain.SortDesc}}
+
+		svc.On("GetOverdue", MOCK_CTX, sort, int64(0), int64(10), "priority", "dueAt").Return([]domain.Todo{overdue}, int64(1), nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/overdue?sort=priority*desc&fields=priority,dueAt", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		data := result.Data.(map[string]interface{})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 1, data["total"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"id": "1", "priority": "urgent", "dueAt": "2024-01-31T08:00:00Z"},
+		}, data["items"])
+	})
+
+	t.Run("Failed - Unknown Sort", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/overdue?sort=description", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		svc.On("GetOverdue", MOCK_CTX, []domain.Sort{}, int64(0), int64(10)).Return(nil, int64(0), errors.New("some error")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/overdue", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+}
+
+func TestGetSubtree(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	subtask := MOCK_DATA_LIST[1]
+	subtask.ParentID = "1"
+
+	t.Run("Success", func(t *testing.T) {
+		svc.On("GetSubtree", MOCK_CTX, "1").Return(&domain.TodoNode{
+			Todo: MOCK_DATA_LIST[0],
+			Subtasks: []domain.TodoNode{
+				{Todo: subtask, Depth: 1, Subtasks: []domain.TodoNode{}},
+			},
+		}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/1/subtree", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		data := result.Data.(map[string]interface{})
+		subtasks := data["subtasks"].([]interface{})
+		child := subtasks[0].(map[string]interface{})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "1", data["id"])
+		assert.EqualValues(t, 0, data["depth"])
+		assert.Equal(t, "2", child["id"])
+		assert.Equal(t, "1", child["parentId"])
+		assert.EqualValues(t, 1, child["depth"])
+		assert.Equal(t, []interface{}{}, child["subtasks"])
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		svc.On("GetSubtree", MOCK_CTX, "2").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/2/subtree", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestTags(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	tagged := MOCK_DATA_SINGLE
+	tagged.Tags = []string{"home", "work"}
+
+	t.Run("Success - Counts", func(t *testing.T) {
+		svc.On("GetTags", MOCK_CTX).Return([]domain.TagCount{{Tag: "work", Count: 2}, {Tag: "home", Count: 1}}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/tags", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, []interface{}{
+			map[string]interface{}{"tag": "work", "count": float64(2)},
+			map[string]interface{}{"tag": "home", "count": float64(1)},
+		}, result.Data)
+	})
+
+	t.Run("Success - Add", func(t *testing.T) {
+		svc.On("AddTags", MOCK_CTX, "1", []string{"Home", "work"}).Return(&tagged, nil).Once()
+
+		body, _ := helper.ToJsonBody(domain.TodoTagsDto{Tags: []string{"Home", "work"}})
+
+		req := httptest.NewRequest(http.MethodPost, "/1/tags", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []interface{}{"home", "work"}, result.Data.(map[string]interface{})["tags"])
+	})
+
+	t.Run("Failed - Add Without Tags", func(t *testing.T) {
+		body, _ := helper.ToJsonBody(domain.TodoTagsDto{Tags: []string{}})
+
+		req := httptest.NewRequest(http.MethodPost, "/1/tags", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "tags", result.Errors[0].Field)
+	})
+
+	t.Run("Success - Remove", func(t *testing.T) {
+		svc.On("RemoveTags", MOCK_CTX, "1", []string{"to do"}).Return(&MOCK_DATA_SINGLE, nil).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/1/tags/to%20do", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Failed - Remove", func(t *testing.T) {
+		svc.On("RemoveTags", MOCK_CTX, "2", []string{"home"}).Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/2/tags/home", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	deletedAt := time.Date(2024, 1, 31, 8, 0, 0, 0, time.UTC)
+	trashed := MOCK_DATA_SINGLE
+	trashed.Audit = &domain.Audit{DeletedAt: &deletedAt}
+
+	t.Run("Success - Get", func(t *testing.T) {
+		svc.On("GetTrash", MOCK_CTX, int64(0), int64(1)).Return([]domain.Todo{trashed}, int64(2), nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/trash?limit=1", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		data := result.Data.(map[string]interface{})
+		items := data["items"].([]interface{})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 2, data["total"])
+		assert.Equal(t, true, data["hasMore"])
+		assert.Equal(t, "2024-01-31T08:00:00Z", items[0].(map[string]interface{})["deletedAt"])
+	})
+
+	t.Run("Failed - Get", func(t *testing.T) {
+		svc.On("GetTrash", MOCK_CTX, int64(0), int64(10)).Return(nil, int64(0), errors.New("some error")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/trash", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("Success - Restore", func(t *testing.T) {
+		svc.On("Restore", MOCK_CTX, "1").Return(&MOCK_DATA_SINGLE, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/1/restore", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Failed - Restore Not In Trash", func(t *testing.T) {
+		svc.On("Restore", MOCK_CTX, "2").Return(nil, domain.NewNotFoundError("todo not found in trash")).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/2/restore", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+
+	t.Run("Failed - Restore Parent Deleted", func(t *testing.T) {
+		svc.On("Restore", MOCK_CTX, "3").Return(nil, domain.NewConflictError("parent todo is deleted, restore it first")).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/3/restore", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("Success - Purge", func(t *testing.T) {
+		svc.On("Purge", MOCK_CTX).Return(int64(2), nil).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/trash", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, map[string]interface{}{"purged": float64(2)}, result.Data)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	revisions := []domain.TodoRevision{
+		{
+			ID:      "11",
+			TodoID:  "1",
+			Action:  domain.RevisionUpdate,
+			Actor:   "alice",
+			State:   domain.TodoState{Title: "Title 1 - Updated", Tags: []string{}, Priority: domain.PriorityHigh},
+			Changes: []domain.FieldChange{{Field: "title", From: "Title 1", To: "Title 1 - Updated"}},
+		},
+	}
+
+	t.Run("Success - Get", func(t *testing.T) {
+		svc.On("GetHistory", MOCK_CTX, "1", int64(0), int64(1)).Return(revisions, int64(2), nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/1/history?limit=1", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		data := result.Data.(map[string]interface{})
+		item := data["items"].([]interface{})[0].(map[string]interface{})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.EqualValues(t, 2, data["total"])
+		assert.Equal(t, true, data["hasMore"])
+		assert.Equal(t, "alice", item["actor"])
+		assert.Equal(t, "high", item["state"].(map[string]interface{})["priority"])
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"field": "title", "from": "Title 1", "to": "Title 1 - Updated"},
+		}, item["changes"])
+		assert.NotContains(t, item, "before")
+	})
+
+	t.Run("Failed - Get", func(t *testing.T) {
+		svc.On("GetHistory", MOCK_CTX, "1", int64(0), int64(10)).Return(nil, int64(0), errors.New("some error")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/1/history", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	})
+
+	t.Run("Success - Revert", func(t *testing.T) {
+		svc.On("Revert", MOCK_CTX, "1", "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/1/history/10/revert", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Failed - Revert Unknown Revision", func(t *testing.T) {
+		svc.On("Revert", MOCK_CTX, "1", "99").Return(nil, domain.NewNotFoundError("todo revision not found")).Once()
+
+		req := httptest.NewRequest(http.MethodPost, "/1/history/99/revert", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestVersion(t *testing.T) {
+	app := api.NewTodoApi(svc)
+
+	versioned := MOCK_DATA_SINGLE
+	versioned.Version = 3
+
+	t.Run("Success - ETag", func(t *testing.T) {
+		svc.On("GetByID", MOCK_CTX, "1").Return(&versioned, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/1", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `"3"`, res.Header.Get(fiber.HeaderETag))
+	})
+
+	t.Run("Success - Not Modified", func(t *testing.T) {
+		svc.On("GetByID", MOCK_CTX, "1").Return(&versioned, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/1", nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, `W/"3"`)
+
+		res, _ := app.Test(req)
+		body, _ := io.ReadAll(res.Body)
+
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		assert.Equal(t, `"3"`, res.Header.Get(fiber.HeaderETag))
+		assert.Empty(t, body)
+	})
+
+	t.Run("Success - Modified", func(t *testing.T) {
+		svc.On("GetByID", MOCK_CTX, "1").Return(&versioned, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/1", nil)
+		req.Header.Set(fiber.HeaderIfNoneMatch, `"2"`)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Success - If-Match", func(t *testing.T) {
+		updated := versioned
+		updated.Version = 4
+
+		svc.On("Update", MOCK_CTX, "1", &MOCK_DTO_UPDATE, int64(3)).Return(&updated, nil).Once()
+
+		body, _ := helper.ToJsonBody(MOCK_DTO_UPDATE)
+
+		req := httptest.NewRequest(http.MethodPut, "/1", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(fiber.HeaderIfMatch, `"3"`)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `"4"`, res.Header.Get(fiber.HeaderETag))
+	})
+
+	t.Run("Failed - Stale", func(t *testing.T) {
+		svc.On("Delete", MOCK_CTX, "1", int64(2)).Return(domain.NewPreconditionError("todo version doesn't match")).Once()
+
+		req := httptest.NewRequest(http.MethodDelete, "/1", nil)
+		req.Header.Set(fiber.HeaderIfMatch, `"2"`)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+	})
+
+	t.Run("Failed - Weak If-Match", func(t *testing.T) {
+		body, _ := helper.ToJsonBody(domain.TodoStatusDto{IsCompleted: false})
+
+		req := httptest.NewRequest(http.MethodPatch, "/1", body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(fiber.HeaderIfMatch, `W/"3"`)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusPreconditionFailed, res.StatusCode)
+	})
+
+	t.Run("Failed - If-Match List", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodDelete, "/1", nil)
+		req.Header.Set(fiber.HeaderIfMatch, `"2", "3"`)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
+
+func TestReplace(t *testing.T) {
+	app := api.NewTodoApi(svc)
+	id := "65a0c0ffee0000000000000a"
+
+	created := MOCK_DATA_SINGLE
+	created.ID = id
+	created.Version = 1
+
+	replace := func(id string, headers map[string]string) *http.Response {
+		body, _ := helper.ToJsonBody(MOCK_DTO_UPDATE)
+
+		req := httptest.NewRequest(http.MethodPut, "/"+id, body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(fiber.HeaderIfNoneMatch, "*")
+
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		res, _ := app.Test(req)
+
+		return res
+	}
+
+	t.Run("Success - Created", func(t *testing.T) {
+		svc.On("Replace", MOCK_CTX, id, &MOCK_DTO_UPDATE).Return(&created, true, nil).Once()
+
+		res := replace(id, nil)
+
+		assert.Equal(t, http.StatusCreated, res.StatusCode)
+		assert.Equal(t, `"1"`, res.Header.Get(fiber.HeaderETag))
+	})
+
+	t.Run("Success - Replaced", func(t *testing.T) {
+		replaced := created
+		replaced.Version = 2
+
+		svc.On("Replace", MOCK_CTX, id, &MOCK_DTO_UPDATE).Return(&replaced, false, nil).Once()
+
+		res := replace(id, nil)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, `"2"`, res.Header.Get(fiber.HeaderETag))
+	})
+
+	t.Run("Failed - Trashed", func(t *testing.T) {
+		svc.On("Replace", MOCK_CTX, id, &MOCK_DTO_UPDATE).Return(nil, false, domain.NewConflictError("todo is in the trash, restore it first")).Once()
+
+		res := replace(id, nil)
+
+		assert.Equal(t, http.StatusConflict, res.StatusCode)
+	})
+
+	t.Run("Failed - Invalid ID", func(t *testing.T) {
+		res := replace("not-an-id", nil)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, "validation failed: id (mongodb)", result.Message)
+	})
+
+	t.Run("Failed - With If-Match", func(t *testing.T) {
+		res := replace(id, map[string]string{fiber.HeaderIfMatch: `"1"`})
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+
+	t.Run("Failed - Update Unknown", func(t *testing.T) {
+		svc.On("Update", MOCK_CTX, "2", &MOCK_DTO_UPDATE, int64(0)).Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+		body, _ := helper.ToJsonBody(MOCK_DTO_UPDATE)
+
+		req := httptest.NewRequest(http.MethodPut, "/2", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
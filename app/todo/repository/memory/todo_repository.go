@@ -0,0 +1,817 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNotFound = domain.NewNotFoundError("todo not found")
+
+var errVersion = domain.NewPreconditionError("todo version doesn't match")
+
+var errTrashed = domain.NewConflictError("todo is in the trash, restore it first")
+
+type memoryTodoRepository struct {
+	mu    sync.RWMutex
+	items []domain.Todo
+	trash []domain.Todo
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	v := *t
+
+	return &v
+}
+
+func clone(t domain.Todo) domain.Todo {
+	t.Tags = slices.Clone(t.Tags)
+	t.DueAt = cloneTime(t.DueAt)
+
+	if t.Audit != nil {
+		audit := *t.Audit
+		audit.DeletedAt = cloneTime(audit.DeletedAt)
+		t.Audit = &audit
+	}
+
+	return t
+}
+
+// compareValues compares the values of a field, a null value is less than any other like in the databases
+func compareValues(a, b interface{}) int {
+	if a == nil || b == nil {
+		return cmp.Compare(boolRank(a != nil), boolRank(b != nil))
+	}
+
+	switch x := a.(type) {
+	case string:
+		y, _ := b.(string)
+		return strings.Compare(x, y)
+	case bool:
+		y, _ := b.(bool)
+		if x == y {
+			return 0
+		}
+		if !x {
+			return -1
+		}
+		return 1
+	case time.Time:
+		y, _ := b.(time.Time)
+		return x.Compare(y)
+	case domain.Priority:
+		y, _ := b.(domain.Priority)
+		return cmp.Compare(x, y)
+	}
+
+	return 0
+}
+
+func boolRank(v bool) int {
+	if v {
+		return 1
+	}
+
+	return 0
+}
+
+// project copies the projected fields of the todo, every field without a projection
+func project(t domain.Todo, fields []string) domain.Todo {
+	if len(fields) == 0 {
+		return clone(t)
+	}
+
+	result := domain.Todo{}
+
+	for _, v := range fields {
+		switch v {
+		case "id":
+			result.ID = t.ID
+		case "title":
+			result.Title = t.Title
+		case "description":
+			result.Description = t.Description
+		case "isCompleted":
+			result.IsCompleted = t.IsCompleted
+		case "tags":
+			result.Tags = slices.Clone(t.Tags)
+		case "priority":
+			result.Priority = t.Priority
+		case "dueAt":
+			result.DueAt = cloneTime(t.DueAt)
+		case "parentId":
+			result.ParentID = t.ParentID
+		case "listId":
+			result.ListID = t.ListID
+		case "version":
+			result.Version = t.Version
+		case "createdAt", "updatedAt":
+			if t.Audit == nil {
+				continue
+			}
+
+			if result.Audit == nil {
+				result.Audit = &domain.Audit{}
+			}
+
+			if v == "createdAt" {
+				result.CreatedAt = t.CreatedAt
+			} else {
+				result.UpdatedAt = t.UpdatedAt
+			}
+		}
+	}
+
+	return result
+}
+
+// matchTags evaluates a filter condition against the tags, the todo matches when any of its tags does
+func matchTags(tags []string, condition *domain.Filter) bool {
+	values := []interface{}{condition.Value}
+	if condition.Operator.IsList() {
+		values, _ = condition.Value.([]interface{})
+	}
+
+	found := 0
+
+	for _, v := range values {
+		if slices.Contains(tags, fmt.Sprint(v)) {
+			found++
+		}
+	}
+
+	switch condition.Operator {
+	case domain.FoEq, domain.FoIn:
+		return found > 0
+	case domain.FoNe, domain.FoNin:
+		return found == 0
+	case domain.FoAll:
+		return found == len(values)
+	}
+
+	return true
+}
+
+// matchCondition evaluates a single filter condition against the todo
+func matchCondition(t domain.Todo, condition *domain.Filter) bool {
+	value := t.FieldValue(condition.Field)
+
+	// a null field only equals a null value and differs from the others
+	if value == nil || condition.Value == nil {
+		switch condition.Operator {
+		case domain.FoEq:
+			return value == condition.Value
+		case domain.FoNe:
+			return value != condition.Value
+		case domain.FoNin:
+			return true
+		}
+
+		return false
+	}
+
+	if tags, ok := value.([]string); ok {
+		return matchTags(tags, condition)
+	}
+
+	switch condition.Operator {
+	case domain.FoEq:
+		return compareValues(value, condition.Value) == 0
+	case domain.FoNe:
+		return compareValues(value, condition.Value) != 0
+	case domain.FoGt:
+		return compareValues(value, condition.Value) > 0
+	case domain.FoGte:
+		return compareValues(value, condition.Value) >= 0
+	case domain.FoLt:
+		return compareValues(value, condition.Value) < 0
+	case domain.FoLte:
+		return compareValues(value, condition.Value) <= 0
+	case domain.FoIn, domain.FoNin:
+		values, _ := condition.Value.([]interface{})
+		found := slices.ContainsFunc(values, func(v interface{}) bool {
+			return compareValues(value, v) == 0
+		})
+
+		return found == (condition.Operator == domain.FoIn)
+	case domain.FoRegex:
+		field, _ := value.(string)
+		matched, _ := regexp.MatchString("(?i)"+fmt.Sprint(condition.Value), field)
+
+		return matched
+	case domain.FoContains, domain.FoStartWith, domain.FoEndWith:
+		field, _ := value.(string)
+		field = strings.ToLower(field)
+		pattern := strings.ToLower(fmt.Sprint(condition.Value))
+
+		switch condition.Operator {
+		case domain.FoStartWith:
+			return strings.HasPrefix(field, pattern)
+		case domain.FoEndWith:
+			return strings.HasSuffix(field, pattern)
+		}
+
+		return strings.Contains(field, pattern)
+	}
+
+	return true
+}
+
+// matchFilter reports whether the todo satisfies the filter tree
+func matchFilter(t domain.Todo, filter *domain.Filter) bool {
+	if filter == nil {
+		return true
+	}
+
+	if filter.IsCondition() {
+		return matchCondition(t, filter)
+	}
+
+	for _, v := range filter.And {
+		if !matchFilter(t, v) {
+			return false
+		}
+	}
+
+	if len(filter.Or) > 0 && !slices.ContainsFunc(filter.Or, func(v *domain.Filter) bool { return matchFilter(t, v) }) {
+		return false
+	}
+
+	return filter.Not == nil || !matchFilter(t, filter.Not)
+}
+
+// sortTodos sorts the todos by the given keys, equal keys are ordered by id like the other repositories
+func sortTodos(data []domain.Todo, sort []domain.Sort) {
+	keys := append(append([]domain.Sort{}, sort...), domain.Sort{Field: "id", Order: domain.SortAsc})
+
+	slices.SortStableFunc(data, func(a, b domain.Todo) int {
+		for _, k := range keys {
+			res := compareValues(a.FieldValue(k.Field), b.FieldValue(k.Field))
+
+			if k.Order == domain.SortDesc {
+				res = -res
+			}
+
+			if res != 0 {
+				return res
+			}
+		}
+
+		return 0
+	})
+}
+
+// searchScore scores the todo by the words matching the terms, a title match weighs twice a description match
+func searchScore(t domain.Todo, terms []string) float64 {
+	score := 0.0
+
+	for _, v := range domain.SearchTerms(t.Title) {
+		if domain.MatchTerm(v, terms) {
+			score += 2
+		}
+	}
+
+	for _, v := range domain.SearchTerms(t.Description) {
+		if domain.MatchTerm(v, terms) {
+			score++
+		}
+	}
+
+	return score
+}
+
+func (r *memoryTodoRepository) indexOf(id string) int {
+	for i, v := range r.items {
+		if v.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// newTodo returns the todo of the payload as it's created with the id
+func newTodo(id string, payload *domain.TodoDto) domain.Todo {
+	return domain.Todo{
+		ID:          id,
+		Title:       payload.Title,
+		Description: payload.Description,
+		IsCompleted: false,
+		Tags:        payload.Tags,
+		Priority:    payload.PriorityValue(),
+		DueAt:       cloneTime(payload.DueAt),
+		ParentID:    payload.ParentID,
+		ListID:      payload.ListID,
+		Version:     1,
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	}
+}
+
+// Create implements domain.TodoRepository.
+func (r *memoryTodoRepository) Create(ctx context.Context, payload *domain.TodoDto) (*domain.Todo, error) {
+	data := newTodo(primitive.NewObjectID().Hex(), payload)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = append(r.items, clone(data))
+
+	return &data, nil
+}
+
+// indexOfVersion returns the index of the todo when it has the version, zero matches any version
+func (r *memoryTodoRepository) indexOfVersion(id string, version int64) (int, error) {
+	i := r.indexOf(id)
+	if i < 0 {
+		return i, errNotFound
+	}
+
+	if version > 0 && r.items[i].Version != version {
+		return i, errVersion
+	}
+
+	return i, nil
+}
+
+// trashAt moves the todo at the index to the trash, the lock must be held
+func (r *memoryTodoRepository) trashAt(i int, now time.Time) {
+	data := clone(r.items[i])
+
+	if data.Audit == nil {
+		data.Audit = &domain.Audit{}
+	}
+	data.DeletedAt = &now
+	data.Version++
+
+	r.trash = append(r.trash, data)
+	r.items = append(r.items[:i], r.items[i+1:]...)
+}
+
+// Delete implements domain.TodoRepository.
+func (r *memoryTodoRepository) Delete(ctx context.Context, id string, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.indexOfVersion(id, version)
+	if err != nil {
+		return err
+	}
+
+	r.trashAt(i, time.Now())
+
+	return nil
+}
+
+// Get implements domain.TodoRepository.
+func (r *memoryTodoRepository) Get(ctx context.Context, filter *domain.Filter, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	result := []domain.Todo{}
+
+	if err := filter.Validate(domain.TodoFilterFields); err != nil {
+		return result, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []domain.Todo{}
+
+	for _, v := range r.items {
+		if matchFilter(v, filter) {
+			matched = append(matched, v)
+		}
+	}
+
+	if len(sort) > 0 {
+		sortTodos(matched, sort)
+	}
+
+	count := int64(len(matched))
+
+	for i, v := range matched {
+		if int64(i) < skip {
+			continue
+		}
+
+		if limit > -1 && int64(len(result)) >= limit {
+			break
+		}
+
+		result = append(result, project(v, domain.ProjectFields(fields)))
+	}
+
+	return result, count, nil
+}
+
+// GetByCursor implements domain.TodoRepository.
+func (r *memoryTodoRepository) GetByCursor(ctx context.Context, filter *domain.Filter, sort []domain.Sort, cursor *domain.Cursor, limit int64, fields ...string) ([]domain.Todo, bool, error) {
+	result, _, err := r.Get(ctx, domain.And(filter, domain.KeysetFilter(sort, cursor, domain.TodoNullableFields...)), domain.KeysetSort(sort, cursor), 0, domain.KeysetLimit(limit), domain.ProjectFields(fields, domain.KeysetFields(sort)...)...)
+
+	if err != nil {
+		return result, false, err
+	}
+
+	result, hasMore := domain.TrimKeyset(result, cursor, limit)
+
+	return result, hasMore, nil
+}
+
+// GetByID implements domain.TodoRepository.
+func (r *memoryTodoRepository) GetByID(ctx context.Context, id string, fields ...string) (*domain.Todo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, errNotFound
+	}
+
+	result := project(r.items[i], domain.ProjectFields(fields))
+
+	return &result, nil
+}
+
+// Search implements domain.TodoRepository.
+func (r *memoryTodoRepository) Search(ctx context.Context, query string, skip int64, limit int64) ([]domain.TodoSearchResult, int64, error) {
+	terms := domain.SearchTerms(query)
+	result := []domain.TodoSearchResult{}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []domain.TodoSearchResult{}
+
+	for _, v := range r.items {
+		if score := searchScore(v, terms); score > 0 {
+			matched = append(matched, domain.TodoSearchResult{
+				Todo:  clone(v),
+				Score: score,
+			})
+		}
+	}
+
+	slices.SortStableFunc(matched, func(a, b domain.TodoSearchResult) int {
+		if res := cmp.Compare(b.Score, a.Score); res != 0 {
+			return res
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	count := int64(len(matched))
+
+	for i, v := range matched {
+		if int64(i) < skip {
+			continue
+		}
+
+		if limit > -1 && int64(len(result)) >= limit {
+			break
+		}
+
+		result = append(result, v)
+	}
+
+	return result, count, nil
+}
+
+func (r *memoryTodoRepository) update(id string, version int64, fn func(t *domain.Todo)) (*domain.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, err := r.indexOfVersion(id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.updateAt(i, fn), nil
+}
+
+// updateAt applies fn to the todo at the index along with its next version, the lock must be held
+func (r *memoryTodoRepository) updateAt(i int, fn func(t *domain.Todo)) *domain.Todo {
+	data := clone(r.items[i])
+	fn(&data)
+
+	if data.Audit == nil {
+		data.Audit = &domain.Audit{}
+	}
+	data.UpdatedAt = time.Now()
+	data.Version++
+
+	r.items[i] = data
+
+	result := clone(data)
+
+	return &result
+}
+
+// applyDto sets the fields of the payload to the todo, the tags are kept when the payload has none
+func applyDto(t *domain.Todo, payload *domain.TodoDto) {
+	t.Title = payload.Title
+	t.Description = payload.Description
+	t.Priority = payload.PriorityValue()
+	t.DueAt = cloneTime(payload.DueAt)
+	t.ParentID = payload.ParentID
+	t.ListID = payload.ListID
+
+	if payload.Tags != nil {
+		t.Tags = slices.Clone(payload.Tags)
+	}
+}
+
+// Update implements domain.TodoRepository.
+func (r *memoryTodoRepository) Update(ctx context.Context, id string, payload *domain.TodoDto, version int64) (*domain.Todo, error) {
+	return r.update(id, version, func(t *domain.Todo) {
+		applyDto(t, payload)
+	})
+}
+
+// insert adds the todo in its creation order like the databases keep them, the lock must be held
+func (r *memoryTodoRepository) insert(data domain.Todo) {
+	at := slices.IndexFunc(r.items, func(v domain.Todo) bool { return v.ID > data.ID })
+	if at < 0 {
+		at = len(r.items)
+	}
+
+	r.items = slices.Insert(r.items, at, data)
+}
+
+// Replace implements domain.TodoRepository.
+func (r *memoryTodoRepository) Replace(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.indexOf(id); i >= 0 {
+		return r.updateAt(i, func(t *domain.Todo) {
+			applyDto(t, payload)
+		}), false, nil
+	}
+
+	if slices.ContainsFunc(r.trash, func(v domain.Todo) bool { return v.ID == id }) {
+		return nil, false, errTrashed
+	}
+
+	data := newTodo(id, payload)
+	r.insert(clone(data))
+
+	return &data, true, nil
+}
+
+// UpdateStatus implements domain.TodoRepository.
+func (r *memoryTodoRepository) UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*domain.Todo, error) {
+	return r.update(id, version, func(t *domain.Todo) {
+		t.IsCompleted = isCompleted
+	})
+}
+
+// Revert implements domain.TodoRepository.
+func (r *memoryTodoRepository) Revert(ctx context.Context, id string, state domain.TodoState) (*domain.Todo, error) {
+	return r.update(id, 0, func(t *domain.Todo) {
+		applyDto(t, state.Dto())
+		t.IsCompleted = state.IsCompleted
+	})
+}
+
+// updateMany applies fn to the todos of the ids, the unknown ids are ignored
+func (r *memoryTodoRepository) updateMany(ids []string, fn func(t *domain.Todo)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, v := range r.items {
+		if !slices.Contains(ids, v.ID) {
+			continue
+		}
+
+		data := clone(v)
+		fn(&data)
+
+		if data.Audit == nil {
+			data.Audit = &domain.Audit{}
+		}
+		data.UpdatedAt = time.Now()
+		data.Version++
+
+		r.items[i] = data
+	}
+}
+
+// UpdateStatusMany implements domain.TodoRepository.
+func (r *memoryTodoRepository) UpdateStatusMany(ctx context.Context, ids []string, isCompleted bool) error {
+	r.updateMany(ids, func(t *domain.Todo) {
+		t.IsCompleted = isCompleted
+	})
+
+	return nil
+}
+
+// UpdateListMany implements domain.TodoRepository.
+func (r *memoryTodoRepository) UpdateListMany(ctx context.Context, ids []string, listID string) error {
+	r.updateMany(ids, func(t *domain.Todo) {
+		t.ListID = listID
+	})
+
+	return nil
+}
+
+// DeleteMany implements domain.TodoRepository.
+func (r *memoryTodoRepository) DeleteMany(ctx context.Context, ids []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	for _, v := range ids {
+		if i := r.indexOf(v); i > -1 {
+			r.trashAt(i, now)
+		}
+	}
+
+	return nil
+}
+
+// GetTrash implements domain.TodoRepository.
+func (r *memoryTodoRepository) GetTrash(ctx context.Context, filter *domain.Filter, skip int64, limit int64) ([]domain.Todo, int64, error) {
+	result := []domain.Todo{}
+
+	if err := filter.Validate(domain.TodoFilterFields); err != nil {
+		return result, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []domain.Todo{}
+
+	for _, v := range r.trash {
+		if matchFilter(v, filter) {
+			matched = append(matched, v)
+		}
+	}
+
+	// the most recently deleted todos come first
+	slices.SortStableFunc(matched, func(a, b domain.Todo) int {
+		if res := b.DeletedAt.Compare(*a.DeletedAt); res != 0 {
+			return res
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	count := int64(len(matched))
+
+	for i, v := range matched {
+		if int64(i) < skip {
+			continue
+		}
+
+		if limit > -1 && int64(len(result)) >= limit {
+			break
+		}
+
+		result = append(result, clone(v))
+	}
+
+	return result, count, nil
+}
+
+// Restore implements domain.TodoRepository.
+func (r *memoryTodoRepository) Restore(ctx context.Context, id string) (*domain.Todo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.trash, func(v domain.Todo) bool { return v.ID == id })
+	if i < 0 {
+		return nil, errNotFound
+	}
+
+	data := clone(r.trash[i])
+	data.DeletedAt = nil
+	data.Version++
+
+	r.trash = append(r.trash[:i], r.trash[i+1:]...)
+	r.insert(data)
+
+	result := clone(data)
+
+	return &result, nil
+}
+
+// Purge implements domain.TodoRepository.
+func (r *memoryTodoRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := len(r.trash)
+
+	r.trash = slices.DeleteFunc(r.trash, func(v domain.Todo) bool {
+		return v.DeletedAt.Before(before)
+	})
+
+	return int64(count - len(r.trash)), nil
+}
+
+// AddTags implements domain.TodoRepository.
+func (r *memoryTodoRepository) AddTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	return r.update(id, 0, func(t *domain.Todo) {
+		t.Tags = domain.NormalizeTags(append(t.Tags, tags...))
+	})
+}
+
+// RemoveTags implements domain.TodoRepository.
+func (r *memoryTodoRepository) RemoveTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	return r.update(id, 0, func(t *domain.Todo) {
+		t.Tags = slices.DeleteFunc(t.Tags, func(v string) bool {
+			return slices.Contains(tags, v)
+		})
+	})
+}
+
+// GetTags implements domain.TodoRepository.
+func (r *memoryTodoRepository) GetTags(ctx context.Context) ([]domain.TagCount, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	counts := map[string]int64{}
+
+	for _, v := range r.items {
+		for _, tag := range v.Tags {
+			counts[tag]++
+		}
+	}
+
+	result := []domain.TagCount{}
+
+	for k, v := range counts {
+		result = append(result, domain.TagCount{Tag: k, Count: v})
+	}
+
+	slices.SortFunc(result, func(a, b domain.TagCount) int {
+		if res := cmp.Compare(b.Count, a.Count); res != 0 {
+			return res
+		}
+
+		return strings.Compare(a.Tag, b.Tag)
+	})
+
+	return result, nil
+}
+
+// GetSubtree implements domain.TodoRepository.
+func (r *memoryTodoRepository) GetSubtree(ctx context.Context, id string) ([]domain.TodoNode, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, errNotFound
+	}
+
+	result := []domain.TodoNode{{Todo: clone(r.items[i])}}
+	visited := map[string]bool{id: true}
+
+	// breadth first, one level of subtasks at a time
+	for level := result; len(level) > 0; {
+		next := []domain.TodoNode{}
+
+		for _, v := range r.items {
+			if visited[v.ID] || !slices.ContainsFunc(level, func(n domain.TodoNode) bool { return n.ID == v.ParentID }) {
+				continue
+			}
+
+			visited[v.ID] = true
+			next = append(next, domain.TodoNode{Todo: clone(v), Depth: level[0].Depth + 1})
+		}
+
+		slices.SortFunc(next, func(a, b domain.TodoNode) int {
+			return strings.Compare(a.ID, b.ID)
+		})
+
+		result = append(result, next...)
+		level = next
+	}
+
+	return result, nil
+}
+
+// NewMemoryTodoRepository will create an object that represent the todo.Repository interface,
+// the data only lives as long as the process and is safe for concurrent use
+func NewMemoryTodoRepository() domain.TodoRepository {
+	return &memoryTodoRepository{
+		items: []domain.Todo{},
+		trash: []domain.Todo{},
+	}
+}
@@ -0,0 +1,230 @@
+package memory_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/ariefsn/go-resik/app/todo/repository/memory"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/stretchr/testify/assert"
+)
+
+var MOCK_DTO = &domain.TodoDto{
+	Title:       "Title - 1",
+	Description: "Description - 1",
+}
+
+var MOCK_DTO_UPDATE = domain.TodoDto{
+	Title:       "Title 1 - Updated",
+	Description: "Description 1 - Updated",
+}
+
+func seed(t *testing.T, repo domain.TodoRepository, n int) []domain.Todo {
+	result := []domain.Todo{}
+
+	for i := 1; i <= n; i++ {
+		res, err := repo.Create(context.TODO(), &domain.TodoDto{
+			Title:       fmt.Sprintf("Title %d", i),
+			Description: fmt.Sprintf("Description %d", i),
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		result = append(result, *res)
+	}
+
+	return result
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo := memory.NewMemoryTodoRepository()
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.NotEmpty(t, res.ID)
+		assert.Equal(t, MOCK_DTO.Title, res.Title)
+		assert.Equal(t, MOCK_DTO.Description, res.Description)
+		assert.False(t, res.IsCompleted)
+		assert.False(t, res.CreatedAt.IsZero())
+	})
+}
+
+func TestGet(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoRepository()
+	data := seed(t, mockRepo, 12)
+
+	t.Run("Success", func(t *testing.T) {
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 10, len(res))
+		assert.EqualValues(t, len(data), total)
+		assert.Equal(t, data[0].ID, res[0].ID)
+	})
+
+	t.Run("Success With Pagination", func(t *testing.T) {
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 10, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		assert.EqualValues(t, len(data), total)
+		assert.Equal(t, data[10].ID, res[0].ID)
+	})
+
+	t.Run("Success With Filter", func(t *testing.T) {
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("title", domain.FoContains, "title 1"), nil, 0, 2)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, len(res))
+		// Title 1, Title 10, Title 11 and Title 12
+		assert.EqualValues(t, 4, total)
+	})
+
+	t.Run("Success With Boolean Filter", func(t *testing.T) {
+		res, total, err := mockRepo.Get(context.TODO(), domain.And(
+			domain.Where("title", domain.FoContains, "Title 2"),
+			domain.Where("isCompleted", domain.FoEq, false),
+		), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.EqualValues(t, 1, total)
+	})
+
+	t.Run("Success Not Matched", func(t *testing.T) {
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("description", domain.FoContains, "unknown"), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoRepository()
+	data := seed(t, mockRepo, 1)
+
+	t.Run("Success", func(t *testing.T) {
+		res, err := mockRepo.GetByID(context.TODO(), data[0].ID)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, data[0].Title, res.Title)
+		assert.Equal(t, data[0].Description, res.Description)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		res, err := mockRepo.GetByID(context.TODO(), "unknown")
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoRepository()
+	data := seed(t, mockRepo, 1)
+
+	t.Run("Success", func(t *testing.T) {
+		res, err := mockRepo.Update(context.TODO(), data[0].ID, &MOCK_DTO_UPDATE, 0)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, MOCK_DTO_UPDATE.Title, res.Title)
+		assert.Equal(t, MOCK_DTO_UPDATE.Description, res.Description)
+		assert.Equal(t, data[0].CreatedAt, res.CreatedAt)
+		assert.False(t, res.UpdatedAt.Before(data[0].UpdatedAt))
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		res, err := mockRepo.Update(context.TODO(), "unknown", &MOCK_DTO_UPDATE, 0)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUpdateStatus(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoRepository()
+	data := seed(t, mockRepo, 1)
+
+	t.Run("Success", func(t *testing.T) {
+		res, err := mockRepo.UpdateStatus(context.TODO(), data[0].ID, true, 0)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.True(t, res.IsCompleted)
+
+		stored, _ := mockRepo.GetByID(context.TODO(), data[0].ID)
+		assert.True(t, stored.IsCompleted)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		res, err := mockRepo.UpdateStatus(context.TODO(), "unknown", true, 0)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoRepository()
+	data := seed(t, mockRepo, 1)
+
+	t.Run("Success", func(t *testing.T) {
+		err := mockRepo.Delete(context.TODO(), data[0].ID, 0)
+
+		assert.Nil(t, err)
+
+		_, total, _ := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+		assert.EqualValues(t, 0, total)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		err := mockRepo.Delete(context.TODO(), data[0].ID, 0)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoRepository()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+			if err != nil {
+				return
+			}
+
+			mockRepo.UpdateStatus(context.TODO(), res.ID, true, 0)
+			mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+		}()
+	}
+
+	wg.Wait()
+
+	_, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 20, total)
+}
+
+func TestContract(t *testing.T) {
+	repotest.RunTodoRepositoryTests(t, func(t *testing.T) domain.TodoRepository {
+		return memory.NewMemoryTodoRepository()
+	})
+}
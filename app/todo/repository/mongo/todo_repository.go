@@ -1,10 +1,14 @@
 package mongo
 
 import (
+	"cmp"
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/ariefsn/go-resik/common"
 	"github.com/ariefsn/go-resik/domain"
 	"github.com/ariefsn/go-resik/helper"
 	"github.com/ariefsn/go-resik/logger"
@@ -14,6 +18,227 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// mongoFields maps the json names of the todo fields to their document paths
+var mongoFields = map[string]string{
+	"id":          "_id",
+	"title":       "title",
+	"description": "description",
+	"isCompleted": "isCompleted",
+	"tags":        "tags",
+	"priority":    "priority",
+	"dueAt":       "dueAt",
+	"parentId":    "parentId",
+	"listId":      "listId",
+	"version":     "version",
+	"createdAt":   "audit.createdAt",
+	"updatedAt":   "audit.updatedAt",
+}
+
+// live matches the todos out of the trash along with the filter, the filters never match the deletedAt
+func live(filter bson.M) bson.M {
+	result := bson.M{"audit.deletedAt": nil}
+
+	for k, v := range filter {
+		result[k] = v
+	}
+
+	return result
+}
+
+// buildProjection maps the projected fields to their document paths
+func buildProjection(fields []string) []string {
+	result := []string{}
+
+	for _, v := range fields {
+		if field, ok := mongoFields[v]; ok {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+func buildSort(sort []domain.Sort) []helper.MongoSort {
+	result := []helper.MongoSort{}
+	hasID := false
+
+	for _, v := range sort {
+		field, ok := mongoFields[v.Field]
+		if !ok {
+			continue
+		}
+
+		hasID = hasID || field == "_id"
+
+		sortBy := helper.SortByAsc
+		if v.Order == domain.SortDesc {
+			sortBy = helper.SortByDesc
+		}
+
+		result = append(result, helper.MongoSort{
+			SortField: field,
+			SortBy:    sortBy,
+		})
+	}
+
+	// keep the order of equal keys stable across pages
+	if len(result) > 0 && !hasID {
+		result = append(result, helper.MongoSort{
+			SortField: "_id",
+			SortBy:    helper.SortByAsc,
+		})
+	}
+
+	return result
+}
+
+// buildFilter compiles the filter tree into a match document, an empty document matches everything
+func buildFilter(filter *domain.Filter) (bson.M, error) {
+	if filter == nil {
+		return bson.M{}, nil
+	}
+
+	if filter.IsCondition() {
+		field, ok := mongoFields[filter.Field]
+		if !ok {
+			return nil, domain.NewValidationError(fmt.Sprintf("filter field %s is not supported", filter.Field))
+		}
+
+		match := helper.MongoFilter(filter.Operator, field, filter.Value)
+		if match == nil {
+			return nil, domain.NewValidationError(fmt.Sprintf("filter operator %s is not supported", filter.Operator))
+		}
+
+		return match, nil
+	}
+
+	parts := bson.A{}
+
+	for _, v := range filter.And {
+		match, err := buildFilter(v)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(match) > 0 {
+			parts = append(parts, match)
+		}
+	}
+
+	ors := bson.A{}
+
+	for _, v := range filter.Or {
+		match, err := buildFilter(v)
+		if err != nil {
+			return nil, err
+		}
+
+		// one of the alternatives matches everything, so does the whole group
+		if len(match) == 0 {
+			ors = bson.A{}
+			break
+		}
+
+		ors = append(ors, match)
+	}
+
+	if len(ors) > 0 {
+		parts = append(parts, bson.M{"$or": ors})
+	}
+
+	if filter.Not != nil {
+		match, err := buildFilter(filter.Not)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(match) == 0 {
+			parts = append(parts, bson.M{"$expr": false})
+		} else {
+			parts = append(parts, bson.M{"$nor": bson.A{match}})
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return bson.M{}, nil
+	case 1:
+		return parts[0].(bson.M), nil
+	}
+
+	return bson.M{"$and": parts}, nil
+}
+
+// searchIndex is the text index backing the search, a title match weighs twice a description match
+var searchIndex = mongo.IndexModel{
+	Keys: bson.D{
+		{Key: "title", Value: "text"},
+		{Key: "description", Value: "text"},
+	},
+	Options: options.Index().SetName("todos_search").SetWeights(bson.M{
+		"title":       2,
+		"description": 1,
+	}),
+}
+
+// overdueIndex backs the overdue view, the incomplete todos are read by their due date
+var overdueIndex = mongo.IndexModel{
+	Keys: bson.D{
+		{Key: "isCompleted", Value: 1},
+		{Key: "dueAt", Value: 1},
+	},
+	Options: options.Index().SetName("todos_overdue"),
+}
+
+// parentIndex backs the subtree lookups, the subtasks are read by their parent
+var parentIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "parentId", Value: 1}},
+	Options: options.Index().SetName("todos_parent"),
+}
+
+// listIndex backs the lists, the todos of a list are read by the list
+var listIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "listId", Value: 1}},
+	Options: options.Index().SetName("todos_list"),
+}
+
+// deletedIndex backs the trash and the purge, the deleted todos are read by their deletion time
+var deletedIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "audit.deletedAt", Value: 1}},
+	Options: options.Index().SetName("todos_deleted"),
+}
+
+// EnsureIndexes creates the indexes of the todos collection, creating an existing index is a no-op.
+// The todos stored before they had a version get the first one, their entity tag could never match otherwise. The
+// ones stored before they had a priority get none like the MySQL rows, the next page of a cursor would skip them as
+// a missing field matches no comparison.
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	collection := db.Collection(domain.Todo{}.TableName())
+
+	_, err := collection.Indexes().CreateMany(ctx, []mongo.IndexModel{searchIndex, overdueIndex, parentIndex, listIndex, deletedIndex})
+
+	if err != nil {
+		logger.Error(err)
+		return helper.ParseMongoError(err)
+	}
+
+	_, err = collection.UpdateMany(ctx, bson.M{"version": bson.M{"$exists": false}}, helper.MongoSet(bson.M{"version": 1}))
+
+	if err != nil {
+		logger.Error(err)
+		return helper.ParseMongoError(err)
+	}
+
+	_, err = collection.UpdateMany(ctx, bson.M{"priority": bson.M{"$exists": false}}, helper.MongoSet(bson.M{"priority": 0}))
+
+	if err != nil {
+		logger.Error(err)
+		return helper.ParseMongoError(err)
+	}
+
+	return nil
+}
+
 type mongoTodoRepository struct {
 	Db *mongo.Database
 }
@@ -25,6 +250,12 @@ func (r *mongoTodoRepository) Create(ctx context.Context, payload *domain.TodoDt
 		Title:       payload.Title,
 		Description: payload.Description,
 		IsCompleted: false,
+		Tags:        payload.Tags,
+		Priority:    payload.PriorityValue(),
+		DueAt:       payload.DueAt,
+		ParentID:    payload.ParentID,
+		ListID:      payload.ListID,
+		Version:     1,
 		Audit: &domain.Audit{
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
@@ -35,144 +266,611 @@ func (r *mongoTodoRepository) Create(ctx context.Context, payload *domain.TodoDt
 
 	if err != nil {
 		logger.Error(err)
-		return nil, err
+		return nil, helper.ParseMongoError(err)
 	}
 
 	return &data, nil
 }
 
-// Delete implements domain.TodoRepository.
-func (r *mongoTodoRepository) Delete(ctx context.Context, id string) error {
-	res := r.Db.Collection(domain.Todo{}.TableName()).FindOneAndDelete(ctx, bson.M{"_id": id})
+// nextVersion increments the version of the todos, every write sets it
+var nextVersion = bson.M{"version": 1}
 
-	if res.Err() != nil {
-		err := helper.ParseMongoError(res.Err())
-		logger.Error(err)
+// byVersion matches the live todo of the id when it has the version, zero matches any version
+func byVersion(id string, version int64) bson.M {
+	filter := bson.M{"_id": id}
+
+	if version > 0 {
+		filter["version"] = version
 	}
 
-	return helper.ParseMongoError(res.Err())
+	return live(filter)
 }
 
-// Get implements domain.TodoRepository.
-func (r *mongoTodoRepository) Get(ctx context.Context, filter interface{}, skip int64, limit int64) ([]domain.Todo, int64, error) {
-	result := []domain.Todo{}
-	coll := r.Db.Collection(domain.Todo{}.TableName())
-
-	if filter == nil {
-		filter = common.M{}
+// notWritten tells why a write of the todo matched no document, either it's missing or in the trash, or it has
+// another version than the expected one
+func (r *mongoTodoRepository) notWritten(ctx context.Context, id string, version int64, err error) error {
+	if version > 0 && errors.Is(err, domain.ErrNotFound) {
+		if _, getErr := r.GetByID(ctx, id, "id"); getErr == nil {
+			return domain.NewPreconditionError("todo version doesn't match", err)
+		}
 	}
 
-	_bson, _ := helper.ToBsonM(filter)
+	return err
+}
+
+// Delete implements domain.TodoRepository.
+func (r *mongoTodoRepository) Delete(ctx context.Context, id string, version int64) error {
+	update := helper.MongoSet(bson.M{
+		"audit.deletedAt": time.Now(),
+	})
+	update["$inc"] = nextVersion
 
-	filterBson := bson.M{}
+	res := r.Db.Collection(domain.Todo{}.TableName()).FindOneAndUpdate(ctx, byVersion(id, version), update)
 
-	for k, v := range _bson {
-		switch t := v.(type) {
-		case string:
-			_f := helper.MongoFilter(helper.FoContains, k, t)
-			filterBson[k] = _f[k]
-		}
+	if res.Err() != nil {
+		logger.Error(res.Err())
+		return r.notWritten(ctx, id, version, helper.ParseMongoError(res.Err()))
 	}
 
-	count, err := coll.CountDocuments(ctx, filterBson)
+	return nil
+}
 
-	if err != nil && err != mongo.ErrNilDocument {
-		logger.Error(err)
-		return result, 0, err
-	}
+func (r *mongoTodoRepository) find(ctx context.Context, filter bson.M, sort []helper.MongoSort, skip int64, limit int64, fields []string) ([]domain.Todo, error) {
+	result := []domain.Todo{}
 
 	pipe := helper.MongoPipe(helper.MongoAggregate{
-		Match: filterBson,
-		Sort:  nil,
-		Skip:  &skip,
-		Limit: &limit,
+		Match:   filter,
+		Sort:    sort,
+		Skip:    &skip,
+		Limit:   &limit,
+		Project: buildProjection(domain.ProjectFields(fields)),
 	})
 
-	cur, err := coll.Aggregate(ctx, pipe)
+	cur, err := r.Db.Collection(domain.Todo{}.TableName()).Aggregate(ctx, pipe)
 
 	if err != nil {
 		logger.Error(err)
-		return result, 0, err
+		return result, helper.ParseMongoError(err)
 	}
 
+	defer cur.Close(ctx)
+
 	for cur.Next(ctx) {
 		var row domain.Todo
 
 		err = cur.Decode(&row)
 		if err != nil {
-			break
+			logger.Error(err)
+			return []domain.Todo{}, helper.ParseMongoError(err)
 		}
 
 		result = append(result, row)
 	}
 
+	if err := cur.Err(); err != nil {
+		logger.Error(err)
+		return []domain.Todo{}, helper.ParseMongoError(err)
+	}
+
+	return result, nil
+}
+
+// Get implements domain.TodoRepository.
+func (r *mongoTodoRepository) Get(ctx context.Context, filter *domain.Filter, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	result := []domain.Todo{}
+
+	if err := filter.Validate(domain.TodoFilterFields); err != nil {
+		return result, 0, err
+	}
+
+	filterBson, err := buildFilter(filter)
+
+	if err != nil {
+		return result, 0, err
+	}
+
+	filterBson = live(filterBson)
+
+	count, err := r.Db.Collection(domain.Todo{}.TableName()).CountDocuments(ctx, filterBson)
+
+	if err != nil && err != mongo.ErrNilDocument {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	result, err = r.find(ctx, filterBson, buildSort(sort), skip, limit, fields)
+
+	if err != nil {
+		return []domain.Todo{}, 0, err
+	}
+
 	return result, count, nil
 }
 
+// GetByCursor implements domain.TodoRepository.
+func (r *mongoTodoRepository) GetByCursor(ctx context.Context, filter *domain.Filter, sort []domain.Sort, cursor *domain.Cursor, limit int64, fields ...string) ([]domain.Todo, bool, error) {
+	keyset := domain.And(filter, domain.KeysetFilter(sort, cursor, domain.TodoNullableFields...))
+
+	if err := keyset.Validate(domain.TodoFilterFields); err != nil {
+		return []domain.Todo{}, false, err
+	}
+
+	filterBson, err := buildFilter(keyset)
+
+	if err != nil {
+		return []domain.Todo{}, false, err
+	}
+
+	result, err := r.find(ctx, live(filterBson), buildSort(domain.KeysetSort(sort, cursor)), 0, domain.KeysetLimit(limit), domain.ProjectFields(fields, domain.KeysetFields(sort)...))
+
+	if err != nil {
+		return result, false, err
+	}
+
+	result, hasMore := domain.TrimKeyset(result, cursor, limit)
+
+	return result, hasMore, nil
+}
+
 // GetByID implements domain.TodoRepository.
-func (r *mongoTodoRepository) GetByID(ctx context.Context, id string) (*domain.Todo, error) {
+func (r *mongoTodoRepository) GetByID(ctx context.Context, id string, fields ...string) (*domain.Todo, error) {
 	result := domain.Todo{}
-	err := r.Db.Collection(result.TableName()).FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+	opts := options.FindOne()
+
+	if projection := buildProjection(domain.ProjectFields(fields)); len(projection) > 0 {
+		opts.SetProjection(helper.MongoProjection(projection...))
+	}
+
+	err := r.Db.Collection(result.TableName()).FindOne(ctx, live(bson.M{"_id": id}), opts).Decode(&result)
 
 	if err != nil {
 		logger.Error(err)
-		return nil, err
+		return nil, helper.ParseMongoError(err)
 	}
 
 	return &result, nil
 }
 
-func (r *mongoTodoRepository) update(ctx context.Context, filter bson.M, payload bson.M) *mongo.SingleResult {
-	upsert := true
+// Search implements domain.TodoRepository.
+func (r *mongoTodoRepository) Search(ctx context.Context, query string, skip int64, limit int64) ([]domain.TodoSearchResult, int64, error) {
+	result := []domain.TodoSearchResult{}
+	filter := live(bson.M{"$text": bson.M{"$search": query}})
+
+	count, err := r.Db.Collection(domain.Todo{}.TableName()).CountDocuments(ctx, filter)
+
+	if err != nil && err != mongo.ErrNilDocument {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	pipe := []bson.M{
+		helper.MongoMatch(filter),
+		helper.MongoSet(bson.M{"score": bson.M{"$meta": "textScore"}}),
+	}
+
+	pipe = append(pipe, helper.MongoPipe(helper.MongoAggregate{
+		Sort: []helper.MongoSort{
+			{SortField: "score", SortBy: helper.SortByDesc},
+			{SortField: "_id", SortBy: helper.SortByAsc},
+		},
+		Skip:  &skip,
+		Limit: &limit,
+	})...)
+
+	cur, err := r.Db.Collection(domain.Todo{}.TableName()).Aggregate(ctx, pipe)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var row domain.TodoSearchResult
+
+		err = cur.Decode(&row)
+		if err != nil {
+			logger.Error(err)
+			return []domain.TodoSearchResult{}, 0, helper.ParseMongoError(err)
+		}
+
+		result = append(result, row)
+	}
+
+	if err := cur.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TodoSearchResult{}, 0, helper.ParseMongoError(err)
+	}
+
+	return result, count, nil
+}
+
+// update applies the update to the todo along with its next version, it never creates the todo
+func (r *mongoTodoRepository) update(ctx context.Context, id string, version int64, update bson.M) (*domain.Todo, error) {
 	returnDoc := options.After
 
-	return r.Db.Collection(domain.Todo{}.TableName()).FindOneAndUpdate(ctx, filter, bson.M{
-		"$set": payload,
-	}, &options.FindOneAndUpdateOptions{
+	update["$inc"] = nextVersion
+
+	res, err := r.decodeUpdated(r.Db.Collection(domain.Todo{}.TableName()).FindOneAndUpdate(ctx, byVersion(id, version), update, &options.FindOneAndUpdateOptions{
 		ReturnDocument: &returnDoc,
-		Upsert:         &upsert,
-	})
+	}))
+
+	if err != nil {
+		return nil, r.notWritten(ctx, id, version, err)
+	}
+
+	return res, nil
 }
 
-// Update implements domain.TodoRepository.
-func (r *mongoTodoRepository) Update(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, error) {
+func (r *mongoTodoRepository) decodeUpdated(res *mongo.SingleResult) (*domain.Todo, error) {
 	var data domain.Todo
 
-	res := r.update(ctx, bson.M{"_id": id}, bson.M{
+	if res.Err() != nil {
+		logger.Error(res.Err())
+		return nil, helper.ParseMongoError(res.Err())
+	}
+
+	if err := res.Decode(&data); err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	return &data, nil
+}
+
+// dtoUpdate sets the fields of the payload and unsets the empty optional ones, the tags are kept when the payload
+// has none
+func dtoUpdate(payload *domain.TodoDto) bson.M {
+	set := bson.M{
 		"title":           payload.Title,
 		"description":     payload.Description,
+		"priority":        payload.PriorityValue(),
 		"audit.updatedAt": time.Now(),
-	})
+	}
 
-	if res.Err() != nil {
-		err := helper.ParseMongoError(res.Err())
+	if payload.Tags != nil {
+		set["tags"] = payload.Tags
+	}
+
+	update := helper.MongoSet(set)
+	unset := bson.M{}
+
+	if payload.DueAt != nil {
+		set["dueAt"] = payload.DueAt
+	} else {
+		unset["dueAt"] = ""
+	}
+
+	if payload.ParentID != "" {
+		set["parentId"] = payload.ParentID
+	} else {
+		unset["parentId"] = ""
+	}
+
+	if payload.ListID != "" {
+		set["listId"] = payload.ListID
+	} else {
+		unset["listId"] = ""
+	}
+
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	return update
+}
+
+// Update implements domain.TodoRepository.
+func (r *mongoTodoRepository) Update(ctx context.Context, id string, payload *domain.TodoDto, version int64) (*domain.Todo, error) {
+	return r.update(ctx, id, version, dtoUpdate(payload))
+}
+
+// Replace implements domain.TodoRepository.
+func (r *mongoTodoRepository) Replace(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, bool, error) {
+	// the inserted todo gets the fields a created one has, its version is incremented from none to 1
+	update := dtoUpdate(payload)
+	update["$inc"] = nextVersion
+	update["$setOnInsert"] = bson.M{
+		"isCompleted":     false,
+		"audit.createdAt": time.Now(),
+	}
+
+	res, err := r.Db.Collection(domain.Todo{}.TableName()).UpdateOne(ctx, byVersion(id, 0), update, options.Update().SetUpsert(true))
+
+	if err != nil {
 		logger.Error(err)
-		return nil, err
+
+		// the live todo is missing yet the id is taken, by a todo in the trash
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, false, domain.NewConflictError("todo is in the trash, restore it first", err)
+		}
+
+		return nil, false, helper.ParseMongoError(err)
 	}
 
-	res.Decode(&data)
+	data, err := r.GetByID(ctx, id)
 
-	return &data, nil
+	if err != nil {
+		return nil, false, err
+	}
+
+	return data, res.UpsertedCount > 0, nil
 }
 
 // UpdateStatus implements domain.TodoRepository.
-func (r *mongoTodoRepository) UpdateStatus(ctx context.Context, id string, isCompleted bool) (*domain.Todo, error) {
-	var data domain.Todo
+func (r *mongoTodoRepository) UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*domain.Todo, error) {
+	return r.update(ctx, id, version, helper.MongoSet(bson.M{
+		"isCompleted":     isCompleted,
+		"audit.updatedAt": time.Now(),
+	}))
+}
+
+// Revert implements domain.TodoRepository.
+func (r *mongoTodoRepository) Revert(ctx context.Context, id string, state domain.TodoState) (*domain.Todo, error) {
+	update := dtoUpdate(state.Dto())
+	// the completion isn't part of the payload, it's set along
+	update["$set"].(bson.M)["isCompleted"] = state.IsCompleted
 
-	res := r.update(ctx, bson.M{"_id": id}, bson.M{
+	return r.update(ctx, id, 0, update)
+}
+
+// UpdateStatusMany implements domain.TodoRepository.
+func (r *mongoTodoRepository) UpdateStatusMany(ctx context.Context, ids []string, isCompleted bool) error {
+	update := helper.MongoSet(bson.M{
 		"isCompleted":     isCompleted,
 		"audit.updatedAt": time.Now(),
 	})
+	update["$inc"] = nextVersion
 
-	if res.Err() != nil {
-		err := helper.ParseMongoError(res.Err())
+	_, err := r.Db.Collection(domain.Todo{}.TableName()).UpdateMany(ctx, live(bson.M{"_id": bson.M{"$in": ids}}), update)
+
+	if err != nil {
+		logger.Error(err)
+		return helper.ParseMongoError(err)
+	}
+
+	return nil
+}
+
+// UpdateListMany implements domain.TodoRepository.
+func (r *mongoTodoRepository) UpdateListMany(ctx context.Context, ids []string, listID string) error {
+	set := bson.M{
+		"audit.updatedAt": time.Now(),
+	}
+
+	update := helper.MongoSet(set)
+	update["$inc"] = nextVersion
+
+	if listID != "" {
+		set["listId"] = listID
+	} else {
+		update["$unset"] = bson.M{"listId": ""}
+	}
+
+	_, err := r.Db.Collection(domain.Todo{}.TableName()).UpdateMany(ctx, live(bson.M{"_id": bson.M{"$in": ids}}), update)
+
+	if err != nil {
 		logger.Error(err)
+		return helper.ParseMongoError(err)
+	}
+
+	return nil
+}
+
+// DeleteMany implements domain.TodoRepository.
+func (r *mongoTodoRepository) DeleteMany(ctx context.Context, ids []string) error {
+	update := helper.MongoSet(bson.M{
+		"audit.deletedAt": time.Now(),
+	})
+	update["$inc"] = nextVersion
+
+	_, err := r.Db.Collection(domain.Todo{}.TableName()).UpdateMany(ctx, live(bson.M{"_id": bson.M{"$in": ids}}), update)
+
+	if err != nil {
+		logger.Error(err)
+		return helper.ParseMongoError(err)
+	}
+
+	return nil
+}
+
+// GetTrash implements domain.TodoRepository.
+func (r *mongoTodoRepository) GetTrash(ctx context.Context, filter *domain.Filter, skip int64, limit int64) ([]domain.Todo, int64, error) {
+	result := []domain.Todo{}
+
+	if err := filter.Validate(domain.TodoFilterFields); err != nil {
+		return result, 0, err
+	}
+
+	filterBson, err := buildFilter(filter)
+
+	if err != nil {
+		return result, 0, err
+	}
+
+	trashed := bson.M{"audit.deletedAt": bson.M{"$ne": nil}}
+
+	for k, v := range filterBson {
+		trashed[k] = v
+	}
+
+	filterBson = trashed
+
+	count, err := r.Db.Collection(domain.Todo{}.TableName()).CountDocuments(ctx, filterBson)
+
+	if err != nil && err != mongo.ErrNilDocument {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	// the most recently deleted todos come first
+	result, err = r.find(ctx, filterBson, []helper.MongoSort{
+		{SortField: "audit.deletedAt", SortBy: helper.SortByDesc},
+		{SortField: "_id", SortBy: helper.SortByAsc},
+	}, skip, limit, nil)
+
+	if err != nil {
+		return []domain.Todo{}, 0, err
+	}
+
+	return result, count, nil
+}
+
+// Restore implements domain.TodoRepository.
+func (r *mongoTodoRepository) Restore(ctx context.Context, id string) (*domain.Todo, error) {
+	returnDoc := options.After
+
+	return r.decodeUpdated(r.Db.Collection(domain.Todo{}.TableName()).FindOneAndUpdate(ctx, bson.M{
+		"_id":             id,
+		"audit.deletedAt": bson.M{"$ne": nil},
+	}, bson.M{
+		"$unset": bson.M{"audit.deletedAt": ""},
+		"$inc":   nextVersion,
+	}, &options.FindOneAndUpdateOptions{
+		ReturnDocument: &returnDoc,
+	}))
+}
+
+// Purge implements domain.TodoRepository.
+func (r *mongoTodoRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.Db.Collection(domain.Todo{}.TableName()).DeleteMany(ctx, bson.M{"audit.deletedAt": bson.M{"$lt": before}})
+
+	if err != nil {
+		logger.Error(err)
+		return 0, helper.ParseMongoError(err)
+	}
+
+	return res.DeletedCount, nil
+}
+
+// AddTags implements domain.TodoRepository.
+func (r *mongoTodoRepository) AddTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	if _, err := r.update(ctx, id, 0, bson.M{
+		"$addToSet": bson.M{"tags": bson.M{"$each": tags}},
+		"$set":      bson.M{"audit.updatedAt": time.Now()},
+	}); err != nil {
 		return nil, err
 	}
 
-	res.Decode(&data)
+	// $addToSet appends the tags, pushing none sorts them as the other drivers keep them
+	returnDoc := options.After
 
-	return &data, nil
+	return r.decodeUpdated(r.Db.Collection(domain.Todo{}.TableName()).FindOneAndUpdate(ctx, live(bson.M{"_id": id}), bson.M{
+		"$push": bson.M{"tags": bson.M{"$each": bson.A{}, "$sort": 1}},
+	}, &options.FindOneAndUpdateOptions{
+		ReturnDocument: &returnDoc,
+	}))
+}
+
+// RemoveTags implements domain.TodoRepository.
+func (r *mongoTodoRepository) RemoveTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	return r.update(ctx, id, 0, bson.M{
+		"$pullAll": bson.M{"tags": tags},
+		"$set":     bson.M{"audit.updatedAt": time.Now()},
+	})
+}
+
+// GetTags implements domain.TodoRepository.
+func (r *mongoTodoRepository) GetTags(ctx context.Context) ([]domain.TagCount, error) {
+	result := []domain.TagCount{}
+
+	pipe := []bson.M{
+		helper.MongoMatch(live(nil)),
+		helper.MongoUnwind(helper.MongoUnwindOptions{Path: "$tags"}),
+		helper.MongoGroup("$tags", bson.M{"count": bson.M{"$sum": 1}}),
+		{"$sort": helper.MongoSorting(
+			helper.MongoSort{SortField: "count", SortBy: helper.SortByDesc},
+			helper.MongoSort{SortField: "_id", SortBy: helper.SortByAsc},
+		)},
+	}
+
+	cur, err := r.Db.Collection(domain.Todo{}.TableName()).Aggregate(ctx, pipe)
+
+	if err != nil {
+		logger.Error(err)
+		return result, helper.ParseMongoError(err)
+	}
+
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var row domain.TagCount
+
+		err = cur.Decode(&row)
+		if err != nil {
+			logger.Error(err)
+			return []domain.TagCount{}, helper.ParseMongoError(err)
+		}
+
+		result = append(result, row)
+	}
+
+	if err := cur.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TagCount{}, helper.ParseMongoError(err)
+	}
+
+	return result, nil
+}
+
+// GetSubtree implements domain.TodoRepository.
+func (r *mongoTodoRepository) GetSubtree(ctx context.Context, id string) ([]domain.TodoNode, error) {
+	pipe := []bson.M{
+		helper.MongoMatch(live(bson.M{"_id": id})),
+		helper.MongoGraphLookup(helper.MongoGraphLookupOptions{
+			From:                    domain.Todo{}.TableName(),
+			StartWith:               "$_id",
+			ConnectFromField:        "_id",
+			ConnectToField:          "parentId",
+			DepthField:              "depth",
+			As:                      "subtasks",
+			RestrictSearchWithMatch: live(nil),
+		}),
+	}
+
+	cur, err := r.Db.Collection(domain.Todo{}.TableName()).Aggregate(ctx, pipe)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	defer cur.Close(ctx)
+
+	if !cur.Next(ctx) {
+		if err := cur.Err(); err != nil {
+			logger.Error(err)
+			return nil, helper.ParseMongoError(err)
+		}
+
+		return nil, domain.NewNotFoundError("todo not found", mongo.ErrNoDocuments)
+	}
+
+	row := struct {
+		domain.Todo `bson:",inline"`
+		Subtasks    []domain.TodoNode `bson:"subtasks"`
+	}{}
+
+	if err := cur.Decode(&row); err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	// the depth of $graphLookup starts at 0 for the direct subtasks
+	for i := range row.Subtasks {
+		row.Subtasks[i].Depth++
+	}
+
+	slices.SortFunc(row.Subtasks, func(a, b domain.TodoNode) int {
+		if res := cmp.Compare(a.Depth, b.Depth); res != 0 {
+			return res
+		}
+
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return append([]domain.TodoNode{{Todo: row.Todo}}, row.Subtasks...), nil
 }
 
 // NewMongoTodoRepository will create an object that represent the todo.Repository interface
@@ -2,16 +2,21 @@ package mongo_test
 
 import (
 	"context"
+	"os"
 	"testing"
 	"time"
 
 	"github.com/ariefsn/go-resik/app/todo/repository/mongo"
 	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
 	"github.com/ariefsn/go-resik/helper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	mdb "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var MOCK_DTO = &domain.TodoDto{
@@ -116,6 +121,21 @@ func TestCreate(t *testing.T) {
 		assert.NotNil(t, err)
 		assert.Nil(t, res)
 	})
+
+	mt.Run("Failed - Duplicate", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Nil(t, res)
+	})
 }
 
 func TestGet(t *testing.T) {
@@ -135,13 +155,34 @@ func TestGet(t *testing.T) {
 		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: len(MOCK_DATA_LIST)}}))
 		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, mockResultBsonD...))
 
-		res, total, err := mockRepo.Get(context.TODO(), nil, 0, 10)
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, res)
 		assert.EqualValues(t, len(MOCK_DATA_LIST), total)
 	})
 
+	mt.Run("Success With Projection", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, bson.D{{Key: "_id", Value: "1"}, {Key: "isCompleted", Value: true}}))
+
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10, "isCompleted")
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, []domain.Todo{{ID: "1", IsCompleted: true}}, res)
+
+		t.GetStartedEvent()
+		pipeline, _ := t.GetStartedEvent().Command.Lookup("pipeline").Array().Values()
+		project := pipeline[len(pipeline)-1].Document().Lookup("$project").Document()
+
+		assert.EqualValues(t, 1, project.Lookup("_id").AsInt64())
+		assert.EqualValues(t, 1, project.Lookup("isCompleted").AsInt64())
+	})
+
 	mt.Run("Success With Filter", func(t *mtest.T) {
 		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
 
@@ -149,11 +190,47 @@ func TestGet(t *testing.T) {
 		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
 		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, mockResultBsonD...))
 
-		res, total, err := mockRepo.Get(context.TODO(), bson.M{"title": "Title 1"}, 0, 10)
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("title", domain.FoContains, "Title 1"), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.EqualValues(t, 1, total)
+	})
+
+	mt.Run("Success With Filter Groups", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, mockResultBsonD...))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.And(
+			domain.Where("isCompleted", domain.FoEq, true),
+			domain.Where("createdAt", domain.FoGte, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)),
+			domain.Or(
+				domain.Where("id", domain.FoIn, []interface{}{"1", "2"}),
+				domain.Not(domain.Where("title", domain.FoStartWith, "Title")),
+			),
+		), nil, 0, 10)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, res)
 		assert.EqualValues(t, 1, total)
+
+		match := t.GetStartedEvent().Command.Lookup("pipeline").Array().Index(0).Value().Document().Lookup("$match", "$and").Array()
+		values, _ := match.Values()
+
+		assert.Equal(t, 3, len(values))
+		assert.Equal(t, true, values[0].Document().Lookup("isCompleted", "$eq").Boolean())
+		assert.Equal(t, time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC), values[1].Document().Lookup("audit.createdAt", "$gte").Time().UTC())
+
+		ors, _ := values[2].Document().Lookup("$or").Array().Values()
+
+		assert.Equal(t, 2, len(ors))
+		assert.NotNil(t, ors[0].Document().Lookup("_id", "$in").Array())
+		pattern, _ := ors[1].Document().Lookup("$nor").Array().Index(0).Value().Document().Lookup("title").Regex()
+
+		assert.Equal(t, "^Title", pattern)
 	})
 
 	mt.Run("Failed - CountDocuments", func(t *mtest.T) {
@@ -165,7 +242,7 @@ func TestGet(t *testing.T) {
 			Message: "some error",
 		}))
 
-		res, total, err := mockRepo.Get(context.TODO(), bson.M{"title": "Title 3"}, 0, 10)
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("title", domain.FoContains, "Title 3"), nil, 0, 10)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, []domain.Todo{}, res)
@@ -183,7 +260,37 @@ func TestGet(t *testing.T) {
 			Message: "some error",
 		}))
 
-		res, total, err := mockRepo.Get(context.TODO(), bson.M{"title": "Title 3"}, 0, 10)
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("title", domain.FoContains, "Title 3"), nil, 0, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	mt.Run("Failed - Cursor", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: len(MOCK_DATA_LIST)}}))
+		// the first batch is read, fetching the next one fails
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, mockResultBsonD[0]))
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	mt.Run("Failed - Decode", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, bson.D{{Key: "_id", Value: "1"}, {Key: "title", Value: 1}}))
+
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
 
 		assert.NotNil(t, err)
 		assert.Equal(t, []domain.Todo{}, res)
@@ -191,6 +298,165 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetByCursor(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mockResultBsonD := []bson.D{}
+
+	for _, v := range MOCK_DATA_LIST {
+		b, _ := helper.ToBsonD(v)
+		mockResultBsonD = append(mockResultBsonD, *b)
+	}
+
+	sort := []domain.Sort{{Field: "title", Order: domain.SortDesc}}
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, mockResultBsonD...))
+
+		res, hasMore, err := mockRepo.GetByCursor(context.TODO(), nil, sort, &domain.Cursor{
+			Values:   []interface{}{"Title 3", "3"},
+			Backward: true,
+		}, int64(len(MOCK_DATA_LIST)-1))
+
+		assert.Nil(t, err)
+		assert.True(t, hasMore)
+		assert.Equal(t, len(MOCK_DATA_LIST)-1, len(res))
+		assert.Equal(t, MOCK_DATA_LIST[0].ID, res[len(res)-1].ID)
+
+		pipeline := t.GetStartedEvent().Command.Lookup("pipeline").Array()
+		ors, _ := pipeline.Index(0).Value().Document().Lookup("$match", "$or").Array().Values()
+
+		assert.Equal(t, 2, len(ors))
+		assert.Equal(t, "Title 3", ors[0].Document().Lookup("title", "$gt").StringValue())
+		assert.EqualValues(t, 1, pipeline.Index(1).Value().Document().Lookup("$sort", "title").AsInt64())
+		assert.EqualValues(t, -1, pipeline.Index(1).Value().Document().Lookup("$sort", "_id").AsInt64())
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   1,
+			Code:    12,
+			Message: "some error",
+		}))
+
+		res, hasMore, err := mockRepo.GetByCursor(context.TODO(), nil, sort, nil, 10)
+
+		assert.NotNil(t, err)
+		assert.False(t, hasMore)
+		assert.Equal(t, []domain.Todo{}, res)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mockResultBsonD := []bson.D{}
+
+	for i, v := range MOCK_DATA_LIST {
+		b, _ := helper.ToBsonD(v)
+		mockResultBsonD = append(mockResultBsonD, append(*b, bson.E{Key: "score", Value: 2.0 - float64(i)}))
+	}
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: len(MOCK_DATA_LIST)}}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, mockResultBsonD...))
+
+		res, total, err := mockRepo.Search(context.TODO(), "title", 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(MOCK_DATA_LIST), total)
+		assert.Equal(t, len(MOCK_DATA_LIST), len(res))
+		assert.Equal(t, MOCK_DATA_LIST[0].ID, res[0].ID)
+		assert.Equal(t, MOCK_DATA_LIST[0].Title, res[0].Title)
+		assert.Equal(t, 2.0, res[0].Score)
+
+		t.GetStartedEvent()
+		pipeline := t.GetStartedEvent().Command.Lookup("pipeline").Array()
+
+		assert.Equal(t, "title", pipeline.Index(0).Value().Document().Lookup("$match", "$text", "$search").StringValue())
+		assert.Equal(t, "textScore", pipeline.Index(1).Value().Document().Lookup("$set", "score", "$meta").StringValue())
+		assert.EqualValues(t, -1, pipeline.Index(2).Value().Document().Lookup("$sort", "score").AsInt64())
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 27, Message: "text index required for $text query"}))
+
+		res, total, err := mockRepo.Search(context.TODO(), "title", 0, 10)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Equal(t, []domain.TodoSearchResult{}, res)
+	})
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		t.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 2}, bson.E{Key: "nModified", Value: 2}))
+		t.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+
+		err := mongo.EnsureIndexes(context.TODO(), t.Client.Database("mock-db"))
+
+		assert.Nil(t, err)
+
+		indexes := t.GetStartedEvent().Command.Lookup("indexes").Array()
+		index := indexes.Index(0).Value().Document()
+
+		assert.Equal(t, "todos_search", index.Lookup("name").StringValue())
+		assert.Equal(t, "text", index.Lookup("key", "title").StringValue())
+		assert.EqualValues(t, 2, index.Lookup("weights", "title").AsInt64())
+
+		index = indexes.Index(1).Value().Document()
+
+		assert.Equal(t, "todos_overdue", index.Lookup("name").StringValue())
+		assert.Equal(t, "todos_parent", indexes.Index(2).Value().Document().Lookup("name").StringValue())
+		assert.Equal(t, "todos_list", indexes.Index(3).Value().Document().Lookup("name").StringValue())
+		assert.Equal(t, "todos_deleted", indexes.Index(4).Value().Document().Lookup("name").StringValue())
+
+		// the todos without a version get the first one
+		update := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+
+		assert.False(t, update.Lookup("q", "version", "$exists").Boolean())
+		assert.EqualValues(t, 1, update.Lookup("u", "$set", "version").AsInt64())
+		assert.True(t, update.Lookup("multi").Boolean())
+
+		// the todos without a priority get none, so the keyset of a cursor compares it
+		update = t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+
+		assert.False(t, update.Lookup("q", "priority", "$exists").Boolean())
+		assert.EqualValues(t, 0, update.Lookup("u", "$set", "priority").AsInt64())
+		assert.True(t, update.Lookup("multi").Boolean())
+	})
+
+	mt.Run("Failed - Backfill", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		err := mongo.EnsureIndexes(context.TODO(), t.Client.Database("mock-db"))
+
+		assert.NotNil(t, err)
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		err := mongo.EnsureIndexes(context.TODO(), t.Client.Database("mock-db"))
+
+		assert.NotNil(t, err)
+	})
+}
+
 func TestGetByID(t *testing.T) {
 	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
 
@@ -207,6 +473,23 @@ func TestGetByID(t *testing.T) {
 		assert.Equal(t, MOCK_DATA_SINGLE.Description, res.Description)
 	})
 
+	mt.Run("Success With Projection", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "_id", Value: "1"}, {Key: "title", Value: "Title 1"}}))
+
+		res, err := mockRepo.GetByID(context.TODO(), "1", "title", "createdAt")
+
+		assert.Nil(t, err)
+		assert.Equal(t, &domain.Todo{ID: "1", Title: "Title 1"}, res)
+
+		projection := t.GetStartedEvent().Command.Lookup("projection").Document()
+
+		assert.EqualValues(t, 1, projection.Lookup("_id").AsInt64())
+		assert.EqualValues(t, 1, projection.Lookup("title").AsInt64())
+		assert.EqualValues(t, 1, projection.Lookup("audit.createdAt").AsInt64())
+	})
+
 	mt.Run("Failed", func(t *mtest.T) {
 		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
 
@@ -214,7 +497,7 @@ func TestGetByID(t *testing.T) {
 
 		res, err := mockRepo.GetByID(context.TODO(), "1")
 
-		assert.NotNil(t, err)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
 		assert.Nil(t, res)
 	})
 }
@@ -237,12 +520,37 @@ func TestUpdate(t *testing.T) {
 			},
 		})
 
-		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE)
+		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 0)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, res)
 		assert.Equal(t, MOCK_DATA_SINGLE_UPDATED.Title, res.Title)
 		assert.Equal(t, MOCK_DATA_SINGLE_UPDATED.Description, res.Description)
+
+		// the default priority is set, the missing due date, parent and list are removed
+		command := t.GetStartedEvent().Command
+		update := command.Lookup("update").Document()
+
+		assert.EqualValues(t, domain.PriorityMedium, update.Lookup("$set", "priority").AsInt64())
+		assert.NoError(t, update.Lookup("$unset", "dueAt").Validate())
+		assert.NoError(t, update.Lookup("$unset", "parentId").Validate())
+		assert.NoError(t, update.Lookup("$unset", "listId").Validate())
+		assert.EqualValues(t, 1, update.Lookup("$inc", "version").AsInt64())
+
+		// an update never creates the todo
+		assert.Error(t, command.Lookup("upsert").Validate())
+	})
+
+	mt.Run("Success With Version", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: MOCK_DATA_SINGLE_UPDATED_BSOND}})
+
+		_, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 2)
+
+		assert.Nil(t, err)
+
+		assert.EqualValues(t, 2, t.GetStartedEvent().Command.Lookup("query", "version").AsInt64())
 	})
 
 	mt.Run("Failed", func(t *mtest.T) {
@@ -250,11 +558,377 @@ func TestUpdate(t *testing.T) {
 
 		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Message: mdb.ErrNoDocuments.Error()}))
 
-		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE)
+		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 0)
 
 		assert.NotNil(t, err)
 		assert.Nil(t, res)
 	})
+
+	mt.Run("Failed - Not Found", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		res, err := mockRepo.Update(context.TODO(), "unknown", &MOCK_DTO_UPDATE, 0)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	mt.Run("Failed - Stale Version", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, bson.D{{Key: "_id", Value: "1"}}))
+
+		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 2)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.Nil(t, res)
+	})
+
+	mt.Run("Failed - Not Found With Version", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch))
+
+		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 2)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestReplace(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success - Created", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		created := MOCK_DATA_SINGLE
+		created.Version = 1
+		createdBsonD, _ := helper.ToBsonD(created)
+
+		t.AddMockResponses(mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: 1},
+			bson.E{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: 0}, {Key: "_id", Value: "1"}}}},
+		))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, *createdBsonD))
+
+		res, isCreated, err := mockRepo.Replace(context.TODO(), "1", MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.True(t, isCreated)
+		assert.Equal(t, "1", res.ID)
+
+		// the inserted todo gets the audit data of a created one
+		update := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+
+		assert.True(t, update.Lookup("upsert").Boolean())
+		assert.Equal(t, bson.TypeNull, update.Lookup("q", "audit.deletedAt").Type)
+		assert.EqualValues(t, 1, update.Lookup("u", "$inc", "version").AsInt64())
+		assert.False(t, update.Lookup("u", "$setOnInsert", "isCompleted").Boolean())
+		assert.Equal(t, bson.TypeDateTime, update.Lookup("u", "$setOnInsert", "audit.createdAt").Type)
+		assert.Equal(t, bson.TypeDateTime, update.Lookup("u", "$set", "audit.updatedAt").Type)
+	})
+
+	mt.Run("Success - Replaced", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		// a todo stored before the versions is replaced to the version 1 yet it isn't created
+		replaced := MOCK_DATA_SINGLE_UPDATED
+		replaced.Version = 1
+		replacedBsonD, _ := helper.ToBsonD(replaced)
+
+		t.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, *replacedBsonD))
+
+		res, isCreated, err := mockRepo.Replace(context.TODO(), "1", &MOCK_DTO_UPDATE)
+
+		assert.Nil(t, err)
+		assert.False(t, isCreated)
+		assert.Equal(t, MOCK_DATA_SINGLE_UPDATED.Title, res.Title)
+	})
+
+	mt.Run("Failed - Trashed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{
+			Index:   0,
+			Code:    11000,
+			Message: "duplicate key error",
+		}))
+
+		res, isCreated, err := mockRepo.Replace(context.TODO(), "1", MOCK_DTO)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.False(t, isCreated)
+		assert.Nil(t, res)
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		res, isCreated, err := mockRepo.Replace(context.TODO(), "1", MOCK_DTO)
+
+		assert.NotNil(t, err)
+		assert.NotErrorIs(t, err, domain.ErrConflict)
+		assert.False(t, isCreated)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTags(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tagged := MOCK_DATA_SINGLE
+	tagged.Tags = []string{"home", "work"}
+
+	taggedBsonD, _ := helper.ToBsonD(tagged)
+
+	mt.Run("Success - Add", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: taggedBsonD}},
+			bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: taggedBsonD}},
+		)
+
+		res, err := mockRepo.AddTags(context.TODO(), "1", []string{"home", "work"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, tagged.Tags, res.Tags)
+
+		update := t.GetStartedEvent().Command.Lookup("update").Document()
+		values, _ := update.Lookup("$addToSet", "tags", "$each").Array().Values()
+
+		assert.Equal(t, 2, len(values))
+		assert.Equal(t, "home", values[0].StringValue())
+
+		sorting := t.GetStartedEvent().Command.Lookup("update").Document()
+
+		assert.EqualValues(t, 1, sorting.Lookup("$push", "tags", "$sort").AsInt64())
+		assert.Error(t, sorting.Lookup("$inc").Validate())
+	})
+
+	mt.Run("Success - Remove", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: MOCK_DATA_SINGLE_BSOND}})
+
+		res, err := mockRepo.RemoveTags(context.TODO(), "1", []string{"home"})
+
+		assert.Nil(t, err)
+		assert.Nil(t, res.Tags)
+
+		update := t.GetStartedEvent().Command.Lookup("update").Document()
+
+		assert.Equal(t, "home", update.Lookup("$pullAll", "tags").Array().Index(0).Value().StringValue())
+	})
+
+	mt.Run("Failed - Add", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		res, err := mockRepo.AddTags(context.TODO(), "1", []string{"home"})
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+
+	mt.Run("Failed - Add Unknown", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		res, err := mockRepo.AddTags(context.TODO(), "unknown", []string{"home"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		assert.Error(t, t.GetStartedEvent().Command.Lookup("upsert").Validate())
+	})
+
+	mt.Run("Success - Counts", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch,
+			bson.D{{Key: "_id", Value: "work"}, {Key: "count", Value: 3}},
+			bson.D{{Key: "_id", Value: "home"}, {Key: "count", Value: 1}},
+		))
+
+		res, err := mockRepo.GetTags(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.TagCount{{Tag: "work", Count: 3}, {Tag: "home", Count: 1}}, res)
+
+		pipeline := t.GetStartedEvent().Command.Lookup("pipeline").Array()
+
+		assert.Equal(t, bson.TypeNull, pipeline.Index(0).Value().Document().Lookup("$match", "audit.deletedAt").Type)
+		assert.Equal(t, "$tags", pipeline.Index(1).Value().Document().Lookup("$unwind", "path").StringValue())
+		assert.Equal(t, "$tags", pipeline.Index(2).Value().Document().Lookup("$group", "_id").StringValue())
+		assert.EqualValues(t, -1, pipeline.Index(3).Value().Document().Lookup("$sort", "count").AsInt64())
+	})
+
+	mt.Run("Failed - Counts", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		res, err := mockRepo.GetTags(context.TODO())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.TagCount{}, res)
+	})
+}
+
+func TestSubtree(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, bson.D{
+			{Key: "_id", Value: "1"},
+			{Key: "title", Value: "Title 1"},
+			{Key: "subtasks", Value: bson.A{
+				bson.D{{Key: "_id", Value: "3"}, {Key: "parentId", Value: "2"}, {Key: "depth", Value: int64(1)}},
+				bson.D{{Key: "_id", Value: "4"}, {Key: "parentId", Value: "1"}, {Key: "depth", Value: int64(0)}},
+				bson.D{{Key: "_id", Value: "2"}, {Key: "parentId", Value: "1"}, {Key: "depth", Value: int64(0)}},
+			}},
+		}))
+
+		res, err := mockRepo.GetSubtree(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		require.Equal(t, 4, len(res))
+
+		ids := []string{}
+		depths := []int64{}
+		for _, v := range res {
+			ids = append(ids, v.ID)
+			depths = append(depths, v.Depth)
+		}
+
+		assert.Equal(t, []string{"1", "2", "4", "3"}, ids)
+		assert.Equal(t, []int64{0, 1, 1, 2}, depths)
+
+		lookup := t.GetStartedEvent().Command.Lookup("pipeline").Array().Index(1).Value().Document()
+
+		assert.Equal(t, "parentId", lookup.Lookup("$graphLookup", "connectToField").StringValue())
+		assert.Equal(t, "depth", lookup.Lookup("$graphLookup", "depthField").StringValue())
+	})
+
+	mt.Run("Failed - Not Found", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch))
+
+		res, err := mockRepo.GetSubtree(context.TODO(), "1")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	mt.Run("Success - Update Status Many", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}})
+
+		err := mockRepo.UpdateStatusMany(context.TODO(), []string{"2", "3"}, true)
+
+		assert.Nil(t, err)
+
+		update := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+
+		assert.Equal(t, true, update.Lookup("u", "$set", "isCompleted").Boolean())
+		assert.Equal(t, "3", update.Lookup("q", "_id", "$in").Array().Index(1).Value().StringValue())
+		assert.Equal(t, bson.TypeNull, update.Lookup("q", "audit.deletedAt").Type)
+	})
+
+	mt.Run("Failed - Update Status Many", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		err := mockRepo.UpdateStatusMany(context.TODO(), []string{"2"}, true)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestListMany(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success - Update List Many", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}})
+
+		err := mockRepo.UpdateListMany(context.TODO(), []string{"2", "3"}, "10")
+
+		assert.Nil(t, err)
+
+		update := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+
+		assert.Equal(t, "10", update.Lookup("u", "$set", "listId").StringValue())
+		assert.Equal(t, "3", update.Lookup("q", "_id", "$in").Array().Index(1).Value().StringValue())
+		assert.Equal(t, bson.TypeNull, update.Lookup("q", "audit.deletedAt").Type)
+	})
+
+	mt.Run("Success - Update List Many Out Of Any List", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}})
+
+		err := mockRepo.UpdateListMany(context.TODO(), []string{"2", "3"}, "")
+
+		assert.Nil(t, err)
+
+		update := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+
+		assert.NoError(t, update.Lookup("u", "$unset", "listId").Validate())
+		assert.Error(t, update.Lookup("u", "$set", "listId").Validate())
+	})
+
+	mt.Run("Failed - Update List Many", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		err := mockRepo.UpdateListMany(context.TODO(), []string{"2"}, "10")
+
+		assert.NotNil(t, err)
+	})
+
+	mt.Run("Success - Delete Many", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		err := mockRepo.DeleteMany(context.TODO(), []string{"2", "3"})
+
+		assert.Nil(t, err)
+
+		update := t.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+
+		assert.Equal(t, "2", update.Lookup("q", "_id", "$in").Array().Index(0).Value().StringValue())
+		assert.Equal(t, bson.TypeNull, update.Lookup("q", "audit.deletedAt").Type)
+		assert.NoError(t, update.Lookup("u", "$set", "audit.deletedAt").Validate())
+	})
+
+	mt.Run("Failed - Delete Many", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		err := mockRepo.DeleteMany(context.TODO(), []string{"2"})
+
+		assert.NotNil(t, err)
+	})
 }
 
 func TestUpdateStatus(t *testing.T) {
@@ -275,7 +949,7 @@ func TestUpdateStatus(t *testing.T) {
 			},
 		})
 
-		res, err := mockRepo.UpdateStatus(context.TODO(), "1", true)
+		res, err := mockRepo.UpdateStatus(context.TODO(), "1", true, 0)
 
 		assert.Nil(t, err)
 		assert.NotNil(t, res)
@@ -289,7 +963,45 @@ func TestUpdateStatus(t *testing.T) {
 
 		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Message: mdb.ErrNoDocuments.Error()}))
 
-		res, err := mockRepo.UpdateStatus(context.TODO(), "1", true)
+		res, err := mockRepo.UpdateStatus(context.TODO(), "1", true, 0)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestRevert(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	state := domain.TodoState{Title: "Title 1 - Updated", Description: "Description 1 - Updated", IsCompleted: true, Tags: []string{}, Priority: domain.PriorityMedium}
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{
+			{Key: "ok", Value: 1},
+			{Key: "value", Value: MOCK_DATA_SINGLE_STATUS_UPDATED_BSOND},
+		})
+
+		res, err := mockRepo.Revert(context.TODO(), "1", state)
+
+		assert.Nil(t, err)
+		assert.True(t, res.IsCompleted)
+
+		// the state and the completion are written by a single update
+		update := t.GetStartedEvent().Command.Lookup("update").Document()
+
+		assert.Equal(t, "Title 1 - Updated", update.Lookup("$set", "title").StringValue())
+		assert.Equal(t, true, update.Lookup("$set", "isCompleted").Boolean())
+		assert.EqualValues(t, 1, update.Lookup("$inc", "version").AsInt64())
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Message: mdb.ErrNoDocuments.Error()}))
+
+		res, err := mockRepo.Revert(context.TODO(), "1", state)
 
 		assert.NotNil(t, err)
 		assert.Nil(t, res)
@@ -314,9 +1026,27 @@ func TestDelete(t *testing.T) {
 			},
 		})
 
-		err := mockRepo.Delete(context.TODO(), "1")
+		err := mockRepo.Delete(context.TODO(), "1", 0)
 
 		assert.Nil(t, err)
+
+		command := t.GetStartedEvent().Command
+
+		assert.Equal(t, bson.TypeNull, command.Lookup("query", "audit.deletedAt").Type)
+		assert.NoError(t, command.Lookup("update", "$set", "audit.deletedAt").Validate())
+		assert.EqualValues(t, 1, command.Lookup("update", "$inc", "version").AsInt64())
+	})
+
+	mt.Run("Failed - Stale Version", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, bson.D{{Key: "_id", Value: "1"}}))
+
+		err := mockRepo.Delete(context.TODO(), "1", 2)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.EqualValues(t, 2, t.GetStartedEvent().Command.Lookup("query", "version").AsInt64())
 	})
 
 	mt.Run("Failed", func(t *mtest.T) {
@@ -324,8 +1054,198 @@ func TestDelete(t *testing.T) {
 
 		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Message: mdb.ErrNoDocuments.Error()}))
 
-		err := mockRepo.Delete(context.TODO(), "1")
+		err := mockRepo.Delete(context.TODO(), "1", 0)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	deletedAt := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	trashed := MOCK_DATA_SINGLE
+	trashed.Audit = &domain.Audit{DeletedAt: &deletedAt}
+
+	trashedBsonD, _ := helper.ToBsonD(trashed)
+
+	mt.Run("Success - Get", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todos", mtest.FirstBatch, bson.D{{Key: "n", Value: 1}}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todos", mtest.FirstBatch, *trashedBsonD))
+
+		res, total, err := mockRepo.GetTrash(context.TODO(), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, deletedAt, res[0].Audit.DeletedAt.UTC())
+
+		// the count comes first
+		t.GetStartedEvent()
+		pipeline := t.GetStartedEvent().Command.Lookup("pipeline").Array()
+		match := pipeline.Index(0).Value().Document().Lookup("$match").Document()
+		sort := pipeline.Index(1).Value().Document().Lookup("$sort").Document()
+
+		assert.Equal(t, bson.TypeNull, match.Lookup("audit.deletedAt", "$ne").Type)
+
+		assert.EqualValues(t, -1, sort.Lookup("audit.deletedAt").AsInt64())
+	})
+
+	mt.Run("Failed - Get Invalid Filter", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		res, total, err := mockRepo.GetTrash(context.TODO(), domain.Where("unknown", domain.FoEq, "1"), 0, 10)
+
+		assert.Equal(t, domain.ErrKindValidation, domain.ErrorKindOf(err))
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	mt.Run("Success - Restore", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: MOCK_DATA_SINGLE_BSOND}})
+
+		res, err := mockRepo.Restore(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1", res.ID)
+
+		command := t.GetStartedEvent().Command
+
+		assert.Equal(t, bson.TypeNull, command.Lookup("query", "audit.deletedAt", "$ne").Type)
+		assert.NoError(t, command.Lookup("update", "$unset", "audit.deletedAt").Validate())
+		assert.Error(t, command.Lookup("upsert").Validate())
+	})
+
+	mt.Run("Failed - Restore", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		res, err := mockRepo.Restore(context.TODO(), "1")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	mt.Run("Success - Purge", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "n", Value: 2}})
+
+		purged, err := mockRepo.Purge(context.TODO(), deletedAt)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, purged)
+
+		deletes := t.GetStartedEvent().Command.Lookup("deletes").Array().Index(0).Value().Document()
+
+		assert.Equal(t, deletedAt, deletes.Lookup("q", "audit.deletedAt", "$lt").Time().UTC())
+	})
+
+	mt.Run("Failed - Purge", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		purged, err := mockRepo.Purge(context.TODO(), deletedAt)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, purged)
+	})
+}
+
+// TestContract runs the repository contract against a real server, it's skipped unless TEST_MONGO_URI is set
+func TestContract(t *testing.T) {
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGO_URI is not set")
+	}
+
+	client, err := mdb.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		client.Disconnect(context.TODO())
+	})
+
+	repotest.RunTodoRepositoryTests(t, func(t *testing.T) domain.TodoRepository {
+		db := client.Database("resik_test_" + primitive.NewObjectID().Hex())
+
+		t.Cleanup(func() {
+			db.Drop(context.TODO())
+		})
+
+		require.Nil(t, mongo.EnsureIndexes(context.TODO(), db))
+
+		return mongo.NewMongoTodoRepository(db)
+	})
+}
+
+// TestLegacyPriority pages through the todos stored before they had a priority, it's skipped unless TEST_MONGO_URI
+// is set
+func TestLegacyPriority(t *testing.T) {
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGO_URI is not set")
+	}
+
+	client, err := mdb.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	require.Nil(t, err)
+
+	db := client.Database("resik_test_" + primitive.NewObjectID().Hex())
+
+	t.Cleanup(func() {
+		db.Drop(context.TODO())
+		client.Disconnect(context.TODO())
+	})
+
+	_, err = db.Collection(domain.Todo{}.TableName()).InsertMany(context.TODO(), []interface{}{
+		bson.M{"_id": "1", "title": "Title 1", "description": "Description 1"},
+		bson.M{"_id": "2", "title": "Title 2", "description": "Description 2"},
+		bson.M{"_id": "3", "title": "Title 3", "description": "Description 3", "priority": 2},
+	})
+	require.Nil(t, err)
+
+	require.Nil(t, mongo.EnsureIndexes(context.TODO(), db))
+
+	repo := mongo.NewMongoTodoRepository(db)
+	sort := []domain.Sort{{Field: "priority", Order: domain.SortAsc}}
+	ids := []string{}
+	var cursor *domain.Cursor
+
+	for {
+		res, hasMore, err := repo.GetByCursor(context.TODO(), nil, sort, cursor, 1)
+		require.Nil(t, err)
+
+		for _, v := range res {
+			ids = append(ids, v.ID)
+		}
+
+		if !hasMore {
+			break
+		}
+
+		cursor = domain.NewTodoCursor(res[len(res)-1], sort, false)
+	}
+
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+}
+
+func TestDeleteFailedUnknownError(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		err := mockRepo.Delete(context.TODO(), "1", 0)
 
 		assert.NotNil(t, err)
+		assert.Equal(t, domain.ErrorKind(""), domain.ErrorKindOf(err))
 	})
 }
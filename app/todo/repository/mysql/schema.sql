@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS todos (
+  id VARCHAR(24) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL,
+  is_completed TINYINT(1) NOT NULL DEFAULT 0,
+  priority TINYINT NOT NULL DEFAULT 0,
+  due_at DATETIME(6) NULL,
+  parent_id VARCHAR(24) NULL,
+  list_id VARCHAR(24) NULL,
+  version BIGINT NOT NULL DEFAULT 1,
+  created_at DATETIME(6) NOT NULL,
+  updated_at DATETIME(6) NOT NULL,
+  deleted_at DATETIME(6) NULL,
+  PRIMARY KEY (id),
+  KEY todos_overdue (is_completed, due_at),
+  KEY todos_parent (parent_id),
+  KEY todos_list (list_id),
+  KEY todos_deleted (deleted_at),
+  FULLTEXT KEY todos_search (title, description)
+);
+
+CREATE TABLE IF NOT EXISTS todo_tags (
+  todo_id VARCHAR(24) NOT NULL,
+  tag VARCHAR(50) NOT NULL,
+  PRIMARY KEY (todo_id, tag),
+  KEY todo_tags_tag (tag),
+  CONSTRAINT todo_tags_todo FOREIGN KEY (todo_id) REFERENCES todos (id) ON DELETE CASCADE
+);
@@ -0,0 +1,1101 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/ariefsn/go-resik/logger"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Schema is the DDL of the tables used by the todo repository
+//
+//go:embed schema.sql
+var Schema string
+
+const todoColumns = "id, title, description, is_completed, priority, due_at, parent_id, list_id, version, created_at, updated_at"
+
+// errDuplicateEntry is the MySQL error number of a duplicate key
+const errDuplicateEntry = 1062
+
+// liveCondition matches the todos out of the trash, trashedCondition the ones in it
+const (
+	liveCondition    = "deleted_at IS NULL"
+	trashedCondition = "deleted_at IS NOT NULL"
+)
+
+// nextVersion increments the version of the todos, every write sets it
+const nextVersion = "version = version + 1"
+
+// tagsColumn reads the tags of the todo from the todo_tags table, they're joined by commas which a tag can't contain
+const tagsColumn = "(SELECT GROUP_CONCAT(tag ORDER BY tag SEPARATOR ',') FROM todo_tags WHERE todo_tags.todo_id = todos.id)"
+
+// fieldColumns maps the json names of the todo fields to their table columns
+var fieldColumns = map[string]string{
+	"id":          "id",
+	"title":       "title",
+	"description": "description",
+	"isCompleted": "is_completed",
+	"tags":        tagsColumn,
+	"priority":    "priority",
+	"dueAt":       "due_at",
+	"parentId":    "parent_id",
+	"listId":      "list_id",
+	"version":     "version",
+	"createdAt":   "created_at",
+	"updatedAt":   "updated_at",
+}
+
+// sqlOperators maps the comparison operators to their sql counterparts
+var sqlOperators = map[domain.FilterOperator]string{
+	domain.FoEq:  "=",
+	domain.FoNe:  "<>",
+	domain.FoGt:  ">",
+	domain.FoGte: ">=",
+	domain.FoLt:  "<",
+	domain.FoLte: "<=",
+}
+
+// likeEscaper escapes the LIKE wildcards so the string operators match their value literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type mysqlTodoRepository struct {
+	Db *sql.DB
+}
+
+// selectFields returns the projected fields in the order of the table columns, every field without a projection
+func selectFields(fields []string) []string {
+	if len(fields) == 0 {
+		return domain.TodoFields
+	}
+
+	result := []string{}
+
+	for _, v := range domain.TodoFields {
+		if slices.Contains(fields, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func selectColumns(fields []string) string {
+	columns := []string{}
+
+	for _, v := range fields {
+		if v == "tags" {
+			columns = append(columns, tagsColumn+" AS tags")
+			continue
+		}
+
+		columns = append(columns, fieldColumns[v])
+	}
+
+	return strings.Join(columns, ", ")
+}
+
+// scanTodo scans the selected fields of the todo, the extra destinations receive the columns selected after them
+func scanTodo(row rowScanner, fields []string, extra ...interface{}) (*domain.Todo, error) {
+	data := domain.Todo{}
+	audit := domain.Audit{}
+	tags := sql.NullString{}
+	dueAt := sql.NullTime{}
+	parentID := sql.NullString{}
+	listID := sql.NullString{}
+	dest := []interface{}{}
+
+	for _, v := range fields {
+		switch v {
+		case "id":
+			dest = append(dest, &data.ID)
+		case "title":
+			dest = append(dest, &data.Title)
+		case "description":
+			dest = append(dest, &data.Description)
+		case "isCompleted":
+			dest = append(dest, &data.IsCompleted)
+		case "tags":
+			dest = append(dest, &tags)
+		case "priority":
+			dest = append(dest, &data.Priority)
+		case "dueAt":
+			dest = append(dest, &dueAt)
+		case "parentId":
+			dest = append(dest, &parentID)
+		case "listId":
+			dest = append(dest, &listID)
+		case "version":
+			dest = append(dest, &data.Version)
+		case "createdAt":
+			dest = append(dest, &audit.CreatedAt)
+			data.Audit = &audit
+		case "updatedAt":
+			dest = append(dest, &audit.UpdatedAt)
+			data.Audit = &audit
+		}
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if tags.String != "" {
+		data.Tags = strings.Split(tags.String, ",")
+	}
+
+	if dueAt.Valid {
+		data.DueAt = &dueAt.Time
+	}
+
+	data.ParentID = parentID.String
+	data.ListID = listID.String
+
+	return &data, nil
+}
+
+// nullString stores an empty string as null
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func buildOrderBy(sort []domain.Sort) string {
+	orders := []string{}
+	hasID := false
+
+	for _, v := range sort {
+		column, ok := fieldColumns[v.Field]
+		if !ok {
+			continue
+		}
+
+		hasID = hasID || column == "id"
+
+		order := "ASC"
+		if v.Order == domain.SortDesc {
+			order = "DESC"
+		}
+
+		orders = append(orders, fmt.Sprintf("%s %s", column, order))
+	}
+
+	if len(orders) == 0 {
+		return ""
+	}
+
+	// keep the order of equal keys stable across pages
+	if !hasID {
+		orders = append(orders, "id ASC")
+	}
+
+	return " ORDER BY " + strings.Join(orders, ", ")
+}
+
+func buildCondition(column string, condition *domain.Filter) (string, []interface{}) {
+	if condition.Value == nil {
+		switch condition.Operator {
+		case domain.FoEq:
+			return fmt.Sprintf("%s IS NULL", column), nil
+		case domain.FoNe:
+			return fmt.Sprintf("%s IS NOT NULL", column), nil
+		}
+
+		return "", nil
+	}
+
+	switch condition.Operator {
+	case domain.FoIn, domain.FoNin:
+		values, _ := condition.Value.([]interface{})
+		if len(values) == 0 {
+			return "", nil
+		}
+
+		operator := "IN"
+		if condition.Operator == domain.FoNin {
+			operator = "NOT IN"
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+
+		return fmt.Sprintf("%s %s (%s)", column, operator, placeholders), values
+	case domain.FoRegex:
+		return fmt.Sprintf("REGEXP_LIKE(%s, ?, 'i')", column), []interface{}{condition.Value}
+	case domain.FoContains, domain.FoStartWith, domain.FoEndWith:
+		pattern := likeEscaper.Replace(strings.ToLower(fmt.Sprint(condition.Value)))
+
+		switch condition.Operator {
+		case domain.FoContains:
+			pattern = "%" + pattern + "%"
+		case domain.FoStartWith:
+			pattern = pattern + "%"
+		case domain.FoEndWith:
+			pattern = "%" + pattern
+		}
+
+		return fmt.Sprintf("LOWER(%s) LIKE ?", column), []interface{}{pattern}
+	}
+
+	operator, ok := sqlOperators[condition.Operator]
+	if !ok {
+		return "", nil
+	}
+
+	return fmt.Sprintf("%s %s ?", column, operator), []interface{}{condition.Value}
+}
+
+// buildTagsCondition matches the todos by their tags, the values are looked up in the todo_tags table
+func buildTagsCondition(condition *domain.Filter) (string, []interface{}) {
+	list := []interface{}{condition.Value}
+	if condition.Operator.IsList() {
+		list, _ = condition.Value.([]interface{})
+	}
+
+	values := []interface{}{}
+
+	for _, v := range list {
+		if !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+
+	if len(values) == 0 {
+		return "", nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	subquery := fmt.Sprintf("SELECT todo_id FROM todo_tags WHERE tag IN (%s)", placeholders)
+
+	switch condition.Operator {
+	case domain.FoEq, domain.FoIn:
+		return fmt.Sprintf("id IN (%s)", subquery), values
+	case domain.FoNe, domain.FoNin:
+		return fmt.Sprintf("id NOT IN (%s)", subquery), values
+	case domain.FoAll:
+		return fmt.Sprintf("id IN (%s GROUP BY todo_id HAVING COUNT(*) = ?)", subquery), append(values, len(values))
+	}
+
+	return "", nil
+}
+
+// buildFilter compiles the filter tree into a sql condition, an empty condition matches everything
+func buildFilter(filter *domain.Filter) (string, []interface{}, error) {
+	if filter == nil {
+		return "", nil, nil
+	}
+
+	if filter.IsCondition() {
+		column, ok := fieldColumns[filter.Field]
+		if !ok {
+			return "", nil, domain.NewValidationError(fmt.Sprintf("filter field %s is not supported", filter.Field))
+		}
+
+		var condition string
+		var args []interface{}
+
+		if filter.Field == "tags" {
+			condition, args = buildTagsCondition(filter)
+		} else {
+			condition, args = buildCondition(column, filter)
+		}
+
+		if condition == "" {
+			return "", nil, domain.NewValidationError(fmt.Sprintf("filter operator %s is not supported", filter.Operator))
+		}
+
+		// a null never differs from a value in sql, the other repositories match the todos without a value
+		if filter.Value != nil && (filter.Operator == domain.FoNe || filter.Operator == domain.FoNin) && slices.Contains(domain.TodoNullableFields, filter.Field) {
+			condition = fmt.Sprintf("(%s OR %s IS NULL)", condition, column)
+		}
+
+		return condition, args, nil
+	}
+
+	parts := []string{}
+	args := []interface{}{}
+
+	for _, v := range filter.And {
+		condition, conditionArgs, err := buildFilter(v)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if condition != "" {
+			parts = append(parts, condition)
+			args = append(args, conditionArgs...)
+		}
+	}
+
+	ors := []string{}
+	orArgs := []interface{}{}
+
+	for _, v := range filter.Or {
+		condition, conditionArgs, err := buildFilter(v)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// one of the alternatives matches everything, so does the whole group
+		if condition == "" {
+			ors = []string{}
+			orArgs = []interface{}{}
+			break
+		}
+
+		ors = append(ors, condition)
+		orArgs = append(orArgs, conditionArgs...)
+	}
+
+	if len(ors) > 0 {
+		parts = append(parts, "("+strings.Join(ors, " OR ")+")")
+		args = append(args, orArgs...)
+	}
+
+	if filter.Not != nil {
+		condition, conditionArgs, err := buildFilter(filter.Not)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if condition == "" {
+			parts = append(parts, "FALSE")
+		} else {
+			parts = append(parts, "NOT ("+condition+")")
+			args = append(args, conditionArgs...)
+		}
+	}
+
+	if len(parts) <= 1 {
+		return strings.Join(parts, ""), args, nil
+	}
+
+	return "(" + strings.Join(parts, " AND ") + ")", args, nil
+}
+
+// buildWhere compiles the filter into a where clause of the todos matching the scope, either live or trashed
+func buildWhere(scope string, filter *domain.Filter) (string, []interface{}, error) {
+	condition, args, err := buildFilter(filter)
+
+	if err != nil {
+		return "", args, err
+	}
+
+	if condition == "" {
+		return " WHERE " + scope, args, nil
+	}
+
+	return " WHERE " + scope + " AND " + condition, args, nil
+}
+
+// newTodo returns the todo of the payload as it's created with the id
+func newTodo(id string, payload *domain.TodoDto) domain.Todo {
+	return domain.Todo{
+		ID:          id,
+		Title:       payload.Title,
+		Description: payload.Description,
+		IsCompleted: false,
+		Tags:        payload.Tags,
+		Priority:    payload.PriorityValue(),
+		DueAt:       payload.DueAt,
+		ParentID:    payload.ParentID,
+		ListID:      payload.ListID,
+		Version:     1,
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	}
+}
+
+// insert adds the todo along with its tags in a transaction
+func (r *mysqlTodoRepository) insert(ctx context.Context, data domain.Todo) error {
+	return r.withTx(ctx, func(tx *sql.Tx) error {
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)", data.TableName(), todoColumns)
+
+		_, err := tx.ExecContext(ctx, query, data.ID, data.Title, data.Description, data.IsCompleted, int(data.Priority), data.DueAt, nullString(data.ParentID), nullString(data.ListID), data.Version, data.CreatedAt, data.UpdatedAt)
+
+		if err != nil {
+			return err
+		}
+
+		return insertTags(ctx, tx, data.ID, data.Tags)
+	})
+}
+
+// Create implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Create(ctx context.Context, payload *domain.TodoDto) (*domain.Todo, error) {
+	data := newTodo(primitive.NewObjectID().Hex(), payload)
+
+	if err := r.insert(ctx, data); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// whereVersion matches the live todo of the id when it has the version, zero matches any version
+func whereVersion(id string, version int64) (string, []interface{}) {
+	if version > 0 {
+		return " WHERE id = ? AND " + liveCondition + " AND version = ?", []interface{}{id, version}
+	}
+
+	return " WHERE id = ? AND " + liveCondition, []interface{}{id}
+}
+
+// notWritten tells why a write of the todo affected no row, either it's missing or in the trash, or it has another
+// version than the expected one
+func (r *mysqlTodoRepository) notWritten(ctx context.Context, id string, version int64) error {
+	if version > 0 {
+		if _, err := r.GetByID(ctx, id, "id"); err == nil {
+			return domain.NewPreconditionError("todo version doesn't match")
+		}
+	}
+
+	return domain.NewNotFoundError("todo not found", sql.ErrNoRows)
+}
+
+// Delete implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Delete(ctx context.Context, id string, version int64) error {
+	where, args := whereVersion(id, version)
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = ?, %s%s", domain.Todo{}.TableName(), nextVersion, where)
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, append([]interface{}{time.Now()}, args...)...)
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if affected == 0 {
+		return r.notWritten(ctx, id, version)
+	}
+
+	return nil
+}
+
+func (r *mysqlTodoRepository) find(ctx context.Context, where string, args []interface{}, sort []domain.Sort, skip int64, limit int64, fields []string) ([]domain.Todo, error) {
+	result := []domain.Todo{}
+	fields = selectFields(domain.ProjectFields(fields))
+
+	query := fmt.Sprintf("SELECT %s FROM %s%s%s", selectColumns(fields), domain.Todo{}.TableName(), where, buildOrderBy(sort))
+	paging, pagingArgs := helper.MySqlLimit(skip, limit)
+
+	query += paging
+	args = append(append([]interface{}{}, args...), pagingArgs...)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		logger.Error(err)
+		return result, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanTodo(rows, fields)
+		if err != nil {
+			logger.Error(err)
+			return []domain.Todo{}, err
+		}
+
+		result = append(result, *row)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return []domain.Todo{}, err
+	}
+
+	return result, nil
+}
+
+// Get implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Get(ctx context.Context, filter *domain.Filter, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	result := []domain.Todo{}
+
+	if err := filter.Validate(domain.TodoFilterFields); err != nil {
+		return result, 0, err
+	}
+
+	where, args, err := buildWhere(liveCondition, filter)
+
+	if err != nil {
+		return result, 0, err
+	}
+
+	var count int64
+
+	err = r.conn(ctx).QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s%s", domain.Todo{}.TableName(), where), args...).Scan(&count)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	result, err = r.find(ctx, where, args, sort, skip, limit, fields)
+
+	if err != nil {
+		return []domain.Todo{}, 0, err
+	}
+
+	return result, count, nil
+}
+
+// GetByCursor implements domain.TodoRepository.
+func (r *mysqlTodoRepository) GetByCursor(ctx context.Context, filter *domain.Filter, sort []domain.Sort, cursor *domain.Cursor, limit int64, fields ...string) ([]domain.Todo, bool, error) {
+	keyset := domain.And(filter, domain.KeysetFilter(sort, cursor, domain.TodoNullableFields...))
+
+	if err := keyset.Validate(domain.TodoFilterFields); err != nil {
+		return []domain.Todo{}, false, err
+	}
+
+	where, args, err := buildWhere(liveCondition, keyset)
+
+	if err != nil {
+		return []domain.Todo{}, false, err
+	}
+
+	result, err := r.find(ctx, where, args, domain.KeysetSort(sort, cursor), 0, domain.KeysetLimit(limit), domain.ProjectFields(fields, domain.KeysetFields(sort)...))
+
+	if err != nil {
+		return result, false, err
+	}
+
+	result, hasMore := domain.TrimKeyset(result, cursor, limit)
+
+	return result, hasMore, nil
+}
+
+// GetByID implements domain.TodoRepository.
+func (r *mysqlTodoRepository) GetByID(ctx context.Context, id string, fields ...string) (*domain.Todo, error) {
+	fields = selectFields(domain.ProjectFields(fields))
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ? AND %s", selectColumns(fields), domain.Todo{}.TableName(), liveCondition)
+
+	result, err := scanTodo(r.conn(ctx).QueryRowContext(ctx, query, id), fields)
+
+	if err != nil {
+		logger.Error(err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NewNotFoundError("todo not found", err)
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Search implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Search(ctx context.Context, query string, skip int64, limit int64) ([]domain.TodoSearchResult, int64, error) {
+	result := []domain.TodoSearchResult{}
+	match := "MATCH(title, description) AGAINST (?)"
+
+	var count int64
+
+	err := r.conn(ctx).QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s AND %s", domain.Todo{}.TableName(), liveCondition, match), query).Scan(&count)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	paging, args := helper.MySqlLimit(skip, limit)
+
+	rows, err := r.conn(ctx).QueryContext(
+		ctx,
+		fmt.Sprintf("SELECT %s, %s AS score FROM %s WHERE %s AND %s ORDER BY score DESC, id ASC%s", selectColumns(domain.TodoFields), match, domain.Todo{}.TableName(), liveCondition, match, paging),
+		append([]interface{}{query, query}, args...)...,
+	)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var score float64
+
+		row, err := scanTodo(rows, domain.TodoFields, &score)
+		if err != nil {
+			logger.Error(err)
+			return []domain.TodoSearchResult{}, 0, err
+		}
+
+		result = append(result, domain.TodoSearchResult{
+			Todo:  *row,
+			Score: score,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TodoSearchResult{}, 0, err
+	}
+
+	return result, count, nil
+}
+
+// conn returns what the queries run on, the transaction of the context if any
+func (r *mysqlTodoRepository) conn(ctx context.Context) helper.MySqlConn {
+	return helper.MySqlConnOf(ctx, r.Db)
+}
+
+// withTx runs fn in a transaction, it's committed unless fn fails, or in the transaction of the context
+func (r *mysqlTodoRepository) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	return helper.MySqlWithTx(ctx, r.Db, fn)
+}
+
+// insertTags adds the tags to the todo, the tags it already has are ignored
+func insertTags(ctx context.Context, tx *sql.Tx, id string, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	args := []interface{}{}
+
+	for _, v := range tags {
+		args = append(args, id, v)
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?), ", len(tags)), ", ")
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT IGNORE INTO todo_tags (todo_id, tag) VALUES %s", placeholders), args...)
+
+	return err
+}
+
+// deleteTags removes the tags from the todo, nil tags remove all of them
+func deleteTags(ctx context.Context, tx *sql.Tx, id string, tags []string) error {
+	query := "DELETE FROM todo_tags WHERE todo_id = ?"
+	args := []interface{}{id}
+
+	if tags != nil {
+		if len(tags) == 0 {
+			return nil
+		}
+
+		query += fmt.Sprintf(" AND tag IN (%s)", strings.TrimSuffix(strings.Repeat("?, ", len(tags)), ", "))
+
+		for _, v := range tags {
+			args = append(args, v)
+		}
+	}
+
+	_, err := tx.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// update sets the columns along with the version and the updated_at in a transaction, the tags are changed by fns
+// in the same one
+func (r *mysqlTodoRepository) update(ctx context.Context, id string, version int64, set string, args []interface{}, fns ...func(tx *sql.Tx) error) (*domain.Todo, error) {
+	sets := []string{nextVersion, "updated_at = ?"}
+	if set != "" {
+		sets = append([]string{set}, sets...)
+	}
+
+	err := r.withTx(ctx, func(tx *sql.Tx) error {
+		where, whereArgs := whereVersion(id, version)
+		query := fmt.Sprintf("UPDATE %s SET %s%s", domain.Todo{}.TableName(), strings.Join(sets, ", "), where)
+
+		res, err := tx.ExecContext(ctx, query, append(append(append([]interface{}{}, args...), time.Now()), whereArgs...)...)
+
+		if err != nil {
+			return err
+		}
+
+		// the version always changes, no affected row means the todo is missing, in the trash or of another version
+		affected, err := res.RowsAffected()
+
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return r.notWritten(ctx, id, version)
+		}
+
+		for _, fn := range fns {
+			if err := fn(tx); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// dtoSet returns the columns set by the payload along with their values
+func dtoSet(payload *domain.TodoDto) (string, []interface{}) {
+	return "title = ?, description = ?, priority = ?, due_at = ?, parent_id = ?, list_id = ?", []interface{}{payload.Title, payload.Description, int(payload.PriorityValue()), payload.DueAt, nullString(payload.ParentID), nullString(payload.ListID)}
+}
+
+// replaceTags replaces the tags of the todo, they're kept when there are none
+func replaceTags(ctx context.Context, id string, tags []string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		if tags == nil {
+			return nil
+		}
+
+		if err := deleteTags(ctx, tx, id, nil); err != nil {
+			return err
+		}
+
+		return insertTags(ctx, tx, id, tags)
+	}
+}
+
+// Update implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Update(ctx context.Context, id string, payload *domain.TodoDto, version int64) (*domain.Todo, error) {
+	set, args := dtoSet(payload)
+
+	return r.update(ctx, id, version, set, args, replaceTags(ctx, id, payload.Tags))
+}
+
+// Replace implements domain.TodoRepository. The row of the id is locked first, so a todo in the trash is told apart
+// from one created meanwhile by another request
+func (r *mysqlTodoRepository) Replace(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, bool, error) {
+	var result *domain.Todo
+	isCreated := false
+
+	err := helper.MySqlWithinTransaction(ctx, r.Db, func(ctx context.Context) error {
+		var deletedAt sql.NullTime
+
+		query := fmt.Sprintf("SELECT deleted_at FROM %s WHERE id = ? FOR UPDATE", domain.Todo{}.TableName())
+		err := r.conn(ctx).QueryRowContext(ctx, query, id).Scan(&deletedAt)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			data := newTodo(id, payload)
+
+			if err := r.insert(ctx, data); err != nil {
+				var mysqlErr *mysqldriver.MySQLError
+				if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateEntry {
+					return domain.NewConflictError("todo was created meanwhile, retry", err)
+				}
+
+				return err
+			}
+
+			result, isCreated = &data, true
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if deletedAt.Valid {
+			return domain.NewConflictError("todo is in the trash, restore it first")
+		}
+
+		result, err = r.Update(ctx, id, payload, 0)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, isCreated, nil
+}
+
+// UpdateStatus implements domain.TodoRepository.
+func (r *mysqlTodoRepository) UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*domain.Todo, error) {
+	return r.update(ctx, id, version, "is_completed = ?", []interface{}{isCompleted})
+}
+
+// Revert implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Revert(ctx context.Context, id string, state domain.TodoState) (*domain.Todo, error) {
+	payload := state.Dto()
+	set, args := dtoSet(payload)
+
+	return r.update(ctx, id, 0, set+", is_completed = ?", append(args, state.IsCompleted), replaceTags(ctx, id, payload.Tags))
+}
+
+// execMany runs the statement for the todos of the ids, the query ends with the placeholders of the ids
+func (r *mysqlTodoRepository) execMany(ctx context.Context, query string, args []interface{}, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args = append([]interface{}{}, args...)
+
+	for _, v := range ids {
+		args = append(args, v)
+	}
+
+	query = fmt.Sprintf("%s (%s)", query, strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "))
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, args...)
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// UpdateStatusMany implements domain.TodoRepository.
+func (r *mysqlTodoRepository) UpdateStatusMany(ctx context.Context, ids []string, isCompleted bool) error {
+	query := fmt.Sprintf("UPDATE %s SET is_completed = ?, %s, updated_at = ? WHERE %s AND id IN", domain.Todo{}.TableName(), nextVersion, liveCondition)
+
+	return r.execMany(ctx, query, []interface{}{isCompleted, time.Now()}, ids)
+}
+
+// UpdateListMany implements domain.TodoRepository.
+func (r *mysqlTodoRepository) UpdateListMany(ctx context.Context, ids []string, listID string) error {
+	query := fmt.Sprintf("UPDATE %s SET list_id = ?, %s, updated_at = ? WHERE %s AND id IN", domain.Todo{}.TableName(), nextVersion, liveCondition)
+
+	return r.execMany(ctx, query, []interface{}{nullString(listID), time.Now()}, ids)
+}
+
+// DeleteMany implements domain.TodoRepository.
+func (r *mysqlTodoRepository) DeleteMany(ctx context.Context, ids []string) error {
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = ?, %s WHERE %s AND id IN", domain.Todo{}.TableName(), nextVersion, liveCondition)
+
+	return r.execMany(ctx, query, []interface{}{time.Now()}, ids)
+}
+
+// GetTrash implements domain.TodoRepository.
+func (r *mysqlTodoRepository) GetTrash(ctx context.Context, filter *domain.Filter, skip int64, limit int64) ([]domain.Todo, int64, error) {
+	result := []domain.Todo{}
+
+	if err := filter.Validate(domain.TodoFilterFields); err != nil {
+		return result, 0, err
+	}
+
+	where, args, err := buildWhere(trashedCondition, filter)
+
+	if err != nil {
+		return result, 0, err
+	}
+
+	var count int64
+
+	err = r.conn(ctx).QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s%s", domain.Todo{}.TableName(), where), args...).Scan(&count)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	paging, pagingArgs := helper.MySqlLimit(skip, limit)
+
+	// the most recently deleted todos come first
+	rows, err := r.conn(ctx).QueryContext(
+		ctx,
+		fmt.Sprintf("SELECT %s, deleted_at FROM %s%s ORDER BY deleted_at DESC, id ASC%s", selectColumns(domain.TodoFields), domain.Todo{}.TableName(), where, paging),
+		append(append([]interface{}{}, args...), pagingArgs...)...,
+	)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var deletedAt time.Time
+
+		row, err := scanTodo(rows, domain.TodoFields, &deletedAt)
+		if err != nil {
+			logger.Error(err)
+			return []domain.Todo{}, 0, err
+		}
+
+		row.DeletedAt = &deletedAt
+
+		result = append(result, *row)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return []domain.Todo{}, 0, err
+	}
+
+	return result, count, nil
+}
+
+// Restore implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Restore(ctx context.Context, id string) (*domain.Todo, error) {
+	query := fmt.Sprintf("UPDATE %s SET deleted_at = NULL, %s WHERE id = ? AND %s", domain.Todo{}.TableName(), nextVersion, trashedCondition)
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, id)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if affected == 0 {
+		return nil, domain.NewNotFoundError("todo not found", sql.ErrNoRows)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Purge implements domain.TodoRepository.
+func (r *mysqlTodoRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	// the tags of the todos are deleted along by the foreign key
+	query := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < ?", domain.Todo{}.TableName())
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, before)
+
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		logger.Error(err)
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+// AddTags implements domain.TodoRepository.
+func (r *mysqlTodoRepository) AddTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	return r.update(ctx, id, 0, "", nil, func(tx *sql.Tx) error {
+		return insertTags(ctx, tx, id, tags)
+	})
+}
+
+// RemoveTags implements domain.TodoRepository.
+func (r *mysqlTodoRepository) RemoveTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	return r.update(ctx, id, 0, "", nil, func(tx *sql.Tx) error {
+		return deleteTags(ctx, tx, id, append([]string{}, tags...))
+	})
+}
+
+// GetTags implements domain.TodoRepository.
+func (r *mysqlTodoRepository) GetTags(ctx context.Context) ([]domain.TagCount, error) {
+	result := []domain.TagCount{}
+
+	query := fmt.Sprintf("SELECT tag, COUNT(*) AS count FROM todo_tags JOIN %[1]s ON %[1]s.id = todo_tags.todo_id WHERE %[1]s.%[2]s GROUP BY tag ORDER BY count DESC, tag ASC", domain.Todo{}.TableName(), liveCondition)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query)
+
+	if err != nil {
+		logger.Error(err)
+		return result, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row := domain.TagCount{}
+
+		if err := rows.Scan(&row.Tag, &row.Count); err != nil {
+			logger.Error(err)
+			return []domain.TagCount{}, err
+		}
+
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TagCount{}, err
+	}
+
+	return result, nil
+}
+
+// GetSubtree implements domain.TodoRepository.
+func (r *mysqlTodoRepository) GetSubtree(ctx context.Context, id string) ([]domain.TodoNode, error) {
+	result := []domain.TodoNode{}
+
+	query := fmt.Sprintf(
+		"WITH RECURSIVE subtree (todo_id, depth) AS (SELECT id, 0 FROM %[1]s WHERE id = ? AND %[3]s UNION ALL SELECT t.id, s.depth + 1 FROM %[1]s t JOIN subtree s ON t.parent_id = s.todo_id WHERE t.%[3]s) "+
+			"SELECT %[2]s, depth FROM %[1]s JOIN subtree ON subtree.todo_id = todos.id ORDER BY depth ASC, id ASC",
+		domain.Todo{}.TableName(), selectColumns(domain.TodoFields), liveCondition,
+	)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, id)
+
+	if err != nil {
+		logger.Error(err)
+		return result, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var depth int64
+
+		row, err := scanTodo(rows, domain.TodoFields, &depth)
+		if err != nil {
+			logger.Error(err)
+			return nil, err
+		}
+
+		result = append(result, domain.TodoNode{
+			Todo:  *row,
+			Depth: depth,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, domain.NewNotFoundError("todo not found", sql.ErrNoRows)
+	}
+
+	return result, nil
+}
+
+// NewMysqlTodoRepository will create an object that represent the todo.Repository interface.
+// The connection must be opened with parseTime=true so the audit columns can be scanned.
+func NewMysqlTodoRepository(database *sql.DB) domain.TodoRepository {
+	return &mysqlTodoRepository{
+		Db: database,
+	}
+}
@@ -0,0 +1,1268 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ariefsn/go-resik/app/todo/repository/mysql"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/ariefsn/go-resik/helper"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var MOCK_DTO = &domain.TodoDto{
+	Title:       "Title - 1",
+	Description: "Description - 1",
+}
+
+var MOCK_DUE_AT = time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+var MOCK_DTO_UPDATE = domain.TodoDto{
+	Title:       "Title 1 - Updated",
+	Description: "Description 1 - Updated",
+	Priority:    "high",
+	DueAt:       &MOCK_DUE_AT,
+}
+
+var MOCK_DATA_LIST = []domain.Todo{
+	{
+		ID:          "1",
+		Title:       "Title 1",
+		Description: "Description 1",
+		IsCompleted: false,
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	},
+	{
+		ID:          "2",
+		Title:       "Title 2",
+		Description: "Description 2",
+		IsCompleted: false,
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	},
+}
+
+var MOCK_DATA_SINGLE_UPDATED = domain.Todo{
+	ID:          "1",
+	Title:       "Title 1 - Updated",
+	Description: "Description 1 - Updated",
+	IsCompleted: false,
+	Audit: &domain.Audit{
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+}
+
+var MOCK_DATA_SINGLE_STATUS_UPDATED = domain.Todo{
+	ID:          "1",
+	Title:       "Title 1 - Updated",
+	Description: "Description 1 - Updated",
+	IsCompleted: true,
+	Audit: &domain.Audit{
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+}
+
+var COLUMNS = []string{"id", "title", "description", "is_completed", "tags", "priority", "due_at", "parent_id", "list_id", "version", "created_at", "updated_at"}
+
+const SELECT_COLUMNS = "id, title, description, is_completed, (SELECT GROUP_CONCAT(tag ORDER BY tag SEPARATOR ',') FROM todo_tags WHERE todo_tags.todo_id = todos.id) AS tags, priority, due_at, parent_id, list_id, version, created_at, updated_at"
+
+func nullString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+
+	return s
+}
+
+func mockRows(data ...domain.Todo) *sqlmock.Rows {
+	rows := sqlmock.NewRows(COLUMNS)
+
+	for _, v := range data {
+		rows.AddRow(v.ID, v.Title, v.Description, v.IsCompleted, strings.Join(v.Tags, ","), int(v.Priority), v.DueAt, nullString(v.ParentID), nullString(v.ListID), v.Version, v.CreatedAt, v.UpdatedAt)
+	}
+
+	return rows
+}
+
+func newMock(t *testing.T) (domain.TodoRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return mysql.NewMysqlTodoRepository(db), mock
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todos")).
+			WithArgs(sqlmock.AnyArg(), MOCK_DTO.Title, MOCK_DTO.Description, false, int(domain.PriorityMedium), nil, nil, nil, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, MOCK_DTO.Title, res.Title)
+		assert.Equal(t, MOCK_DTO.Description, res.Description)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Tags", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todos")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO todo_tags (todo_id, tag) VALUES (?, ?), (?, ?)")).
+			WithArgs(sqlmock.AnyArg(), "home", sqlmock.AnyArg(), "work").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		res, err := mockRepo.Create(context.TODO(), &domain.TodoDto{
+			Title:       MOCK_DTO.Title,
+			Description: MOCK_DTO.Description,
+			Tags:        []string{"home", "work"},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"home", "work"}, res.Tags)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todos")).WillReturnError(errors.New("some error"))
+		mock.ExpectRollback()
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(MOCK_DATA_LIST)))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT " + SELECT_COLUMNS + " FROM todos WHERE deleted_at IS NULL LIMIT ?")).
+			WithArgs(10).
+			WillReturnRows(mockRows(MOCK_DATA_LIST...))
+
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, len(MOCK_DATA_LIST), len(res))
+		assert.EqualValues(t, len(MOCK_DATA_LIST), total)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Filter", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos WHERE deleted_at IS NULL AND LOWER(title) LIKE ?")).
+			WithArgs("%title 1%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE deleted_at IS NULL AND LOWER(title) LIKE ? LIMIT ? OFFSET ?")).
+			WithArgs("%title 1%", 10, 5).
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("title", domain.FoContains, "Title 1"), nil, 5, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.EqualValues(t, 1, total)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Tags Filter", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		where := "WHERE deleted_at IS NULL AND (id IN (SELECT todo_id FROM todo_tags WHERE tag IN (?, ?) GROUP BY todo_id HAVING COUNT(*) = ?) AND id NOT IN (SELECT todo_id FROM todo_tags WHERE tag IN (?)))"
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos "+where)).
+			WithArgs("home", "work", 2, "done").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos "+where+" LIMIT ?")).
+			WithArgs("home", "work", 2, "done", 10).
+			WillReturnRows(mockRows(domain.Todo{ID: "1", Tags: []string{"home", "work"}, Audit: &domain.Audit{}}))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.And(
+			domain.Where("tags", domain.FoAll, []interface{}{"home", "work", "home"}),
+			domain.Where("tags", domain.FoNe, "done"),
+		), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, []string{"home", "work"}, res[0].Tags)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Escaped Filter", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		where := `WHERE deleted_at IS NULL AND (LOWER(title) LIKE ? AND REGEXP_LIKE(description, ?, 'i'))`
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos "+where)).
+			WithArgs(`%50\% off\_\\%`, "^desc.*$").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos "+where+" LIMIT ?")).
+			WithArgs(`%50\% off\_\\%`, "^desc.*$", 10).
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.And(
+			domain.Where("title", domain.FoContains, `50% OFF_\`),
+			domain.Where("description", domain.FoRegex, "^desc.*$"),
+		), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.EqualValues(t, 1, total)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Priority And Due Date Filter", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		where := "WHERE deleted_at IS NULL AND (priority >= ? AND (due_at <> ? OR due_at IS NULL) AND due_at IS NOT NULL)"
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos "+where)).
+			WithArgs(int64(domain.PriorityHigh), MOCK_DUE_AT).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos "+where+" LIMIT ?")).
+			WithArgs(int64(domain.PriorityHigh), MOCK_DUE_AT, 10).
+			WillReturnRows(mockRows(domain.Todo{ID: "1", Priority: domain.PriorityUrgent, DueAt: &MOCK_DUE_AT, Audit: &domain.Audit{}}))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.And(
+			domain.Where("priority", domain.FoGte, domain.PriorityHigh),
+			domain.Where("dueAt", domain.FoNe, MOCK_DUE_AT),
+			domain.Where("dueAt", domain.FoNe, nil),
+		), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, domain.PriorityUrgent, res[0].Priority)
+		assert.Equal(t, MOCK_DUE_AT, *res[0].DueAt)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Filter Groups", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		createdAt := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+		where := "WHERE deleted_at IS NULL AND (is_completed = ? AND created_at >= ? AND (id IN (?, ?) OR NOT (LOWER(title) LIKE ?)))"
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos "+where)).
+			WithArgs(true, createdAt, "1", "2", "title%").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos "+where+" LIMIT ?")).
+			WithArgs(true, createdAt, "1", "2", "title%", 10).
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.And(
+			domain.Where("isCompleted", domain.FoEq, true),
+			domain.Where("createdAt", domain.FoGte, createdAt),
+			domain.Or(
+				domain.Where("id", domain.FoIn, []interface{}{"1", "2"}),
+				domain.Not(domain.Where("title", domain.FoStartWith, "Title")),
+			),
+		), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, len(res))
+		assert.EqualValues(t, 1, total)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Sort", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(MOCK_DATA_LIST)))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE deleted_at IS NULL ORDER BY is_completed ASC, created_at DESC, id ASC LIMIT ?")).
+			WithArgs(10).
+			WillReturnRows(mockRows(MOCK_DATA_LIST...))
+
+		res, _, err := mockRepo.Get(context.TODO(), nil, []domain.Sort{
+			{Field: "isCompleted", Order: domain.SortAsc},
+			{Field: "createdAt", Order: domain.SortDesc},
+		}, 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, len(MOCK_DATA_LIST), len(res))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Count", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos")).WillReturnError(errors.New("some error"))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("title", domain.FoContains, "Title 3"), nil, 0, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	t.Run("Failed - Query", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id")).WillReturnError(errors.New("some error"))
+
+		res, total, err := mockRepo.Get(context.TODO(), domain.Where("title", domain.FoContains, "Title 3"), nil, 0, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	t.Run("Failed - Scan", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id")).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	t.Run("Failed - Rows", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(MOCK_DATA_LIST)))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id")).
+			WillReturnRows(mockRows(MOCK_DATA_LIST...).RowError(1, errors.New("some error")))
+
+		// a page cut short by the connection isn't returned as a whole one
+		res, total, err := mockRepo.Get(context.TODO(), nil, nil, 0, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+}
+
+func TestGetByCursor(t *testing.T) {
+	sort := []domain.Sort{{Field: "title", Order: domain.SortDesc}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title FROM todos WHERE deleted_at IS NULL ORDER BY title DESC, id ASC LIMIT ?")).
+			WithArgs(2).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow("1", "Title 1").AddRow("2", "Title 2"))
+
+		res, hasMore, err := mockRepo.GetByCursor(context.TODO(), nil, sort, nil, 1, "id")
+
+		assert.Nil(t, err)
+		assert.True(t, hasMore)
+		assert.Equal(t, []domain.Todo{{ID: "1", Title: "Title 1"}}, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success Backward", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE deleted_at IS NULL AND (title > ? OR (title = ? AND id < ?)) ORDER BY title ASC, id DESC LIMIT ?")).
+			WithArgs("Title 3", "Title 3", "3", 3).
+			WillReturnRows(mockRows(MOCK_DATA_LIST[1], MOCK_DATA_LIST[0]))
+
+		res, hasMore, err := mockRepo.GetByCursor(context.TODO(), nil, sort, &domain.Cursor{
+			Values:   []interface{}{"Title 3", "3"},
+			Backward: true,
+		}, 2)
+
+		assert.Nil(t, err)
+		assert.False(t, hasMore)
+		assert.Equal(t, []domain.Todo{MOCK_DATA_LIST[0], MOCK_DATA_LIST[1]}, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Nullable Key", func(t *testing.T) {
+		sort := []domain.Sort{{Field: "dueAt", Order: domain.SortDesc}}
+
+		cases := []struct {
+			name  string
+			value interface{}
+			where string
+			args  []driver.Value
+		}{
+			{
+				name:  "Due Date",
+				value: MOCK_DUE_AT,
+				where: "WHERE deleted_at IS NULL AND ((due_at < ? OR due_at IS NULL) OR (due_at = ? AND id > ?))",
+				args:  []driver.Value{MOCK_DUE_AT, MOCK_DUE_AT, "3", 2},
+			},
+			{
+				name:  "No Due Date",
+				value: nil,
+				where: "WHERE deleted_at IS NULL AND ((due_at IS NULL AND id > ?))",
+				args:  []driver.Value{"3", 2},
+			},
+		}
+
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				mockRepo, mock := newMock(t)
+
+				mock.ExpectQuery(regexp.QuoteMeta("FROM todos " + c.where + " ORDER BY due_at DESC, id ASC LIMIT ?")).
+					WithArgs(c.args...).
+					WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+				res, hasMore, err := mockRepo.GetByCursor(context.TODO(), nil, sort, &domain.Cursor{
+					Values: []interface{}{c.value, "3"},
+				}, 1)
+
+				assert.Nil(t, err)
+				assert.False(t, hasMore)
+				assert.Equal(t, 1, len(res))
+				assert.Nil(t, mock.ExpectationsWereMet())
+			})
+		}
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id")).WillReturnError(errors.New("some error"))
+
+		res, hasMore, err := mockRepo.GetByCursor(context.TODO(), nil, sort, nil, 1)
+
+		assert.NotNil(t, err)
+		assert.False(t, hasMore)
+		assert.Equal(t, []domain.Todo{}, res)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	match := "MATCH(title, description) AGAINST (?)"
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		rows := sqlmock.NewRows(append(COLUMNS, "score"))
+
+		for i, v := range MOCK_DATA_LIST {
+			rows.AddRow(v.ID, v.Title, v.Description, v.IsCompleted, nil, int(v.Priority), v.DueAt, nil, nil, v.Version, v.CreatedAt, v.UpdatedAt, 2.0-float64(i))
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos WHERE deleted_at IS NULL AND " + match)).
+			WithArgs("title").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(MOCK_DATA_LIST)))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT "+SELECT_COLUMNS+", "+match+" AS score FROM todos WHERE deleted_at IS NULL AND "+match+" ORDER BY score DESC, id ASC LIMIT ? OFFSET ?")).
+			WithArgs("title", "title", 10, 5).
+			WillReturnRows(rows)
+
+		res, total, err := mockRepo.Search(context.TODO(), "title", 5, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(MOCK_DATA_LIST), total)
+		assert.Equal(t, len(MOCK_DATA_LIST), len(res))
+		assert.Equal(t, MOCK_DATA_LIST[0].ID, res[0].ID)
+		assert.Equal(t, 2.0, res[0].Score)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos WHERE deleted_at IS NULL AND " + match)).
+			WillReturnError(errors.New("some error"))
+
+		res, total, err := mockRepo.Search(context.TODO(), "title", 0, 10)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Equal(t, []domain.TodoSearchResult{}, res)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+		res, err := mockRepo.GetByID(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, MOCK_DATA_LIST[0].Title, res.Title)
+		assert.Equal(t, MOCK_DATA_LIST[0].Description, res.Description)
+	})
+
+	t.Run("Success With Projection", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id, title, updated_at FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title", "updated_at"}).AddRow("1", "Title 1", MOCK_DATA_LIST[0].UpdatedAt))
+
+		res, err := mockRepo.GetByID(context.TODO(), "1", "updatedAt", "title", "unknown")
+
+		assert.Nil(t, err)
+		assert.Equal(t, &domain.Todo{
+			ID:    "1",
+			Title: "Title 1",
+			Audit: &domain.Audit{UpdatedAt: MOCK_DATA_LIST[0].UpdatedAt},
+		}, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnError(sql.ErrNoRows)
+
+		res, err := mockRepo.GetByID(context.TODO(), "1")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET title = ?, description = ?, priority = ?, due_at = ?, parent_id = ?, list_id = ?, version = version + 1, updated_at = ? WHERE id = ?")).
+			WithArgs(MOCK_DTO_UPDATE.Title, MOCK_DTO_UPDATE.Description, int(domain.PriorityHigh), MOCK_DUE_AT, nil, nil, sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_SINGLE_UPDATED))
+
+		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 0)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, MOCK_DATA_SINGLE_UPDATED.Title, res.Title)
+		assert.Equal(t, MOCK_DATA_SINGLE_UPDATED.Description, res.Description)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 0)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Version", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("WHERE id = ? AND deleted_at IS NULL AND version = ?")).
+			WithArgs(MOCK_DTO_UPDATE.Title, MOCK_DTO_UPDATE.Description, int(domain.PriorityHigh), MOCK_DUE_AT, nil, nil, sqlmock.AnyArg(), "1", int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_SINGLE_UPDATED))
+
+		_, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 2)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Stale Version", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+		mock.ExpectRollback()
+
+		res, err := mockRepo.Update(context.TODO(), "1", &MOCK_DTO_UPDATE, 2)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success With Tags", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET title = ?, description = ?, priority = ?, due_at = ?, parent_id = ?, list_id = ?, version = version + 1, updated_at = ? WHERE id = ?")).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todo_tags WHERE todo_id = ?")).
+			WithArgs("1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO todo_tags (todo_id, tag) VALUES (?, ?)")).
+			WithArgs("1", "work").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(domain.Todo{ID: "1", Tags: []string{"work"}, Audit: &domain.Audit{}}))
+
+		res, err := mockRepo.Update(context.TODO(), "1", &domain.TodoDto{
+			Title:       MOCK_DTO_UPDATE.Title,
+			Description: MOCK_DTO_UPDATE.Description,
+			Tags:        []string{"work"},
+		}, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"work"}, res.Tags)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Tags", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todo_tags")).WillReturnError(errors.New("some error"))
+		mock.ExpectRollback()
+
+		res, err := mockRepo.Update(context.TODO(), "1", &domain.TodoDto{Tags: []string{}}, 0)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReplace(t *testing.T) {
+	t.Run("Success - Replaced", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM todos WHERE id = ? FOR UPDATE")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(nil))
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET title = ?, description = ?, priority = ?, due_at = ?, parent_id = ?, list_id = ?, version = version + 1, updated_at = ? WHERE id = ? AND deleted_at IS NULL")).
+			WithArgs(MOCK_DTO_UPDATE.Title, MOCK_DTO_UPDATE.Description, int(domain.PriorityHigh), MOCK_DUE_AT, nil, nil, sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_SINGLE_UPDATED))
+		mock.ExpectCommit()
+
+		res, created, err := mockRepo.Replace(context.TODO(), "1", &MOCK_DTO_UPDATE)
+
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, MOCK_DATA_SINGLE_UPDATED.Title, res.Title)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Created", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM todos WHERE id = ? FOR UPDATE")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todos")).
+			WithArgs("1", MOCK_DTO.Title, MOCK_DTO.Description, false, int(domain.PriorityMedium), nil, nil, nil, 1, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		res, created, err := mockRepo.Replace(context.TODO(), "1", MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "1", res.ID)
+		assert.EqualValues(t, 1, res.Version)
+		assert.False(t, res.CreatedAt.IsZero())
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Trashed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM todos WHERE id = ? FOR UPDATE")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}).AddRow(time.Now()))
+		mock.ExpectRollback()
+
+		res, created, err := mockRepo.Replace(context.TODO(), "1", MOCK_DTO)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.Contains(t, err.Error(), "trash")
+		assert.False(t, created)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Created Meanwhile", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT deleted_at FROM todos WHERE id = ? FOR UPDATE")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"deleted_at"}))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todos")).
+			WillReturnError(&mysqldriver.MySQLError{Number: 1062, Message: "Duplicate entry '1' for key 'PRIMARY'"})
+		mock.ExpectRollback()
+
+		res, created, err := mockRepo.Replace(context.TODO(), "1", MOCK_DTO)
+
+		// the duplicate key isn't taken for a todo in the trash
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.NotContains(t, err.Error(), "trash")
+		assert.False(t, created)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin().WillReturnError(errors.New("some error"))
+
+		res, created, err := mockRepo.Replace(context.TODO(), "1", MOCK_DTO)
+
+		assert.NotNil(t, err)
+		assert.False(t, created)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTags(t *testing.T) {
+	t.Run("Success - Add", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET version = version + 1, updated_at = ? WHERE id = ?")).
+			WithArgs(sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO todo_tags (todo_id, tag) VALUES (?, ?), (?, ?)")).
+			WithArgs("1", "home", "1", "work").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT " + SELECT_COLUMNS + " FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(domain.Todo{ID: "1", Tags: []string{"home", "work"}, Audit: &domain.Audit{}}))
+
+		res, err := mockRepo.AddTags(context.TODO(), "1", []string{"home", "work"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"home", "work"}, res.Tags)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Remove", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET version = version + 1, updated_at = ? WHERE id = ?")).
+			WithArgs(sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todo_tags WHERE todo_id = ? AND tag IN (?)")).
+			WithArgs("1", "home").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(domain.Todo{ID: "1", Audit: &domain.Audit{}}))
+
+		res, err := mockRepo.RemoveTags(context.TODO(), "1", []string{"home"})
+
+		assert.Nil(t, err)
+		assert.Nil(t, res.Tags)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET version = version + 1, updated_at = ? WHERE id = ? AND deleted_at IS NULL")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		res, err := mockRepo.AddTags(context.TODO(), "1", []string{"home"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Counts", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT tag, COUNT(*) AS count FROM todo_tags JOIN todos ON todos.id = todo_tags.todo_id WHERE todos.deleted_at IS NULL GROUP BY tag ORDER BY count DESC, tag ASC")).
+			WillReturnRows(sqlmock.NewRows([]string{"tag", "count"}).AddRow("work", 3).AddRow("home", 1))
+
+		res, err := mockRepo.GetTags(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.TagCount{{Tag: "work", Count: 3}, {Tag: "home", Count: 1}}, res)
+	})
+
+	t.Run("Failed - Counts", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT tag")).WillReturnError(errors.New("some error"))
+
+		res, err := mockRepo.GetTags(context.TODO())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.TagCount{}, res)
+	})
+
+	t.Run("Failed - Scan", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT tag")).
+			WillReturnRows(sqlmock.NewRows([]string{"tag", "count"}).AddRow("work", "many"))
+
+		res, err := mockRepo.GetTags(context.TODO())
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.TagCount{}, res)
+	})
+}
+
+func TestUpdateStatus(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET is_completed = ?, version = version + 1, updated_at = ? WHERE id = ?")).
+			WithArgs(true, sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_SINGLE_STATUS_UPDATED))
+
+		res, err := mockRepo.UpdateStatus(context.TODO(), "1", true, 0)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Equal(t, MOCK_DATA_SINGLE_STATUS_UPDATED.Title, res.Title)
+		assert.Equal(t, MOCK_DATA_SINGLE_STATUS_UPDATED.Description, res.Description)
+		assert.Equal(t, MOCK_DATA_SINGLE_STATUS_UPDATED.IsCompleted, res.IsCompleted)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos")).WillReturnError(errors.New("some error"))
+		mock.ExpectRollback()
+
+		res, err := mockRepo.UpdateStatus(context.TODO(), "1", true, 0)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestRevert(t *testing.T) {
+	state := domain.TodoState{Title: "Title 1", Description: "Description 1", IsCompleted: true, Tags: []string{"work"}, Priority: domain.PriorityHigh}
+
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET title = ?, description = ?, priority = ?, due_at = ?, parent_id = ?, list_id = ?, is_completed = ?, version = version + 1, updated_at = ? WHERE id = ?")).
+			WithArgs("Title 1", "Description 1", int(domain.PriorityHigh), nil, nil, nil, true, sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todo_tags WHERE todo_id = ?")).
+			WithArgs("1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO todo_tags (todo_id, tag) VALUES (?, ?)")).
+			WithArgs("1", "work").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(domain.Todo{ID: "1", Title: "Title 1", IsCompleted: true, Tags: []string{"work"}, Audit: &domain.Audit{}}))
+
+		res, err := mockRepo.Revert(context.TODO(), "1", state)
+
+		assert.Nil(t, err)
+		assert.True(t, res.IsCompleted)
+		assert.Equal(t, []string{"work"}, res.Tags)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Within Transaction", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mockRepo := mysql.NewMysqlTodoRepository(db)
+		database := &helper.Database{Driver: helper.DbDriverMysql, Mysql: db}
+
+		// the write joins the transaction of the context and the todo is read back within it
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todo_tags WHERE todo_id = ?")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO todo_tags")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(domain.Todo{ID: "1", Title: "Title 1", IsCompleted: true, Tags: []string{"work"}, Audit: &domain.Audit{}}))
+		mock.ExpectCommit()
+
+		err := database.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			_, err := mockRepo.Revert(ctx, "1", state)
+			return err
+		})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		res, err := mockRepo.Revert(context.TODO(), "1", state)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL")).
+			WithArgs(sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := mockRepo.Delete(context.TODO(), "1", 0)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL")).
+			WithArgs(sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := mockRepo.Delete(context.TODO(), "1", 0)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("Failed - Stale Version", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET deleted_at = ?, version = version + 1 WHERE id = ? AND deleted_at IS NULL AND version = ?")).
+			WithArgs(sqlmock.AnyArg(), "1", int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT id FROM todos WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("1"))
+
+		err := mockRepo.Delete(context.TODO(), "1", 2)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestTrash(t *testing.T) {
+	deletedAt := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success - Get", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		rows := sqlmock.NewRows(append(COLUMNS, "deleted_at"))
+		for _, v := range MOCK_DATA_LIST {
+			rows.AddRow(v.ID, v.Title, v.Description, v.IsCompleted, nil, int(v.Priority), v.DueAt, nullString(v.ParentID), nullString(v.ListID), v.Version, v.CreatedAt, v.UpdatedAt, deletedAt)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos WHERE deleted_at IS NOT NULL AND title = ?")).
+			WithArgs("Title 1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(MOCK_DATA_LIST)))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT "+SELECT_COLUMNS+", deleted_at FROM todos WHERE deleted_at IS NOT NULL AND title = ? ORDER BY deleted_at DESC, id ASC LIMIT ?")).
+			WithArgs("Title 1", 10).
+			WillReturnRows(rows)
+
+		res, total, err := mockRepo.GetTrash(context.TODO(), domain.Where("title", domain.FoEq, "Title 1"), 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, len(MOCK_DATA_LIST), total)
+		assert.Equal(t, deletedAt, *res[0].DeletedAt)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Get", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todos")).WillReturnError(errors.New("some error"))
+
+		res, total, err := mockRepo.GetTrash(context.TODO(), nil, 0, 10)
+
+		assert.NotNil(t, err)
+		assert.Equal(t, []domain.Todo{}, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	t.Run("Success - Restore", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET deleted_at = NULL, version = version + 1 WHERE id = ? AND deleted_at IS NOT NULL")).
+			WithArgs("1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todos WHERE id = ? AND deleted_at IS NULL")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+		res, err := mockRepo.Restore(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, MOCK_DATA_LIST[0].ID, res.ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Restore", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET deleted_at = NULL")).
+			WithArgs("1").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		res, err := mockRepo.Restore(context.TODO(), "1")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Success - Purge", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todos WHERE deleted_at < ?")).
+			WithArgs(deletedAt).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		purged, err := mockRepo.Purge(context.TODO(), deletedAt)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, purged)
+	})
+
+	t.Run("Failed - Purge", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todos")).WillReturnError(errors.New("some error"))
+
+		purged, err := mockRepo.Purge(context.TODO(), deletedAt)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, purged)
+	})
+}
+
+func TestSubtree(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		child := MOCK_DATA_LIST[1]
+		child.ParentID = "1"
+
+		rows := sqlmock.NewRows(append(COLUMNS, "depth"))
+		for i, v := range []domain.Todo{MOCK_DATA_LIST[0], child} {
+			rows.AddRow(v.ID, v.Title, v.Description, v.IsCompleted, nil, int(v.Priority), v.DueAt, nullString(v.ParentID), nullString(v.ListID), v.Version, v.CreatedAt, v.UpdatedAt, i)
+		}
+
+		mock.ExpectQuery(regexp.QuoteMeta("WITH RECURSIVE subtree (todo_id, depth) AS (SELECT id, 0 FROM todos WHERE id = ? AND deleted_at IS NULL UNION ALL SELECT t.id, s.depth + 1 FROM todos t JOIN subtree s ON t.parent_id = s.todo_id WHERE t.deleted_at IS NULL) SELECT " + SELECT_COLUMNS + ", depth FROM todos JOIN subtree ON subtree.todo_id = todos.id ORDER BY depth ASC, id ASC")).
+			WithArgs("1").
+			WillReturnRows(rows)
+
+		res, err := mockRepo.GetSubtree(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		require.Equal(t, 2, len(res))
+		assert.Equal(t, "1", res[0].ID)
+		assert.EqualValues(t, 0, res[0].Depth)
+		assert.Equal(t, "1", res[1].ParentID)
+		assert.EqualValues(t, 1, res[1].Depth)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("WITH RECURSIVE subtree")).
+			WithArgs("2").
+			WillReturnRows(sqlmock.NewRows(append(COLUMNS, "depth")))
+
+		res, err := mockRepo.GetSubtree(context.TODO(), "2")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Success - Update Status Many", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET is_completed = ?, version = version + 1, updated_at = ? WHERE deleted_at IS NULL AND id IN (?, ?)")).
+			WithArgs(true, sqlmock.AnyArg(), "2", "3").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := mockRepo.UpdateStatusMany(context.TODO(), []string{"2", "3"}, true)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Update Status Many", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET is_completed = ?")).WillReturnError(errors.New("some error"))
+
+		err := mockRepo.UpdateStatusMany(context.TODO(), []string{"2"}, true)
+
+		assert.NotNil(t, err)
+	})
+}
+
+func TestListMany(t *testing.T) {
+	t.Run("Success - Update List Many", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET list_id = ?, version = version + 1, updated_at = ? WHERE deleted_at IS NULL AND id IN (?, ?)")).
+			WithArgs("10", sqlmock.AnyArg(), "2", "3").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := mockRepo.UpdateListMany(context.TODO(), []string{"2", "3"}, "10")
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Update List Many Out Of Any List", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET list_id = ?, version = version + 1, updated_at = ? WHERE deleted_at IS NULL AND id IN (?)")).
+			WithArgs(nil, sqlmock.AnyArg(), "2").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := mockRepo.UpdateListMany(context.TODO(), []string{"2"}, "")
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Delete Many", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET deleted_at = ?, version = version + 1 WHERE deleted_at IS NULL AND id IN (?, ?)")).
+			WithArgs(sqlmock.AnyArg(), "2", "3").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		err := mockRepo.DeleteMany(context.TODO(), []string{"2", "3"})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Delete Many Without Ids", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		err := mockRepo.DeleteMany(context.TODO(), []string{})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Delete Many", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos SET deleted_at = ?")).WillReturnError(errors.New("some error"))
+
+		err := mockRepo.DeleteMany(context.TODO(), []string{"2"})
+
+		assert.NotNil(t, err)
+	})
+}
+
+// TestContract runs the repository contract against a real server, it's skipped unless TEST_MYSQL_DSN is set.
+// The DSN must enable parseTime, e.g. user:password@tcp(localhost:3306)/resik_test?parseTime=true
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+
+	db := helper.MySqlClient(dsn)
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	require.Nil(t, helper.MySqlExecSchema(context.TODO(), db, mysql.Schema))
+
+	repotest.RunTodoRepositoryTests(t, func(t *testing.T) domain.TodoRepository {
+		_, err := db.Exec("DELETE FROM todos")
+		require.Nil(t, err)
+
+		return mysql.NewMysqlTodoRepository(db)
+	})
+}
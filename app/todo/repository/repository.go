@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ariefsn/go-resik/app/todo/repository/memory"
+	"github.com/ariefsn/go-resik/app/todo/repository/mongo"
+	"github.com/ariefsn/go-resik/app/todo/repository/mysql"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+)
+
+// NewTodoRepository will create the domain.TodoRepository matching the driver of the given database
+func NewTodoRepository(db *helper.Database) (domain.TodoRepository, error) {
+	switch db.Driver {
+	case helper.DbDriverMongo:
+		return mongo.NewMongoTodoRepository(db.Mongo), nil
+	case helper.DbDriverMysql:
+		return mysql.NewMysqlTodoRepository(db.Mysql), nil
+	case helper.DbDriverMemory:
+		return memory.NewMemoryTodoRepository(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported db driver: %s", db.Driver)
+}
+
+// EnsureTodoSchema will create the mongo indexes or the mysql tables the domain.TodoRepository needs
+func EnsureTodoSchema(ctx context.Context, db *helper.Database) error {
+	switch db.Driver {
+	case helper.DbDriverMongo:
+		return mongo.EnsureIndexes(ctx, db.Mongo)
+	case helper.DbDriverMysql:
+		return helper.MySqlExecSchema(ctx, db.Mysql, mysql.Schema)
+	}
+
+	return nil
+}
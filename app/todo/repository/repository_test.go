@@ -0,0 +1,91 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ariefsn/go-resik/app/todo/repository"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestNewTodoRepository(t *testing.T) {
+	cases := []struct {
+		name    string
+		success bool
+		db      *helper.Database
+	}{
+		{
+			name:    "Mongo",
+			success: true,
+			db: &helper.Database{
+				Driver: helper.DbDriverMongo,
+				Mongo:  &mongo.Database{},
+			},
+		},
+		{
+			name:    "Mysql",
+			success: true,
+			db: &helper.Database{
+				Driver: helper.DbDriverMysql,
+				Mysql:  &sql.DB{},
+			},
+		},
+		{
+			name:    "Memory",
+			success: true,
+			db: &helper.Database{
+				Driver: helper.DbDriverMemory,
+			},
+		},
+		{
+			name:    "Unsupported",
+			success: false,
+			db: &helper.Database{
+				Driver: "unknown",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := repository.NewTodoRepository(c.db)
+
+			if c.success {
+				assert.Nil(t, err)
+				assert.NotNil(t, res)
+			} else {
+				assert.NotNil(t, err)
+				assert.Nil(t, res)
+			}
+		})
+	}
+}
+
+func TestEnsureTodoSchema(t *testing.T) {
+	err := repository.EnsureTodoSchema(context.TODO(), &helper.Database{
+		Driver: helper.DbDriverMemory,
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestEnsureTodoSchemaMysql(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS todos")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS todo_tags")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repository.EnsureTodoSchema(context.TODO(), &helper.Database{
+		Driver: helper.DbDriverMysql,
+		Mysql:  db,
+	})
+
+	assert.Nil(t, err)
+	assert.Nil(t, mock.ExpectationsWereMet())
+}
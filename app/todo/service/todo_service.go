@@ -2,47 +2,598 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
 
 	"github.com/ariefsn/go-resik/domain"
 )
 
 type todoService struct {
-	todoRepo domain.TodoRepository
+	todoRepo       domain.TodoRepository
+	todoListRepo   domain.TodoListRepository
+	revisionRepo   domain.TodoRevisionRepository
+	transactor     domain.Transactor
+	trashRetention time.Duration
+}
+
+// normalizeDto copies the payload with its tags normalized, the caller's payload is left as is
+func normalizeDto(payload *domain.TodoDto) *domain.TodoDto {
+	dto := *payload
+	dto.Tags = domain.NormalizeTags(payload.Tags)
+
+	return &dto
+}
+
+// checkParent checks the parent of the todo exists and isn't the todo or one of its subtasks, the id is empty for a new todo
+func (s *todoService) checkParent(ctx context.Context, id string, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+
+	if _, err := s.todoRepo.GetByID(ctx, parentID, "id"); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewFieldValidationError(domain.FieldError{
+				Field:   "parentId",
+				Rule:    "exists",
+				Message: "parentId must be an existing todo",
+			})
+		}
+
+		return err
+	}
+
+	if id == "" {
+		return nil
+	}
+
+	nodes, err := s.todoRepo.GetSubtree(ctx, id)
+
+	if err != nil {
+		return err
+	}
+
+	if slices.ContainsFunc(nodes, func(v domain.TodoNode) bool { return v.ID == parentID }) {
+		return domain.NewFieldValidationError(domain.FieldError{
+			Field:   "parentId",
+			Rule:    "acyclic",
+			Message: "parentId must not be the todo or one of its subtasks",
+		})
+	}
+
+	return nil
+}
+
+// checkList checks the list of the todo exists, an empty one keeps the todo out of any list
+func (s *todoService) checkList(ctx context.Context, listID string) error {
+	if listID == "" {
+		return nil
+	}
+
+	if _, err := s.todoListRepo.GetByID(ctx, listID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewFieldValidationError(domain.FieldError{
+				Field:   "listId",
+				Rule:    "exists",
+				Message: "listId must be an existing todo list",
+			})
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// checkVersion checks the todo has the version the write expects, zero matches any version. The repository checks
+// it again as it writes, checking it first fails before any other todo is changed along.
+func checkVersion(t domain.Todo, version int64) error {
+	if version > 0 && t.Version != version {
+		return domain.NewPreconditionError("todo version doesn't match")
+	}
+
+	return nil
+}
+
+// record keeps the revisions of the changes once they're made
+func (s *todoService) record(ctx context.Context, revisions ...domain.TodoRevision) error {
+	_, err := s.revisionRepo.CreateMany(ctx, revisions)
+
+	return err
+}
+
+// change makes the change of the todo and records it in one transaction, the todo is read first so the revision has
+// its previous state and apply can check the change against it
+func (s *todoService) change(ctx context.Context, id string, version int64, action domain.RevisionAction, apply func(ctx context.Context, before *domain.Todo) (*domain.Todo, error)) (*domain.Todo, error) {
+	var result *domain.Todo
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.todoRepo.GetByID(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(*before, version); err != nil {
+			return err
+		}
+
+		res, err := apply(ctx, before)
+
+		if err != nil {
+			return err
+		}
+
+		if err := s.record(ctx, domain.NewTodoRevision(ctx, action, before, *res)); err != nil {
+			return err
+		}
+
+		result = res
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddTags implements domain.TodoService.
+func (s *todoService) AddTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	tags = domain.NormalizeTags(tags)
+
+	return s.change(ctx, id, 0, domain.RevisionUpdate, func(ctx context.Context, before *domain.Todo) (*domain.Todo, error) {
+		// the payload is limited on its own, the tags the todo already has count as well
+		if len(domain.NormalizeTags(append(slices.Clone(before.Tags), tags...))) > domain.TodoMaxTags {
+			return nil, domain.NewValidationError(fmt.Sprintf("a todo can't have more than %d tags", domain.TodoMaxTags))
+		}
+
+		return s.todoRepo.AddTags(ctx, id, tags)
+	})
+}
+
+// RemoveTags implements domain.TodoService.
+func (s *todoService) RemoveTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	return s.change(ctx, id, 0, domain.RevisionUpdate, func(ctx context.Context, _ *domain.Todo) (*domain.Todo, error) {
+		return s.todoRepo.RemoveTags(ctx, id, domain.NormalizeTags(tags))
+	})
+}
+
+// GetTags implements domain.TodoService.
+func (s *todoService) GetTags(ctx context.Context) ([]domain.TagCount, error) {
+	return s.todoRepo.GetTags(ctx)
 }
 
 // Create implements domain.TodoService.
 func (s *todoService) Create(ctx context.Context, payload *domain.TodoDto) (*domain.Todo, error) {
-	return s.todoRepo.Create(ctx, payload)
+	if err := s.checkParent(ctx, "", payload.ParentID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkList(ctx, payload.ListID); err != nil {
+		return nil, err
+	}
+
+	var result *domain.Todo
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		res, err := s.todoRepo.Create(ctx, normalizeDto(payload))
+
+		if err != nil {
+			return err
+		}
+
+		if err := s.record(ctx, domain.NewTodoRevision(ctx, domain.RevisionCreate, nil, *res)); err != nil {
+			return err
+		}
+
+		result = res
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // Delete implements domain.TodoService.
-func (s *todoService) Delete(ctx context.Context, id string) error {
-	return s.todoRepo.Delete(ctx, id)
+func (s *todoService) Delete(ctx context.Context, id string, version int64) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// the subtasks would be left with a missing parent
+		_, total, err := s.todoRepo.Get(ctx, domain.Where("parentId", domain.FoEq, id), nil, 0, 1, "id")
+
+		if err != nil {
+			return err
+		}
+
+		if total > 0 {
+			return domain.NewConflictError("todo has subtasks, delete or move them first")
+		}
+
+		before, err := s.todoRepo.GetByID(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(*before, version); err != nil {
+			return err
+		}
+
+		if err := s.todoRepo.Delete(ctx, id, version); err != nil {
+			return err
+		}
+
+		// the revision keeps the todo as it was deleted
+		return s.record(ctx, domain.NewTodoRevision(ctx, domain.RevisionDelete, before, *before))
+	})
 }
 
 // Get implements domain.TodoService.
-func (s *todoService) Get(ctx context.Context, filter interface{}, skip int64, limit int64) ([]domain.Todo, int64, error) {
-	return s.todoRepo.Get(ctx, filter, skip, limit)
+func (s *todoService) Get(ctx context.Context, filter *domain.Filter, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	return s.todoRepo.Get(ctx, filter, sort, skip, limit, fields...)
+}
+
+// GetByCursor implements domain.TodoService.
+func (s *todoService) GetByCursor(ctx context.Context, filter *domain.Filter, sort []domain.Sort, cursor *domain.Cursor, limit int64, fields ...string) (*domain.TodoPage, error) {
+	items, hasMore, err := s.todoRepo.GetByCursor(ctx, filter, sort, cursor, limit, fields...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.TodoPage{
+		Items: items,
+	}
+
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	backward := cursor != nil && cursor.Backward
+
+	// reading forward there is a previous page once a cursor is given, reading backward there is always a next one
+	if hasMore || backward {
+		page.Next = domain.NewTodoCursor(items[len(items)-1], sort, false)
+	}
+
+	if (hasMore && backward) || (cursor != nil && !backward) {
+		page.Prev = domain.NewTodoCursor(items[0], sort, true)
+	}
+
+	return page, nil
+}
+
+// GetOverdue implements domain.TodoService.
+func (s *todoService) GetOverdue(ctx context.Context, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	// the most overdue todos come first unless another order is given
+	if len(sort) == 0 {
+		sort = []domain.Sort{{Field: "dueAt", Order: domain.SortAsc}}
+	}
+
+	return s.todoRepo.Get(ctx, domain.OverdueFilter(time.Now()), sort, skip, limit, fields...)
 }
 
 // GetByID implements domain.TodoService.
-func (s *todoService) GetByID(ctx context.Context, id string) (*domain.Todo, error) {
-	return s.todoRepo.GetByID(ctx, id)
+func (s *todoService) GetByID(ctx context.Context, id string, fields ...string) (*domain.Todo, error) {
+	return s.todoRepo.GetByID(ctx, id, fields...)
+}
+
+// Search implements domain.TodoService.
+func (s *todoService) Search(ctx context.Context, query string, skip int64, limit int64) ([]domain.TodoSearchResult, int64, error) {
+	terms := domain.SearchTerms(query)
+
+	if len(terms) == 0 {
+		return nil, 0, domain.NewFieldValidationError(domain.FieldError{
+			Field:   "q",
+			Rule:    "required",
+			Message: "q is required",
+		})
+	}
+
+	res, total, err := s.todoRepo.Search(ctx, query, skip, limit)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, v := range res {
+		highlights := map[string]string{}
+
+		if text, ok := domain.Highlight(v.Title, terms); ok {
+			highlights["title"] = text
+		}
+
+		if text, ok := domain.Highlight(v.Description, terms); ok {
+			highlights["description"] = text
+		}
+
+		res[i].Highlights = highlights
+	}
+
+	return res, total, nil
 }
 
 // Update implements domain.TodoService.
-func (s *todoService) Update(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, error) {
-	return s.todoRepo.Update(ctx, id, payload)
+func (s *todoService) Update(ctx context.Context, id string, payload *domain.TodoDto, version int64) (*domain.Todo, error) {
+	if err := s.checkParent(ctx, id, payload.ParentID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkList(ctx, payload.ListID); err != nil {
+		return nil, err
+	}
+
+	return s.change(ctx, id, version, domain.RevisionUpdate, func(ctx context.Context, _ *domain.Todo) (*domain.Todo, error) {
+		return s.todoRepo.Update(ctx, id, normalizeDto(payload), version)
+	})
+}
+
+// Replace implements domain.TodoService.
+func (s *todoService) Replace(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, bool, error) {
+	var result *domain.Todo
+	var created bool
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// a missing todo is created, it has no subtasks the parent could be one of
+		before, err := s.todoRepo.GetByID(ctx, id)
+		subtreeID := id
+
+		if errors.Is(err, domain.ErrNotFound) {
+			before, subtreeID = nil, ""
+		} else if err != nil {
+			return err
+		}
+
+		if err := s.checkParent(ctx, subtreeID, payload.ParentID); err != nil {
+			return err
+		}
+
+		if err := s.checkList(ctx, payload.ListID); err != nil {
+			return err
+		}
+
+		res, isCreated, err := s.todoRepo.Replace(ctx, id, normalizeDto(payload))
+
+		if err != nil {
+			return err
+		}
+
+		action := domain.RevisionUpdate
+		if isCreated {
+			action, before = domain.RevisionCreate, nil
+		}
+
+		if err := s.record(ctx, domain.NewTodoRevision(ctx, action, before, *res)); err != nil {
+			return err
+		}
+
+		result, created = res, isCreated
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, created, nil
+}
+
+// GetSubtree implements domain.TodoService.
+func (s *todoService) GetSubtree(ctx context.Context, id string) (*domain.TodoNode, error) {
+	nodes, err := s.todoRepo.GetSubtree(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NestTodoNodes(nodes), nil
+}
+
+// Complete implements domain.TodoService.
+func (s *todoService) Complete(ctx context.Context, id string, cascade bool, version int64) (*domain.TodoCompletion, error) {
+	result := &domain.TodoCompletion{}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		nodes, err := s.todoRepo.GetSubtree(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		if err := checkVersion(nodes[0].Todo, version); err != nil {
+			return err
+		}
+
+		// the todo is written first, a version changed meanwhile fails before any subtask is completed
+		res, err := s.todoRepo.UpdateStatus(ctx, id, true, version)
+
+		if err != nil {
+			return err
+		}
+
+		revisions := []domain.TodoRevision{domain.NewTodoRevision(ctx, domain.RevisionStatus, &nodes[0].Todo, *res)}
+		incomplete := []string{}
+
+		for _, v := range nodes[1:] {
+			if !v.IsCompleted {
+				incomplete = append(incomplete, v.ID)
+			}
+		}
+
+		// the incomplete subtasks are reported unless they're completed along
+		if len(incomplete) > 0 && !cascade {
+			result.IncompleteSubtasks = incomplete
+		}
+
+		if len(incomplete) > 0 && cascade {
+			if err := s.todoRepo.UpdateStatusMany(ctx, incomplete, true); err != nil {
+				return err
+			}
+
+			for _, v := range nodes[1:] {
+				if !v.IsCompleted {
+					after := v.Todo
+					after.IsCompleted = true
+
+					revisions = append(revisions, domain.NewTodoRevision(ctx, domain.RevisionStatus, &v.Todo, after))
+				}
+			}
+
+			result.CompletedSubtasks = incomplete
+		}
+
+		if err := s.record(ctx, revisions...); err != nil {
+			return err
+		}
+
+		result.Todo = *res
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 // UpdateStatus implements domain.TodoService.
-func (s *todoService) UpdateStatus(ctx context.Context, id string, isCompleted bool) (*domain.Todo, error) {
-	return s.todoRepo.UpdateStatus(ctx, id, isCompleted)
+func (s *todoService) UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*domain.Todo, error) {
+	return s.change(ctx, id, version, domain.RevisionStatus, func(ctx context.Context, _ *domain.Todo) (*domain.Todo, error) {
+		return s.todoRepo.UpdateStatus(ctx, id, isCompleted, version)
+	})
+}
+
+// GetTrash implements domain.TodoService.
+func (s *todoService) GetTrash(ctx context.Context, skip int64, limit int64) ([]domain.Todo, int64, error) {
+	return s.todoRepo.GetTrash(ctx, nil, skip, limit)
+}
+
+// Restore implements domain.TodoService.
+func (s *todoService) Restore(ctx context.Context, id string) (*domain.Todo, error) {
+	var result *domain.Todo
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		trash, _, err := s.todoRepo.GetTrash(ctx, domain.Where("id", domain.FoEq, id), 0, 1)
+
+		if err != nil {
+			return err
+		}
+
+		if len(trash) == 0 {
+			return domain.NewNotFoundError("todo not found in trash")
+		}
+
+		// the subtask would be restored under a missing parent
+		if parentID := trash[0].ParentID; parentID != "" {
+			if _, err := s.todoRepo.GetByID(ctx, parentID, "id"); err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					return domain.NewConflictError("parent todo is deleted, restore it first")
+				}
+
+				return err
+			}
+		}
+
+		res, err := s.todoRepo.Restore(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		// the list may have been deleted meanwhile, the todo is restored out of any list then
+		if res.ListID != "" {
+			if _, err := s.todoListRepo.GetByID(ctx, res.ListID); err != nil {
+				if !errors.Is(err, domain.ErrNotFound) {
+					return err
+				}
+
+				if err := s.todoRepo.UpdateListMany(ctx, []string{id}, ""); err != nil {
+					return err
+				}
+
+				res.ListID = ""
+			}
+		}
+
+		if err := s.record(ctx, domain.NewTodoRevision(ctx, domain.RevisionRestore, &trash[0], *res)); err != nil {
+			return err
+		}
+
+		result = res
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Purge implements domain.TodoService.
+func (s *todoService) Purge(ctx context.Context) (int64, error) {
+	return s.todoRepo.Purge(ctx, time.Now().Add(-s.trashRetention))
+}
+
+// GetHistory implements domain.TodoService.
+func (s *todoService) GetHistory(ctx context.Context, id string, skip int64, limit int64) ([]domain.TodoRevision, int64, error) {
+	res, total, err := s.revisionRepo.Get(ctx, id, skip, limit)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for i, v := range res {
+		res[i].Changes = v.State.Diff(v.Before)
+	}
+
+	return res, total, nil
+}
+
+// Revert implements domain.TodoService.
+func (s *todoService) Revert(ctx context.Context, id string, revisionID string) (*domain.Todo, error) {
+	revision, err := s.revisionRepo.GetByID(ctx, id, revisionID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the parent and the list of the revision may be gone by now
+	if err := s.checkParent(ctx, id, revision.State.ParentID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkList(ctx, revision.State.ListID); err != nil {
+		return nil, err
+	}
+
+	// the state is written at once, the completion included, so the revert is a single version
+	return s.change(ctx, id, 0, domain.RevisionRevert, func(ctx context.Context, _ *domain.Todo) (*domain.Todo, error) {
+		return s.todoRepo.Revert(ctx, id, revision.State)
+	})
 }
 
 // NewTodoService will create new an todoService object representation of domain.TodoService interface
-func NewTodoService(todoRepo domain.TodoRepository) domain.TodoService {
+// the todo list repository checks the lists the todos are put in, every change of the todos is recorded in the
+// revision repository within the transaction of the transactor making it and the deleted todos are purged once
+// they're in the trash for longer than the trash retention
+func NewTodoService(todoRepo domain.TodoRepository, todoListRepo domain.TodoListRepository, revisionRepo domain.TodoRevisionRepository, transactor domain.Transactor, trashRetention time.Duration) domain.TodoService {
 	return &todoService{
-		todoRepo: todoRepo,
+		todoRepo:       todoRepo,
+		todoListRepo:   todoListRepo,
+		revisionRepo:   revisionRepo,
+		transactor:     transactor,
+		trashRetention: trashRetention,
 	}
 }
@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -13,6 +14,28 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+type txKey struct{}
+
+// transactor runs the units in a context telling they're in a transaction
+type transactor struct{}
+
+func (transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+// inTx matches the contexts of a transaction, the writes must be made in one along with their revisions
+var inTx = mock.MatchedBy(func(ctx context.Context) bool {
+	return ctx.Value(txKey{}) == true
+})
+
+// newRevisionRepo returns a revision repository keeping every revision, the history tests set their own expectations
+func newRevisionRepo() *mocks.TodoRevisionRepository {
+	repo := new(mocks.TodoRevisionRepository)
+	repo.On("CreateMany", mock.Anything, mock.Anything).Return(nil, nil)
+
+	return repo
+}
+
 func TestCreate(t *testing.T) {
 	mockTodoRepo := new(mocks.TodoRepository)
 
@@ -35,7 +58,7 @@ func TestCreate(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockTodoRepo.On("Create", mock.Anything, payload).Return(result, nil).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
 		res, err := svc.Create(context.TODO(), payload)
 
 		assert.Nil(t, err)
@@ -46,7 +69,7 @@ func TestCreate(t *testing.T) {
 	t.Run("Failed", func(t *testing.T) {
 		mockTodoRepo.On("Create", mock.Anything, payload).Return(nil, mockError).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
 		res, err := svc.Create(context.TODO(), payload)
 
 		assert.NotNil(t, err)
@@ -84,10 +107,10 @@ func TestGet(t *testing.T) {
 	mockError := errors.New("some error")
 
 	t.Run("Success", func(t *testing.T) {
-		mockTodoRepo.On("Get", context.TODO(), nil, int64(0), int64(10)).Return(mockResult, int64(len(mockResult)), nil).Once()
+		mockTodoRepo.On("Get", context.TODO(), (*domain.Filter)(nil), []domain.Sort(nil), int64(0), int64(10)).Return(mockResult, int64(len(mockResult)), nil).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
-		res, total, err := svc.Get(context.TODO(), nil, int64(0), int64(10))
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.Get(context.TODO(), nil, nil, int64(0), int64(10))
 
 		assert.Nil(t, err)
 		assert.Equal(t, len(mockResult), len(res))
@@ -95,10 +118,10 @@ func TestGet(t *testing.T) {
 	})
 
 	t.Run("Failed", func(t *testing.T) {
-		mockTodoRepo.On("Get", context.TODO(), nil, int64(0), int64(10)).Return([]domain.Todo{}, int64(0), mockError).Once()
+		mockTodoRepo.On("Get", context.TODO(), (*domain.Filter)(nil), []domain.Sort(nil), int64(0), int64(10)).Return([]domain.Todo{}, int64(0), mockError).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
-		res, total, err := svc.Get(context.TODO(), nil, int64(0), int64(10))
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.Get(context.TODO(), nil, nil, int64(0), int64(10))
 
 		assert.NotNil(t, err)
 		assert.Equal(t, 0, len(res))
@@ -106,6 +129,311 @@ func TestGet(t *testing.T) {
 	})
 }
 
+func TestGetByCursor(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+
+	mockResult := []domain.Todo{
+		{ID: "1", Title: "Title 1", Audit: &domain.Audit{}},
+		{ID: "2", Title: "Title 2", Audit: &domain.Audit{}},
+	}
+
+	sort := []domain.Sort{{Field: "title", Order: domain.SortAsc}}
+	first := domain.NewTodoCursor(mockResult[0], sort, true)
+	last := domain.NewTodoCursor(mockResult[1], sort, false)
+
+	cases := []struct {
+		name    string
+		cursor  *domain.Cursor
+		hasMore bool
+		next    *domain.Cursor
+		prev    *domain.Cursor
+	}{
+		{name: "Success - First Page", cursor: nil, hasMore: true, next: last, prev: nil},
+		{name: "Success - Only Page", cursor: nil, hasMore: false, next: nil, prev: nil},
+		{name: "Success - Forward", cursor: &domain.Cursor{Values: []interface{}{"Title 0", "0"}}, hasMore: false, next: nil, prev: first},
+		{name: "Success - Backward", cursor: &domain.Cursor{Values: []interface{}{"Title 3", "3"}, Backward: true}, hasMore: true, next: last, prev: first},
+		{name: "Success - Backward To First Page", cursor: &domain.Cursor{Values: []interface{}{"Title 3", "3"}, Backward: true}, hasMore: false, next: last, prev: nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mockTodoRepo.On("GetByCursor", context.TODO(), (*domain.Filter)(nil), sort, c.cursor, int64(2)).Return(mockResult, c.hasMore, nil).Once()
+
+			svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+			res, err := svc.GetByCursor(context.TODO(), nil, sort, c.cursor, 2)
+
+			assert.Nil(t, err)
+			assert.Equal(t, mockResult, res.Items)
+			assert.Equal(t, c.next, res.Next)
+			assert.Equal(t, c.prev, res.Prev)
+		})
+	}
+
+	t.Run("Failed", func(t *testing.T) {
+		mockTodoRepo.On("GetByCursor", context.TODO(), (*domain.Filter)(nil), sort, (*domain.Cursor)(nil), int64(2)).Return([]domain.Todo{}, false, errors.New("some error")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.GetByCursor(context.TODO(), nil, sort, nil, 2)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestSearch(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+
+	mockResult := []domain.TodoSearchResult{
+		{
+			Todo: domain.Todo{
+				ID:          "1",
+				Title:       "Buy groceries",
+				Description: "Milk, bread and butter",
+			},
+			Score: 2,
+		},
+		{
+			Todo: domain.Todo{
+				ID:          "2",
+				Title:       "Walk the dog",
+				Description: "Buy a leash",
+			},
+			Score: 1,
+		},
+	}
+	mockError := errors.New("some error")
+
+	t.Run("Success", func(t *testing.T) {
+		mockTodoRepo.On("Search", mock.Anything, "BUY bread", int64(0), int64(10)).Return(mockResult, int64(2), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.Search(context.TODO(), "BUY bread", 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.Equal(t, map[string]string{
+			"title":       "<mark>Buy</mark> groceries",
+			"description": "Milk, <mark>bread</mark> and butter",
+		}, res[0].Highlights)
+		assert.Equal(t, map[string]string{
+			"description": "<mark>Buy</mark> a leash",
+		}, res[1].Highlights)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockTodoRepo.On("Search", mock.Anything, "buy", int64(0), int64(10)).Return(nil, int64(0), mockError).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.Search(context.TODO(), "buy", 0, 10)
+
+		assert.Equal(t, mockError, err)
+		assert.EqualValues(t, 0, total)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Failed - Empty Query", func(t *testing.T) {
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.Search(context.TODO(), " ,. ", 0, 10)
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		assert.EqualValues(t, 0, total)
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNumberOfCalls(t, "Search", 2)
+	})
+
+	t.Run("Success - Escaped Markup", func(t *testing.T) {
+		mockTodoRepo.On("Search", mock.Anything, "img", int64(0), int64(10)).Return([]domain.TodoSearchResult{
+			{
+				Todo: domain.Todo{
+					ID:          "3",
+					Title:       `<img src=x onerror=alert(1)>`,
+					Description: `Fix the "img" & <b>logo</b>`,
+				},
+				Score: 1,
+			},
+		}, int64(1), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, _, err := svc.Search(context.TODO(), "img", 0, 10)
+
+		// only the marks are markup, the rest of the text is escaped
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{
+			"title":       "&lt;<mark>img</mark> src=x onerror=alert(1)&gt;",
+			"description": "Fix the &#34;<mark>img</mark>&#34; &amp; &lt;b&gt;logo&lt;/b&gt;",
+		}, res[0].Highlights)
+	})
+}
+
+func TestGetOverdue(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+
+	due := time.Now().Add(-time.Hour)
+	mockResult := []domain.Todo{
+		{
+			ID:       "1",
+			Title:    "Title 1",
+			Priority: domain.PriorityHigh,
+			DueAt:    &due,
+		},
+	}
+	mockError := errors.New("some error")
+
+	overdue := mock.MatchedBy(func(filter *domain.Filter) bool {
+		if len(filter.And) != 2 || filter.And[1].Field != "dueAt" || filter.And[1].Operator != domain.FoLt {
+			return false
+		}
+
+		now, _ := filter.And[1].Value.(time.Time)
+
+		return assert.ObjectsAreEqual(domain.Where("isCompleted", domain.FoEq, false), filter.And[0]) && time.Since(now) < time.Minute
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		mockTodoRepo.On("Get", mock.Anything, overdue, []domain.Sort{{Field: "dueAt", Order: domain.SortAsc}}, int64(0), int64(10)).Return(mockResult, int64(1), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.GetOverdue(context.TODO(), nil, 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, mockResult, res)
+	})
+
+	t.Run("Success - Sort", func(t *testing.T) {
+		sort := []domain.Sort{{Field: "priority", Order: domain.SortDesc}}
+
+		mockTodoRepo.On("Get", mock.Anything, overdue, sort, int64(0), int64(10), "title").Return(mockResult, int64(1), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, _, err := svc.GetOverdue(context.TODO(), sort, 0, 10, "title")
+
+		assert.Nil(t, err)
+		assert.Equal(t, mockResult, res)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockTodoRepo.On("Get", mock.Anything, overdue, mock.Anything, int64(0), int64(10)).Return(nil, int64(0), mockError).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.GetOverdue(context.TODO(), nil, 0, 10)
+
+		assert.Equal(t, mockError, err)
+		assert.EqualValues(t, 0, total)
+		assert.Nil(t, res)
+	})
+}
+
+func TestTags(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+
+	result := &domain.Todo{
+		ID:   "1",
+		Tags: []string{"home", "work"},
+	}
+	mockError := errors.New("some error")
+
+	t.Run("Success - Create", func(t *testing.T) {
+		payload := &domain.TodoDto{
+			Title:       "Title 1",
+			Description: "Description 1",
+			Tags:        []string{" Work", "home", "work "},
+		}
+
+		mockTodoRepo.On("Create", mock.Anything, &domain.TodoDto{
+			Title:       "Title 1",
+			Description: "Description 1",
+			Tags:        []string{"home", "work"},
+		}).Return(result, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, result, res)
+		assert.Equal(t, []string{" Work", "home", "work "}, payload.Tags)
+	})
+
+	t.Run("Success - Add", func(t *testing.T) {
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(&domain.Todo{ID: "1"}, nil).Once()
+		mockTodoRepo.On("AddTags", mock.Anything, "1", []string{"home", "work"}).Return(result, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.AddTags(context.TODO(), "1", []string{"WORK", "Home"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, result, res)
+	})
+
+	t.Run("Failed - Add Over The Limit", func(t *testing.T) {
+		full := &domain.Todo{ID: "1", Tags: []string{}}
+		for i := 0; i < domain.TodoMaxTags; i++ {
+			full.Tags = append(full.Tags, fmt.Sprintf("tag-%02d", i))
+		}
+
+		mockTodoRepo.On("GetByID", inTx, "1").Return(full, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+
+		// the tags the todo already has don't count twice
+		res, err := svc.AddTags(context.TODO(), "1", []string{"home", "Tag-00"})
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNotCalled(t, "AddTags", mock.Anything, "1", []string{"home", "tag-00"})
+	})
+
+	t.Run("Success - Add Existing At The Limit", func(t *testing.T) {
+		full := &domain.Todo{ID: "1", Tags: []string{}}
+		for i := 0; i < domain.TodoMaxTags; i++ {
+			full.Tags = append(full.Tags, fmt.Sprintf("tag-%02d", i))
+		}
+
+		mockTodoRepo.On("GetByID", inTx, "1").Return(full, nil).Once()
+		mockTodoRepo.On("AddTags", inTx, "1", []string{"tag-00"}).Return(full, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.AddTags(context.TODO(), "1", []string{"Tag-00"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, full, res)
+	})
+
+	t.Run("Success - Remove", func(t *testing.T) {
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(result, nil).Once()
+		mockTodoRepo.On("RemoveTags", mock.Anything, "1", []string{"home"}).Return(result, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.RemoveTags(context.TODO(), "1", []string{"Home"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, result, res)
+	})
+
+	t.Run("Success - Counts", func(t *testing.T) {
+		counts := []domain.TagCount{{Tag: "home", Count: 1}}
+
+		mockTodoRepo.On("GetTags", mock.Anything).Return(counts, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.GetTags(context.TODO())
+
+		assert.Nil(t, err)
+		assert.Equal(t, counts, res)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(result, nil).Once()
+		mockTodoRepo.On("AddTags", mock.Anything, "1", []string{"home"}).Return(nil, mockError).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.AddTags(context.TODO(), "1", []string{"home"})
+
+		assert.Equal(t, mockError, err)
+		assert.Nil(t, res)
+	})
+}
+
 func TestGetByID(t *testing.T) {
 	mockTodoRepo := new(mocks.TodoRepository)
 
@@ -125,7 +453,7 @@ func TestGetByID(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockTodoRepo.On("GetByID", context.TODO(), "1").Return(mockResult, nil).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
 		res, err := svc.GetByID(context.TODO(), "1")
 
 		assert.Nil(t, err)
@@ -135,7 +463,7 @@ func TestGetByID(t *testing.T) {
 	t.Run("Failed", func(t *testing.T) {
 		mockTodoRepo.On("GetByID", context.TODO(), "").Return(nil, mockError).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
 		res, err := svc.GetByID(context.TODO(), "")
 
 		assert.NotNil(t, err)
@@ -165,20 +493,22 @@ func TestUpdate(t *testing.T) {
 	mockError := errors.New("some error")
 
 	t.Run("Success", func(t *testing.T) {
-		mockTodoRepo.On("Update", context.TODO(), mockResult.ID, mockDto).Return(mockResult, nil).Once()
+		mockTodoRepo.On("GetByID", inTx, mockResult.ID).Return(&domain.Todo{ID: "1", Title: "Title 1"}, nil).Once()
+		mockTodoRepo.On("Update", inTx, mockResult.ID, mockDto, int64(0)).Return(mockResult, nil).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
-		res, err := svc.Update(context.TODO(), "1", mockDto)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Update(context.TODO(), "1", mockDto, 0)
 
 		assert.Nil(t, err)
 		assert.Equal(t, mockResult, res)
 	})
 
 	t.Run("Failed", func(t *testing.T) {
-		mockTodoRepo.On("Update", context.TODO(), mockResult.ID, mockDto).Return(nil, mockError).Once()
+		mockTodoRepo.On("GetByID", inTx, mockResult.ID).Return(&domain.Todo{ID: "1", Title: "Title 1"}, nil).Once()
+		mockTodoRepo.On("Update", inTx, mockResult.ID, mockDto, int64(0)).Return(nil, mockError).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
-		res, err := svc.Update(context.TODO(), "1", mockDto)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Update(context.TODO(), "1", mockDto, 0)
 
 		assert.NotNil(t, err)
 		assert.Nil(t, res)
@@ -202,10 +532,11 @@ func TestUpdateStatus(t *testing.T) {
 	mockError := errors.New("some error")
 
 	t.Run("Success", func(t *testing.T) {
-		mockTodoRepo.On("UpdateStatus", context.TODO(), mockResult.ID, true).Return(mockResult, nil).Once()
+		mockTodoRepo.On("GetByID", inTx, mockResult.ID).Return(&domain.Todo{ID: "1", Title: "Title 1"}, nil).Once()
+		mockTodoRepo.On("UpdateStatus", inTx, mockResult.ID, true, int64(0)).Return(mockResult, nil).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
-		res, err := svc.UpdateStatus(context.TODO(), "1", true)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.UpdateStatus(context.TODO(), "1", true, 0)
 
 		assert.Nil(t, err)
 		assert.Equal(t, mockResult, res)
@@ -213,10 +544,11 @@ func TestUpdateStatus(t *testing.T) {
 	})
 
 	t.Run("Failed", func(t *testing.T) {
-		mockTodoRepo.On("UpdateStatus", context.TODO(), mockResult.ID, true).Return(nil, mockError).Once()
+		mockTodoRepo.On("GetByID", inTx, mockResult.ID).Return(&domain.Todo{ID: "1", Title: "Title 1"}, nil).Once()
+		mockTodoRepo.On("UpdateStatus", inTx, mockResult.ID, true, int64(0)).Return(nil, mockError).Once()
 
-		svc := service.NewTodoService(mockTodoRepo)
-		res, err := svc.UpdateStatus(context.TODO(), "1", true)
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.UpdateStatus(context.TODO(), "1", true, 0)
 
 		assert.NotNil(t, err)
 		assert.Nil(t, res)
@@ -227,22 +559,627 @@ func TestDelete(t *testing.T) {
 	mockTodoRepo := new(mocks.TodoRepository)
 
 	mockError := errors.New("some error")
+	subtasks := domain.Where("parentId", domain.FoEq, "1")
+
+	t.Run("Success", func(t *testing.T) {
+		mockTodoRepo.On("Get", inTx, subtasks, []domain.Sort(nil), int64(0), int64(1), "id").Return([]domain.Todo{}, int64(0), nil).Once()
+		mockTodoRepo.On("GetByID", inTx, "1").Return(&domain.Todo{ID: "1"}, nil).Once()
+		mockTodoRepo.On("Delete", inTx, "1", int64(0)).Return(nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		err := svc.Delete(context.TODO(), "1", 0)
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockTodoRepo.On("Get", inTx, subtasks, []domain.Sort(nil), int64(0), int64(1), "id").Return([]domain.Todo{}, int64(0), nil).Once()
+		mockTodoRepo.On("GetByID", inTx, "1").Return(&domain.Todo{ID: "1"}, nil).Once()
+		mockTodoRepo.On("Delete", inTx, "1", int64(0)).Return(mockError).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		err := svc.Delete(context.TODO(), "1", 0)
+
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Failed - Has Subtasks", func(t *testing.T) {
+		mockTodoRepo.On("Get", inTx, subtasks, []domain.Sort(nil), int64(0), int64(1), "id").Return([]domain.Todo{{ID: "2"}}, int64(1), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		err := svc.Delete(context.TODO(), "1", 0)
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockTodoRepo.AssertNumberOfCalls(t, "Delete", 2)
+	})
+}
+
+func TestSubtree(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+
+	nodes := []domain.TodoNode{
+		{Todo: domain.Todo{ID: "1"}},
+		{Todo: domain.Todo{ID: "2", ParentID: "1"}, Depth: 1},
+		{Todo: domain.Todo{ID: "3", ParentID: "1", IsCompleted: true}, Depth: 1},
+		{Todo: domain.Todo{ID: "4", ParentID: "2"}, Depth: 2},
+	}
+	completed := &domain.Todo{ID: "1", IsCompleted: true}
+	mockError := errors.New("some error")
+
+	t.Run("Success - Get", func(t *testing.T) {
+		mockTodoRepo.On("GetSubtree", mock.Anything, "1").Return(nodes, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.GetSubtree(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, &domain.TodoNode{
+			Todo: domain.Todo{ID: "1"},
+			Subtasks: []domain.TodoNode{
+				{
+					Todo:  domain.Todo{ID: "2", ParentID: "1"},
+					Depth: 1,
+					Subtasks: []domain.TodoNode{
+						{Todo: domain.Todo{ID: "4", ParentID: "2"}, Depth: 2, Subtasks: []domain.TodoNode{}},
+					},
+				},
+				{Todo: domain.Todo{ID: "3", ParentID: "1", IsCompleted: true}, Depth: 1, Subtasks: []domain.TodoNode{}},
+			},
+		}, res)
+	})
+
+	t.Run("Failed - Get", func(t *testing.T) {
+		mockTodoRepo.On("GetSubtree", mock.Anything, "5").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.GetSubtree(context.TODO(), "5")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Success - Complete Reports", func(t *testing.T) {
+		mockTodoRepo.On("GetSubtree", mock.Anything, "1").Return(nodes, nil).Once()
+		mockTodoRepo.On("UpdateStatus", mock.Anything, "1", true, int64(0)).Return(completed, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Complete(context.TODO(), "1", false, 0)
+
+		assert.Nil(t, err)
+		assert.True(t, res.IsCompleted)
+		assert.Equal(t, []string{"2", "4"}, res.IncompleteSubtasks)
+		assert.Nil(t, res.CompletedSubtasks)
+		mockTodoRepo.AssertNotCalled(t, "UpdateStatusMany", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Complete Cascades", func(t *testing.T) {
+		mockTodoRepo.On("GetSubtree", inTx, "1").Return(nodes, nil).Once()
+		mockTodoRepo.On("UpdateStatusMany", inTx, []string{"2", "4"}, true).Return(nil).Once()
+		mockTodoRepo.On("UpdateStatus", inTx, "1", true, int64(0)).Return(completed, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Complete(context.TODO(), "1", true, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2", "4"}, res.CompletedSubtasks)
+		assert.Nil(t, res.IncompleteSubtasks)
+	})
+
+	t.Run("Failed - Complete Cascades", func(t *testing.T) {
+		mockTodoRepo.On("GetSubtree", mock.Anything, "1").Return(nodes, nil).Once()
+		mockTodoRepo.On("UpdateStatus", mock.Anything, "1", true, int64(0)).Return(completed, nil).Once()
+		mockTodoRepo.On("UpdateStatusMany", mock.Anything, []string{"2", "4"}, true).Return(mockError).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Complete(context.TODO(), "1", true, 0)
+
+		assert.Equal(t, mockError, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Failed - Complete Cascades Stale Version", func(t *testing.T) {
+		mockTodoRepo.On("GetSubtree", mock.Anything, "1").Return(nodes, nil).Once()
+		mockTodoRepo.On("UpdateStatus", mock.Anything, "1", true, int64(0)).Return(nil, domain.NewPreconditionError("todo version doesn't match")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Complete(context.TODO(), "1", true, 0)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.Nil(t, res)
+		// the subtasks are left as they are
+		mockTodoRepo.AssertNumberOfCalls(t, "UpdateStatusMany", 2)
+	})
+
+	t.Run("Success - Parent", func(t *testing.T) {
+		payload := &domain.TodoDto{Title: "Title 5", Description: "Description 5", ParentID: "4"}
+
+		mockTodoRepo.On("GetByID", mock.Anything, "4", "id").Return(&domain.Todo{ID: "4"}, nil).Once()
+		mockTodoRepo.On("Create", mock.Anything, payload).Return(&domain.Todo{ID: "5", ParentID: "4"}, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Create(context.TODO(), payload)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "4", res.ParentID)
+	})
+
+	cases := []struct {
+		name     string
+		id       string
+		parentID string
+		rule     string
+	}{
+		{name: "Failed - Missing Parent", id: "", parentID: "9", rule: "exists"},
+		{name: "Failed - Own Parent", id: "1", parentID: "1", rule: "acyclic"},
+		{name: "Failed - Subtask Parent", id: "1", parentID: "4", rule: "acyclic"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			payload := &domain.TodoDto{Title: "Title 1", Description: "Description 1", ParentID: c.parentID}
+
+			if c.rule == "exists" {
+				mockTodoRepo.On("GetByID", mock.Anything, c.parentID, "id").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+			} else {
+				mockTodoRepo.On("GetByID", mock.Anything, c.parentID, "id").Return(&domain.Todo{ID: c.parentID}, nil).Once()
+				mockTodoRepo.On("GetSubtree", mock.Anything, c.id).Return(nodes, nil).Once()
+			}
+
+			svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+
+			var err error
+			if c.id == "" {
+				_, err = svc.Create(context.TODO(), payload)
+			} else {
+				_, err = svc.Update(context.TODO(), c.id, payload, 0)
+			}
+
+			var domainErr *domain.Error
+
+			assert.True(t, errors.As(err, &domainErr))
+			assert.Equal(t, "parentId", domainErr.Fields[0].Field)
+			assert.Equal(t, c.rule, domainErr.Fields[0].Rule)
+		})
+	}
+}
+
+func TestList(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+	mockTodoListRepo := new(mocks.TodoListRepository)
 
 	t.Run("Success", func(t *testing.T) {
-		mockTodoRepo.On("Delete", context.TODO(), "1").Return(nil).Once()
+		payload := &domain.TodoDto{Title: "Title 1", Description: "Description 1", ListID: "10"}
 
-		svc := service.NewTodoService(mockTodoRepo)
-		err := svc.Delete(context.TODO(), "1")
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&domain.TodoList{ID: "10"}, nil).Once()
+		mockTodoRepo.On("Create", mock.Anything, payload).Return(&domain.Todo{ID: "1", ListID: "10"}, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Create(context.TODO(), payload)
 
 		assert.Nil(t, err)
+		assert.Equal(t, "10", res.ListID)
+	})
+
+	t.Run("Failed - Missing List", func(t *testing.T) {
+		payload := &domain.TodoDto{Title: "Title 1", Description: "Description 1", ListID: "11"}
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "11").Return(nil, domain.NewNotFoundError("todo list not found")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Update(context.TODO(), "1", payload, 0)
+
+		var domainErr *domain.Error
+
+		assert.Nil(t, res)
+		assert.True(t, errors.As(err, &domainErr))
+		assert.Equal(t, "listId", domainErr.Fields[0].Field)
+		assert.Equal(t, "exists", domainErr.Fields[0].Rule)
+		mockTodoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTrash(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+	mockTodoListRepo := new(mocks.TodoListRepository)
+
+	deletedAt := time.Now()
+	trashed := []domain.Todo{{ID: "1", Title: "Title 1", Audit: &domain.Audit{DeletedAt: &deletedAt}}}
+
+	t.Run("Success - Get", func(t *testing.T) {
+		mockTodoRepo.On("GetTrash", mock.Anything, (*domain.Filter)(nil), int64(0), int64(10)).Return(trashed, int64(1), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, total, err := svc.GetTrash(context.TODO(), 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, trashed, res)
+	})
+
+	t.Run("Success - Restore", func(t *testing.T) {
+		mockTodoRepo.On("GetTrash", mock.Anything, domain.Where("id", domain.FoEq, "1"), int64(0), int64(1)).Return(trashed, int64(1), nil).Once()
+		mockTodoRepo.On("Restore", mock.Anything, "1").Return(&domain.Todo{ID: "1", Title: "Title 1"}, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Restore(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "1", res.ID)
+	})
+
+	t.Run("Success - Restore Out Of Missing List", func(t *testing.T) {
+		mockTodoRepo.On("GetTrash", mock.Anything, domain.Where("id", domain.FoEq, "2"), int64(0), int64(1)).Return([]domain.Todo{{ID: "2", ListID: "10"}}, int64(1), nil).Once()
+		mockTodoRepo.On("Restore", mock.Anything, "2").Return(&domain.Todo{ID: "2", ListID: "10"}, nil).Once()
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(nil, domain.NewNotFoundError("todo list not found")).Once()
+		mockTodoRepo.On("UpdateListMany", mock.Anything, []string{"2"}, "").Return(nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Restore(context.TODO(), "2")
+
+		assert.Nil(t, err)
+		assert.Empty(t, res.ListID)
+		mockTodoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed - Restore Not In Trash", func(t *testing.T) {
+		mockTodoRepo.On("GetTrash", mock.Anything, domain.Where("id", domain.FoEq, "3"), int64(0), int64(1)).Return([]domain.Todo{}, int64(0), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Restore(context.TODO(), "3")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNotCalled(t, "Restore", mock.Anything, "3")
+	})
+
+	t.Run("Failed - Restore Parent Deleted", func(t *testing.T) {
+		mockTodoRepo.On("GetTrash", mock.Anything, domain.Where("id", domain.FoEq, "4"), int64(0), int64(1)).Return([]domain.Todo{{ID: "4", ParentID: "1"}}, int64(1), nil).Once()
+		mockTodoRepo.On("GetByID", mock.Anything, "1", "id").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Restore(context.TODO(), "4")
+
+		assert.Equal(t, domain.ErrKindConflict, domain.ErrorKindOf(err))
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNotCalled(t, "Restore", mock.Anything, "4")
+	})
+
+	t.Run("Success - Purge", func(t *testing.T) {
+		before := time.Now().Add(-720 * time.Hour)
+
+		mockTodoRepo.On("Purge", mock.Anything, mock.MatchedBy(func(v time.Time) bool {
+			return !v.Before(before) && v.Before(before.Add(time.Minute))
+		})).Return(int64(2), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, newRevisionRepo(), transactor{}, 720*time.Hour)
+		purged, err := svc.Purge(context.TODO())
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, purged)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	mockTodoRepo := new(mocks.TodoRepository)
+	mockTodoListRepo := new(mocks.TodoListRepository)
+	mockError := errors.New("some error")
+
+	dueAt := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	ctx := domain.WithActor(context.TODO(), "alice")
+
+	t.Run("Success - Record Create", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+		payload := &domain.TodoDto{Title: "Title 1", Description: "Description 1"}
+
+		mockTodoRepo.On("Create", mock.Anything, payload).Return(&domain.Todo{ID: "1", Title: "Title 1", Description: "Description 1", Priority: domain.PriorityMedium}, nil).Once()
+		mockRevisionRepo.On("CreateMany", mock.Anything, []domain.TodoRevision{{
+			TodoID: "1",
+			Action: domain.RevisionCreate,
+			Actor:  "alice",
+			State:  domain.TodoState{Title: "Title 1", Description: "Description 1", Tags: []string{}, Priority: domain.PriorityMedium},
+		}}).Return(nil, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		_, err := svc.Create(ctx, payload)
+
+		assert.Nil(t, err)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Record Complete Cascades", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoRepo.On("GetSubtree", mock.Anything, "1").Return([]domain.TodoNode{
+			{Todo: domain.Todo{ID: "1"}},
+			{Todo: domain.Todo{ID: "2", ParentID: "1"}, Depth: 1},
+		}, nil).Once()
+		mockTodoRepo.On("UpdateStatusMany", mock.Anything, []string{"2"}, true).Return(nil).Once()
+		mockTodoRepo.On("UpdateStatus", mock.Anything, "1", true, int64(0)).Return(&domain.Todo{ID: "1", IsCompleted: true}, nil).Once()
+		mockRevisionRepo.On("CreateMany", inTx, mock.MatchedBy(func(v []domain.TodoRevision) bool {
+			return len(v) == 2 && v[0].TodoID == "1" && v[0].State.IsCompleted &&
+				v[1].TodoID == "2" && v[1].Action == domain.RevisionStatus && v[1].State.IsCompleted && !v[1].Before.IsCompleted
+		})).Return(nil, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		_, err := svc.Complete(ctx, "1", true, 0)
+
+		assert.Nil(t, err)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed - Record", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoRepo.On("GetByID", inTx, "1").Return(&domain.Todo{ID: "1"}, nil).Once()
+		mockTodoRepo.On("UpdateStatus", inTx, "1", true, int64(0)).Return(&domain.Todo{ID: "1", IsCompleted: true}, nil).Once()
+		mockRevisionRepo.On("CreateMany", inTx, mock.Anything).Return(nil, mockError).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, err := svc.UpdateStatus(ctx, "1", true, 0)
+
+		assert.Equal(t, mockError, err)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Success - Get", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockRevisionRepo.On("Get", mock.Anything, "1", int64(0), int64(10)).Return([]domain.TodoRevision{
+			{
+				ID:     "11",
+				Action: domain.RevisionUpdate,
+				Before: &domain.TodoState{Title: "Title 1", Tags: []string{}, Priority: domain.PriorityMedium},
+				State:  domain.TodoState{Title: "Title 1", Tags: []string{"home"}, Priority: domain.PriorityHigh, DueAt: &dueAt},
+			},
+			{
+				ID:     "10",
+				Action: domain.RevisionCreate,
+				State:  domain.TodoState{Title: "Title 1", Tags: []string{}, Priority: domain.PriorityMedium},
+			},
+		}, int64(2), nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, total, err := svc.GetHistory(ctx, "1", 0, 10)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.Equal(t, []domain.FieldChange{
+			{Field: "tags", From: []string{}, To: []string{"home"}},
+			{Field: "priority", From: domain.PriorityMedium, To: domain.PriorityHigh},
+			{Field: "dueAt", From: nil, To: dueAt},
+		}, res[0].Changes)
+		assert.Equal(t, []domain.FieldChange{
+			{Field: "title", From: "", To: "Title 1"},
+			{Field: "priority", From: nil, To: domain.PriorityMedium},
+		}, res[1].Changes)
+	})
+
+	t.Run("Failed - Get", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockRevisionRepo.On("Get", mock.Anything, "1", int64(0), int64(10)).Return(nil, int64(0), mockError).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, total, err := svc.GetHistory(ctx, "1", 0, 10)
+
+		assert.Equal(t, mockError, err)
+		assert.Nil(t, res)
+		assert.EqualValues(t, 0, total)
+	})
+
+	t.Run("Success - Revert", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+		current := &domain.Todo{ID: "1", Title: "Title 1 - Updated", IsCompleted: true, Tags: []string{"home"}, Priority: domain.PriorityHigh}
+		reverted := &domain.Todo{ID: "1", Title: "Title 1", Priority: domain.PriorityMedium}
+
+		mockRevisionRepo.On("GetByID", mock.Anything, "1", "10").Return(&domain.TodoRevision{
+			ID:     "10",
+			TodoID: "1",
+			Action: domain.RevisionCreate,
+			State:  domain.TodoState{Title: "Title 1", Tags: []string{}, Priority: domain.PriorityMedium},
+		}, nil).Once()
+		mockTodoRepo.On("GetByID", inTx, "1").Return(current, nil).Once()
+		mockTodoRepo.On("Revert", inTx, "1", domain.TodoState{Title: "Title 1", Tags: []string{}, Priority: domain.PriorityMedium}).Return(reverted, nil).Once()
+		mockRevisionRepo.On("CreateMany", inTx, []domain.TodoRevision{
+			domain.NewTodoRevision(ctx, domain.RevisionRevert, current, *reverted),
+		}).Return(nil, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, err := svc.Revert(ctx, "1", "10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, reverted, res)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed - Revert Unknown Revision", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockRevisionRepo.On("GetByID", mock.Anything, "1", "99").Return(nil, domain.NewNotFoundError("todo revision not found")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, err := svc.Revert(ctx, "1", "99")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Failed - Revert Missing Parent", func(t *testing.T) {
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockRevisionRepo.On("GetByID", mock.Anything, "2", "20").Return(&domain.TodoRevision{
+			ID:     "20",
+			TodoID: "2",
+			State:  domain.TodoState{Title: "Title 2", Tags: []string{}, ParentID: "1"},
+		}, nil).Once()
+		mockTodoRepo.On("GetByID", mock.Anything, "1", "id").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, mockTodoListRepo, mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, err := svc.Revert(ctx, "2", "20")
+
+		assert.Equal(t, domain.ErrKindValidation, domain.ErrorKindOf(err))
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNotCalled(t, "Revert", mock.Anything, "2", mock.Anything)
+	})
+}
+
+func TestVersion(t *testing.T) {
+	current := &domain.Todo{ID: "1", Title: "Title 1", Version: 3}
+
+	t.Run("Success - Update", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+		dto := &domain.TodoDto{Title: "Title 1 - Updated", Description: "Description 1"}
+
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(current, nil).Once()
+		mockTodoRepo.On("Update", mock.Anything, "1", dto, int64(3)).Return(&domain.Todo{ID: "1", Title: dto.Title, Version: 4}, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Update(context.TODO(), "1", dto, 3)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 4, res.Version)
+	})
+
+	t.Run("Failed - Update Stale", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(current, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Update(context.TODO(), "1", &domain.TodoDto{Title: "Title 1", Description: "Description 1"}, 2)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed - UpdateStatus Written Meanwhile", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(current, nil).Once()
+		mockTodoRepo.On("UpdateStatus", mock.Anything, "1", true, int64(3)).Return(nil, domain.NewPreconditionError("todo version doesn't match")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, err := svc.UpdateStatus(context.TODO(), "1", true, 3)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.Nil(t, res)
+		mockRevisionRepo.AssertNotCalled(t, "CreateMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed - Complete Stale", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+
+		mockTodoRepo.On("GetSubtree", mock.Anything, "1").Return([]domain.TodoNode{
+			{Todo: *current},
+			{Todo: domain.Todo{ID: "2", ParentID: "1"}, Depth: 1},
+		}, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, err := svc.Complete(context.TODO(), "1", true, 2)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNotCalled(t, "UpdateStatusMany", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed - Delete Stale", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+
+		mockTodoRepo.On("Get", mock.Anything, domain.Where("parentId", domain.FoEq, "1"), []domain.Sort(nil), int64(0), int64(1), "id").Return([]domain.Todo{}, int64(0), nil).Once()
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(current, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		err := svc.Delete(context.TODO(), "1", 2)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+		mockTodoRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestReplace(t *testing.T) {
+	payload := &domain.TodoDto{Title: "Title 1", Description: "Description 1"}
+
+	t.Run("Success - Created", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+		mockTodoRepo.On("Replace", mock.Anything, "1", payload).Return(&domain.Todo{ID: "1", Title: "Title 1", Version: 1}, true, nil).Once()
+		mockRevisionRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(v []domain.TodoRevision) bool {
+			return len(v) == 1 && v[0].Action == domain.RevisionCreate && v[0].Before == nil
+		})).Return(nil, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, created, err := svc.Replace(context.TODO(), "1", payload)
+
+		assert.Nil(t, err)
+		assert.True(t, created)
+		assert.EqualValues(t, 1, res.Version)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Created With Parent", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+		withParent := &domain.TodoDto{Title: "Title 1", Description: "Description 1", ParentID: "2"}
+
+		// a created todo has no subtasks to look up
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+		mockTodoRepo.On("GetByID", mock.Anything, "2", "id").Return(&domain.Todo{ID: "2"}, nil).Once()
+		mockTodoRepo.On("Replace", mock.Anything, "1", withParent).Return(&domain.Todo{ID: "1", ParentID: "2", Version: 1}, true, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		_, created, err := svc.Replace(context.TODO(), "1", withParent)
+
+		assert.Nil(t, err)
+		assert.True(t, created)
+		mockTodoRepo.AssertNotCalled(t, "GetSubtree", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Replaced", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(&domain.Todo{ID: "1", Title: "Title 0", Version: 2}, nil).Once()
+		mockTodoRepo.On("Replace", mock.Anything, "1", payload).Return(&domain.Todo{ID: "1", Title: "Title 1", Version: 3}, false, nil).Once()
+		mockRevisionRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(v []domain.TodoRevision) bool {
+			return len(v) == 1 && v[0].Action == domain.RevisionUpdate && v[0].Before.Title == "Title 0"
+		})).Return(nil, nil).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), mockRevisionRepo, transactor{}, 720*time.Hour)
+		res, created, err := svc.Replace(context.TODO(), "1", payload)
+
+		assert.Nil(t, err)
+		assert.False(t, created)
+		assert.Equal(t, "Title 1", res.Title)
+		mockRevisionRepo.AssertExpectations(t)
 	})
 
 	t.Run("Failed", func(t *testing.T) {
-		mockTodoRepo.On("Delete", context.TODO(), "1").Return(mockError).Once()
+		mockTodoRepo := new(mocks.TodoRepository)
+
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(nil, domain.NewNotFoundError("todo not found")).Once()
+		mockTodoRepo.On("Replace", mock.Anything, "1", payload).Return(nil, false, domain.NewConflictError("todo is in the trash, restore it first")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, created, err := svc.Replace(context.TODO(), "1", payload)
 
-		svc := service.NewTodoService(mockTodoRepo)
-		err := svc.Delete(context.TODO(), "1")
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.False(t, created)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Failed - Read", func(t *testing.T) {
+		mockTodoRepo := new(mocks.TodoRepository)
+
+		mockTodoRepo.On("GetByID", mock.Anything, "1").Return(nil, errors.New("some error")).Once()
+
+		svc := service.NewTodoService(mockTodoRepo, new(mocks.TodoListRepository), newRevisionRepo(), transactor{}, 720*time.Hour)
+		res, _, err := svc.Replace(context.TODO(), "1", payload)
 
 		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		mockTodoRepo.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ariefsn/go-resik/common"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/ariefsn/go-resik/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// TodoListApi  represent the httphandler for todo list
+type TodoListApi struct {
+	todoListSvc domain.TodoListService
+}
+
+func NewTodoListApi(todoListSvc domain.TodoListService) *fiber.App {
+	api := &TodoListApi{
+		todoListSvc: todoListSvc,
+	}
+
+	app := fiber.New(fiber.Config{
+		ErrorHandler: helper.ErrorHandler,
+	})
+
+	app.Post("/", api.Create).Name("todoListCreate")
+	app.Get("/", api.Get).Name("todoListGet")
+	app.Get("/:id", api.GetByID).Name("todoListGetById")
+	app.Put("/:id", api.Update)
+	app.Delete("/:id", api.Delete)
+
+	return app
+}
+
+func (a *TodoListApi) Create(c *fiber.Ctx) error {
+	payload := domain.TodoListDto{}
+
+	if err := c.BodyParser(&payload); err != nil {
+		logger.Error(err)
+		return domain.NewValidationError(err.Error(), err)
+	}
+
+	if err := helper.Validate(&payload); err != nil {
+		return err
+	}
+
+	res, err := a.todoListSvc.Create(c.UserContext(), &payload)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoListApi) GetByID(c *fiber.Ctx) error {
+	res, err := a.todoListSvc.GetByID(c.UserContext(), c.Params("id"))
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoListApi) Get(c *fiber.Ctx) error {
+	skip := c.QueryInt("skip", 0)
+	limit := c.QueryInt("limit", 10)
+
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+
+	res, total, err := a.todoListSvc.Get(c.UserContext(), int64(skip), int64(limit))
+
+	if err != nil {
+		return err
+	}
+
+	links := helper.PageLinks(c.Path(), query, int64(skip), int64(limit), total)
+
+	c.Links(links.ToLinks()...)
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(common.PageModel{
+		Items:   res,
+		Skip:    int64(skip),
+		Limit:   int64(limit),
+		Total:   total,
+		HasMore: int64(skip+len(res)) < total,
+		Links:   links,
+	}))
+}
+
+func (a *TodoListApi) Update(c *fiber.Ctx) error {
+	payload := domain.TodoListDto{}
+
+	id := c.Params("id")
+
+	if err := c.BodyParser(&payload); err != nil {
+		logger.Error(err)
+		return domain.NewValidationError(err.Error(), err)
+	}
+
+	if err := helper.Validate(&payload); err != nil {
+		return err
+	}
+
+	res, err := a.todoListSvc.Update(c.UserContext(), id, &payload)
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(res))
+}
+
+func (a *TodoListApi) Delete(c *fiber.Ctx) error {
+	payload := domain.TodoListDeleteDto{}
+
+	id := c.Params("id")
+
+	if err := c.QueryParser(&payload); err != nil {
+		logger.Error(err)
+		return domain.NewValidationError(err.Error(), err)
+	}
+
+	if err := helper.Validate(&payload); err != nil {
+		return err
+	}
+
+	// the query is only valid during the request, the todos and their revisions keep the list they're moved to
+	err := a.todoListSvc.Delete(c.UserContext(), id, payload.PolicyValue(), strings.Clone(payload.To))
+
+	if err != nil {
+		return err
+	}
+
+	return c.Status(http.StatusOK).JSON(helper.JsonSuccess(id))
+}
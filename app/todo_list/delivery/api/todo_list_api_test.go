@@ -0,0 +1,231 @@
+package api_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ariefsn/go-resik/app/todo_list/delivery/api"
+	"github.com/ariefsn/go-resik/common"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/mocks"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp/fasthttputil"
+)
+
+var MOCK_CTX = context.Background()
+
+var MOCK_DTO = &domain.TodoListDto{
+	Name:        "List 1",
+	Description: "Description 1",
+}
+
+var MOCK_DATA_SINGLE = domain.TodoList{
+	ID:          "10",
+	Name:        "List 1",
+	Description: "Description 1",
+	Audit: &domain.Audit{
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	},
+}
+
+var MOCK_DATA_SINGLE_M, _ = helper.FromJson[map[string]interface{}](MOCK_DATA_SINGLE)
+
+var svc = new(mocks.TodoListService)
+
+func TestCreate(t *testing.T) {
+	app := api.NewTodoListApi(svc)
+
+	t.Run("Success", func(t *testing.T) {
+		svc.On("Create", MOCK_CTX, MOCK_DTO).Return(&MOCK_DATA_SINGLE, nil).Once()
+
+		body, _ := helper.ToJsonBody(MOCK_DTO)
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.True(t, result.Status)
+		assert.Equal(t, MOCK_DATA_SINGLE_M, result.Data)
+	})
+
+	t.Run("Failed - Validation", func(t *testing.T) {
+		body, _ := helper.ToJsonBody(domain.TodoListDto{Name: " "})
+
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+		assert.Equal(t, []common.ResponseError{
+			{Field: "name", Code: "notblank", Message: "name is required"},
+		}, result.Errors)
+	})
+}
+
+func TestGet(t *testing.T) {
+	app := api.NewTodoListApi(svc)
+
+	svc.On("Get", MOCK_CTX, int64(0), int64(1)).Return([]domain.TodoList{MOCK_DATA_SINGLE}, int64(2), nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/?limit=1", nil)
+
+	res, _ := app.Test(req)
+
+	result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+	data := result.Data.(map[string]interface{})
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []interface{}{MOCK_DATA_SINGLE_M}, data["items"])
+	assert.EqualValues(t, 2, data["total"])
+	assert.Equal(t, true, data["hasMore"])
+}
+
+func TestGetByID(t *testing.T) {
+	app := api.NewTodoListApi(svc)
+
+	t.Run("Success", func(t *testing.T) {
+		svc.On("GetByID", MOCK_CTX, "10").Return(&domain.TodoListDetail{
+			TodoList: MOCK_DATA_SINGLE,
+			Todos:    []domain.Todo{{ID: "1", Title: "Title 1", ListID: "10"}},
+		}, nil).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/10", nil)
+
+		res, _ := app.Test(req)
+
+		result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+		data := result.Data.(map[string]interface{})
+		todos := data["todos"].([]interface{})
+
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "List 1", data["name"])
+		assert.Equal(t, 1, len(todos))
+		assert.Equal(t, "10", todos[0].(map[string]interface{})["listId"])
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		svc.On("GetByID", MOCK_CTX, "11").Return(nil, domain.NewNotFoundError("todo list not found")).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/11", nil)
+
+		res, _ := app.Test(req)
+
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	app := api.NewTodoListApi(svc)
+
+	svc.On("Update", MOCK_CTX, "10", MOCK_DTO).Return(&MOCK_DATA_SINGLE, nil).Once()
+
+	body, _ := helper.ToJsonBody(MOCK_DTO)
+
+	req := httptest.NewRequest(http.MethodPut, "/10", body)
+	req.Header.Set("Content-Type", "application/json")
+
+	res, _ := app.Test(req)
+
+	result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, MOCK_DATA_SINGLE_M, result.Data)
+}
+
+func TestDelete(t *testing.T) {
+	app := api.NewTodoListApi(svc)
+
+	cases := []struct {
+		name   string
+		query  string
+		policy domain.ListDeletePolicy
+		to     string
+		err    error
+		status int
+	}{
+		{name: "Success - Refuse", query: "", policy: domain.ListDeleteRefuse, status: http.StatusOK},
+		{name: "Success - Cascade", query: "?policy=cascade", policy: domain.ListDeleteCascade, status: http.StatusOK},
+		{name: "Success - Reassign", query: "?policy=reassign&to=6512d6f0a7e2b1c3d4e5f607", policy: domain.ListDeleteReassign, to: "6512d6f0a7e2b1c3d4e5f607", status: http.StatusOK},
+		{name: "Failed - Has Todos", query: "?policy=refuse", policy: domain.ListDeleteRefuse, err: domain.NewConflictError("todo list has todos"), status: http.StatusConflict},
+		{name: "Failed - Policy", query: "?policy=orphan", status: http.StatusBadRequest},
+		{name: "Failed - To", query: "?policy=reassign&to=list", status: http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if c.policy != "" {
+				svc.On("Delete", MOCK_CTX, "10", c.policy, c.to).Return(c.err).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodDelete, "/10"+c.query, nil)
+
+			res, _ := app.Test(req)
+
+			result, _ := helper.FromResponseBody[common.ResponseModel](res.Body)
+
+			assert.Equal(t, c.status, res.StatusCode)
+
+			if c.status == http.StatusOK {
+				assert.Equal(t, "10", result.Data)
+			}
+		})
+	}
+
+	svc.AssertExpectations(t)
+}
+
+func TestDeleteReassignKeepAlive(t *testing.T) {
+	app := api.NewTodoListApi(svc)
+
+	// both requests go through the same connection, the second one reuses the buffers of the first
+	ln := fasthttputil.NewInmemoryListener()
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return ln.Dial()
+		},
+	}}
+
+	to := ""
+	svc.On("Delete", MOCK_CTX, "10", domain.ListDeleteReassign, "6512d6f0a7e2b1c3d4e5f607").Run(func(args mock.Arguments) {
+		to = args.String(3)
+	}).Return(nil).Once()
+
+	req, _ := http.NewRequest(http.MethodDelete, "http://resik/10?policy=reassign&to=6512d6f0a7e2b1c3d4e5f607", nil)
+	res, err := client.Do(req)
+
+	require.Nil(t, err)
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodDelete, "http://resik/10?policy=x&to=QQQQQQQQQQQQQQQQQQQQQQQQ", nil)
+	res, err = client.Do(req)
+
+	require.Nil(t, err)
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	assert.Equal(t, "6512d6f0a7e2b1c3d4e5f607", to)
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNotFound = domain.NewNotFoundError("todo list not found")
+
+type memoryTodoListRepository struct {
+	mu       sync.RWMutex
+	items    []domain.TodoList
+	todoRepo domain.TodoRepository
+}
+
+func clone(l domain.TodoList) domain.TodoList {
+	if l.Audit != nil {
+		audit := *l.Audit
+		l.Audit = &audit
+	}
+
+	return l
+}
+
+func (r *memoryTodoListRepository) indexOf(id string) int {
+	for i, v := range r.items {
+		if v.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Create implements domain.TodoListRepository.
+func (r *memoryTodoListRepository) Create(ctx context.Context, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	data := domain.TodoList{
+		ID:          primitive.NewObjectID().Hex(),
+		Name:        payload.Name,
+		Description: payload.Description,
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.items = append(r.items, clone(data))
+
+	return &data, nil
+}
+
+// Delete implements domain.TodoListRepository.
+func (r *memoryTodoListRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return errNotFound
+	}
+
+	r.items = append(r.items[:i], r.items[i+1:]...)
+
+	return nil
+}
+
+// Get implements domain.TodoListRepository.
+func (r *memoryTodoListRepository) Get(ctx context.Context, skip int64, limit int64) ([]domain.TodoList, int64, error) {
+	result := []domain.TodoList{}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sorted := slices.Clone(r.items)
+
+	slices.SortFunc(sorted, func(a, b domain.TodoList) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for i, v := range sorted {
+		if int64(i) < skip {
+			continue
+		}
+
+		if limit > -1 && int64(len(result)) >= limit {
+			break
+		}
+
+		result = append(result, clone(v))
+	}
+
+	return result, int64(len(sorted)), nil
+}
+
+// GetByID implements domain.TodoListRepository.
+func (r *memoryTodoListRepository) GetByID(ctx context.Context, id string) (*domain.TodoList, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, errNotFound
+	}
+
+	result := clone(r.items[i])
+
+	return &result, nil
+}
+
+// GetWithTodos implements domain.TodoListRepository.
+func (r *memoryTodoListRepository) GetWithTodos(ctx context.Context, id string) (*domain.TodoListDetail, error) {
+	list, err := r.GetByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	todos, _, err := r.todoRepo.Get(ctx, domain.Where("listId", domain.FoEq, id), []domain.Sort{{Field: "id", Order: domain.SortAsc}}, 0, -1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TodoListDetail{
+		TodoList: *list,
+		Todos:    todos,
+	}, nil
+}
+
+// Update implements domain.TodoListRepository.
+func (r *memoryTodoListRepository) Update(ctx context.Context, id string, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.indexOf(id)
+	if i < 0 {
+		return nil, errNotFound
+	}
+
+	data := clone(r.items[i])
+	data.Name = payload.Name
+	data.Description = payload.Description
+
+	if data.Audit == nil {
+		data.Audit = &domain.Audit{}
+	}
+	data.UpdatedAt = time.Now()
+
+	r.items[i] = data
+
+	result := clone(data)
+
+	return &result, nil
+}
+
+// NewMemoryTodoListRepository will create an object that represent the todo_list.Repository interface,
+// the todos of the lists are read from the given todo repository
+func NewMemoryTodoListRepository(todoRepo domain.TodoRepository) domain.TodoListRepository {
+	return &memoryTodoListRepository{
+		items:    []domain.TodoList{},
+		todoRepo: todoRepo,
+	}
+}
@@ -0,0 +1,43 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	todoMemory "github.com/ariefsn/go-resik/app/todo/repository/memory"
+	"github.com/ariefsn/go-resik/app/todo_list/repository/memory"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/stretchr/testify/assert"
+)
+
+var MOCK_DTO = &domain.TodoListDto{
+	Name:        "List - 1",
+	Description: "Description - 1",
+}
+
+func TestCreate(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoListRepository(todoMemory.NewMemoryTodoRepository())
+
+	res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res.ID)
+	assert.Equal(t, MOCK_DTO.Name, res.Name)
+
+	// the stored list isn't shared with the caller
+	res.Name = "Changed"
+
+	stored, err := mockRepo.GetByID(context.TODO(), res.ID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, MOCK_DTO.Name, stored.Name)
+}
+
+func TestContract(t *testing.T) {
+	repotest.RunTodoListRepositoryTests(t, func(t *testing.T) (domain.TodoListRepository, domain.TodoRepository) {
+		todoRepo := todoMemory.NewMemoryTodoRepository()
+
+		return memory.NewMemoryTodoListRepository(todoRepo), todoRepo
+	})
+}
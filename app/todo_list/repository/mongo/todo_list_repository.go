@@ -0,0 +1,203 @@
+package mongo
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/ariefsn/go-resik/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoTodoListRepository struct {
+	Db *mongo.Database
+}
+
+// Create implements domain.TodoListRepository.
+func (r *mongoTodoListRepository) Create(ctx context.Context, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	data := domain.TodoList{
+		ID:          primitive.NewObjectID().Hex(),
+		Name:        payload.Name,
+		Description: payload.Description,
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	}
+
+	_, err := r.Db.Collection(data.TableName()).InsertOne(ctx, data)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	return &data, nil
+}
+
+// Delete implements domain.TodoListRepository.
+func (r *mongoTodoListRepository) Delete(ctx context.Context, id string) error {
+	res := r.Db.Collection(domain.TodoList{}.TableName()).FindOneAndDelete(ctx, bson.M{"_id": id})
+
+	if res.Err() != nil {
+		logger.Error(res.Err())
+		return helper.ParseMongoError(res.Err())
+	}
+
+	return nil
+}
+
+// Get implements domain.TodoListRepository.
+func (r *mongoTodoListRepository) Get(ctx context.Context, skip int64, limit int64) ([]domain.TodoList, int64, error) {
+	result := []domain.TodoList{}
+
+	count, err := r.Db.Collection(domain.TodoList{}.TableName()).CountDocuments(ctx, bson.M{})
+
+	if err != nil && err != mongo.ErrNilDocument {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	pipe := helper.MongoPipe(helper.MongoAggregate{
+		Sort:  []helper.MongoSort{{SortField: "_id", SortBy: helper.SortByAsc}},
+		Skip:  &skip,
+		Limit: &limit,
+	})
+
+	cur, err := r.Db.Collection(domain.TodoList{}.TableName()).Aggregate(ctx, pipe)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var row domain.TodoList
+
+		err = cur.Decode(&row)
+		if err != nil {
+			logger.Error(err)
+			return []domain.TodoList{}, 0, helper.ParseMongoError(err)
+		}
+
+		result = append(result, row)
+	}
+
+	if err := cur.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TodoList{}, 0, helper.ParseMongoError(err)
+	}
+
+	return result, count, nil
+}
+
+// GetByID implements domain.TodoListRepository.
+func (r *mongoTodoListRepository) GetByID(ctx context.Context, id string) (*domain.TodoList, error) {
+	result := domain.TodoList{}
+
+	err := r.Db.Collection(result.TableName()).FindOne(ctx, bson.M{"_id": id}).Decode(&result)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	return &result, nil
+}
+
+// GetWithTodos implements domain.TodoListRepository.
+func (r *mongoTodoListRepository) GetWithTodos(ctx context.Context, id string) (*domain.TodoListDetail, error) {
+	pipe := []bson.M{
+		helper.MongoMatch(bson.M{"_id": id}),
+		helper.MongoLookup(helper.MongoLookupOptions{
+			From: domain.Todo{}.TableName(),
+			As:   "todos",
+			Let:  bson.M{"listId": "$_id"},
+			// the todos of the list are matched by the pipeline along with leaving out the ones in the trash
+			Pipeline: []bson.M{
+				helper.MongoMatch(bson.M{
+					"$expr":           bson.M{"$eq": bson.A{"$listId", "$$listId"}},
+					"audit.deletedAt": nil,
+				}),
+			},
+		}),
+	}
+
+	cur, err := r.Db.Collection(domain.TodoList{}.TableName()).Aggregate(ctx, pipe)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	defer cur.Close(ctx)
+
+	if !cur.Next(ctx) {
+		if err := cur.Err(); err != nil {
+			logger.Error(err)
+			return nil, helper.ParseMongoError(err)
+		}
+
+		return nil, domain.NewNotFoundError("todo list not found", mongo.ErrNoDocuments)
+	}
+
+	result := domain.TodoListDetail{}
+
+	if err := cur.Decode(&result); err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	if result.Todos == nil {
+		result.Todos = []domain.Todo{}
+	}
+
+	// $lookup keeps no particular order
+	slices.SortFunc(result.Todos, func(a, b domain.Todo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return &result, nil
+}
+
+// Update implements domain.TodoListRepository.
+func (r *mongoTodoListRepository) Update(ctx context.Context, id string, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	var data domain.TodoList
+
+	returnDoc := options.After
+
+	res := r.Db.Collection(data.TableName()).FindOneAndUpdate(ctx, bson.M{"_id": id}, helper.MongoSet(bson.M{
+		"name":            payload.Name,
+		"description":     payload.Description,
+		"audit.updatedAt": time.Now(),
+	}), &options.FindOneAndUpdateOptions{
+		ReturnDocument: &returnDoc,
+	})
+
+	if res.Err() != nil {
+		logger.Error(res.Err())
+		return nil, helper.ParseMongoError(res.Err())
+	}
+
+	if err := res.Decode(&data); err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	return &data, nil
+}
+
+// NewMongoTodoListRepository will create an object that represent the todo_list.Repository interface
+func NewMongoTodoListRepository(database *mongo.Database) domain.TodoListRepository {
+	return &mongoTodoListRepository{
+		Db: database,
+	}
+}
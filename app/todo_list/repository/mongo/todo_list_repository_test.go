@@ -0,0 +1,252 @@
+package mongo_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	todoMongo "github.com/ariefsn/go-resik/app/todo/repository/mongo"
+	"github.com/ariefsn/go-resik/app/todo_list/repository/mongo"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mdb "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var MOCK_DTO = &domain.TodoListDto{
+	Name:        "List - 1",
+	Description: "Description - 1",
+}
+
+var MOCK_DATA_SINGLE_BSOND = bson.D{
+	{Key: "_id", Value: "10"},
+	{Key: "name", Value: "List 1"},
+	{Key: "description", Value: "Description 1"},
+}
+
+func TestCreate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.Equal(t, MOCK_DTO.Name, res.Name)
+		assert.Equal(t, "todo_lists", t.GetStartedEvent().Command.Lookup("insert").StringValue())
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 1, Code: 12, Message: "some error"}))
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGet(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todo_lists", mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_lists", mtest.FirstBatch, MOCK_DATA_SINGLE_BSOND))
+
+		res, total, err := mockRepo.Get(context.TODO(), 2, 1)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, []domain.TodoList{{ID: "10", Name: "List 1", Description: "Description 1"}}, res)
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		res, total, err := mockRepo.Get(context.TODO(), 0, 10)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Equal(t, []domain.TodoList{}, res)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_lists", mtest.FirstBatch, MOCK_DATA_SINGLE_BSOND))
+
+		res, err := mockRepo.GetByID(context.TODO(), "10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "List 1", res.Name)
+	})
+
+	mt.Run("Failed - Not Found", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_lists", mtest.FirstBatch))
+
+		res, err := mockRepo.GetByID(context.TODO(), "10")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGetWithTodos(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_lists", mtest.FirstBatch, append(MOCK_DATA_SINGLE_BSOND, bson.E{
+			Key: "todos",
+			Value: bson.A{
+				bson.D{{Key: "_id", Value: "2"}, {Key: "title", Value: "Title 2"}, {Key: "listId", Value: "10"}},
+				bson.D{{Key: "_id", Value: "1"}, {Key: "title", Value: "Title 1"}, {Key: "listId", Value: "10"}},
+			},
+		})))
+
+		res, err := mockRepo.GetWithTodos(context.TODO(), "10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "List 1", res.Name)
+		require.Equal(t, 2, len(res.Todos))
+		assert.Equal(t, "1", res.Todos[0].ID)
+		assert.Equal(t, "10", res.Todos[1].ListID)
+
+		lookup := t.GetStartedEvent().Command.Lookup("pipeline").Array().Index(1).Value().Document()
+
+		assert.Equal(t, "todos", lookup.Lookup("$lookup", "from").StringValue())
+		assert.Equal(t, "$_id", lookup.Lookup("$lookup", "let", "listId").StringValue())
+		assert.Error(t, lookup.Lookup("$lookup", "localField").Validate())
+
+		match := lookup.Lookup("$lookup", "pipeline").Array().Index(0).Value().Document().Lookup("$match").Document()
+		operands, _ := match.Lookup("$expr", "$eq").Array().Values()
+
+		assert.Equal(t, "$listId", operands[0].StringValue())
+		assert.Equal(t, "$$listId", operands[1].StringValue())
+		assert.Equal(t, bson.TypeNull, match.Lookup("audit.deletedAt").Type)
+	})
+
+	mt.Run("Success - Empty", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_lists", mtest.FirstBatch, MOCK_DATA_SINGLE_BSOND))
+
+		res, err := mockRepo.GetWithTodos(context.TODO(), "10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Todo{}, res.Todos)
+	})
+
+	mt.Run("Failed - Not Found", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_lists", mtest.FirstBatch))
+
+		res, err := mockRepo.GetWithTodos(context.TODO(), "10")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: MOCK_DATA_SINGLE_BSOND}})
+
+		res, err := mockRepo.Update(context.TODO(), "10", MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "List 1", res.Name)
+
+		command := t.GetStartedEvent().Command
+
+		assert.Equal(t, MOCK_DTO.Name, command.Lookup("update", "$set", "name").StringValue())
+		assert.NotNil(t, command.Lookup("upsert").Validate())
+	})
+
+	mt.Run("Failed - Not Found", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: nil}})
+
+		res, err := mockRepo.Update(context.TODO(), "10", MOCK_DTO)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestDelete(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(bson.D{{Key: "ok", Value: 1}, {Key: "value", Value: MOCK_DATA_SINGLE_BSOND}})
+
+		err := mockRepo.Delete(context.TODO(), "10")
+
+		assert.Nil(t, err)
+	})
+
+	mt.Run("Failed - Not Found", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoListRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Message: mdb.ErrNoDocuments.Error()}))
+
+		err := mockRepo.Delete(context.TODO(), "10")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+// TestContract runs the repository contract against a real server, it's skipped unless TEST_MONGO_URI is set
+func TestContract(t *testing.T) {
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGO_URI is not set")
+	}
+
+	client, err := mdb.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		client.Disconnect(context.TODO())
+	})
+
+	repotest.RunTodoListRepositoryTests(t, func(t *testing.T) (domain.TodoListRepository, domain.TodoRepository) {
+		db := client.Database("resik_test_" + primitive.NewObjectID().Hex())
+
+		t.Cleanup(func() {
+			db.Drop(context.TODO())
+		})
+
+		return mongo.NewMongoTodoListRepository(db), todoMongo.NewMongoTodoRepository(db)
+	})
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS todo_lists (
+  id VARCHAR(24) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  description TEXT NOT NULL,
+  created_at DATETIME(6) NOT NULL,
+  updated_at DATETIME(6) NOT NULL,
+  PRIMARY KEY (id)
+);
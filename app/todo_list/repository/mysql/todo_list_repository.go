@@ -0,0 +1,205 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/ariefsn/go-resik/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Schema is the DDL of the tables used by the todo list repository
+//
+//go:embed schema.sql
+var Schema string
+
+const todoListColumns = "id, name, description, created_at, updated_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type mysqlTodoListRepository struct {
+	Db       *sql.DB
+	todoRepo domain.TodoRepository
+}
+
+// conn returns what the queries run on, the transaction of the context if any
+func (r *mysqlTodoListRepository) conn(ctx context.Context) helper.MySqlConn {
+	return helper.MySqlConnOf(ctx, r.Db)
+}
+
+func scanTodoList(row rowScanner) (*domain.TodoList, error) {
+	data := domain.TodoList{
+		Audit: &domain.Audit{},
+	}
+
+	err := row.Scan(&data.ID, &data.Name, &data.Description, &data.CreatedAt, &data.UpdatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// Create implements domain.TodoListRepository.
+func (r *mysqlTodoListRepository) Create(ctx context.Context, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	data := domain.TodoList{
+		ID:          primitive.NewObjectID().Hex(),
+		Name:        payload.Name,
+		Description: payload.Description,
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (?, ?, ?, ?, ?)", data.TableName(), todoListColumns)
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, data.ID, data.Name, data.Description, data.CreatedAt, data.UpdatedAt)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// Delete implements domain.TodoListRepository.
+func (r *mysqlTodoListRepository) Delete(ctx context.Context, id string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = ?", domain.TodoList{}.TableName())
+
+	res, err := r.conn(ctx).ExecContext(ctx, query, id)
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if affected == 0 {
+		return domain.NewNotFoundError("todo list not found", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// Get implements domain.TodoListRepository.
+func (r *mysqlTodoListRepository) Get(ctx context.Context, skip int64, limit int64) ([]domain.TodoList, int64, error) {
+	result := []domain.TodoList{}
+
+	var count int64
+
+	err := r.conn(ctx).QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s", domain.TodoList{}.TableName())).Scan(&count)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	paging, args := helper.MySqlLimit(skip, limit)
+	query := fmt.Sprintf("SELECT %s FROM %s ORDER BY id ASC%s", todoListColumns, domain.TodoList{}.TableName(), paging)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanTodoList(rows)
+		if err != nil {
+			logger.Error(err)
+			return []domain.TodoList{}, 0, err
+		}
+
+		result = append(result, *row)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TodoList{}, 0, err
+	}
+
+	return result, count, nil
+}
+
+// GetByID implements domain.TodoListRepository.
+func (r *mysqlTodoListRepository) GetByID(ctx context.Context, id string) (*domain.TodoList, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ?", todoListColumns, domain.TodoList{}.TableName())
+
+	result, err := scanTodoList(r.conn(ctx).QueryRowContext(ctx, query, id))
+
+	if err != nil {
+		logger.Error(err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NewNotFoundError("todo list not found", err)
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetWithTodos implements domain.TodoListRepository.
+func (r *mysqlTodoListRepository) GetWithTodos(ctx context.Context, id string) (*domain.TodoListDetail, error) {
+	list, err := r.GetByID(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	todos, _, err := r.todoRepo.Get(ctx, domain.Where("listId", domain.FoEq, id), []domain.Sort{{Field: "id", Order: domain.SortAsc}}, 0, -1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.TodoListDetail{
+		TodoList: *list,
+		Todos:    todos,
+	}, nil
+}
+
+// Update implements domain.TodoListRepository.
+func (r *mysqlTodoListRepository) Update(ctx context.Context, id string, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	query := fmt.Sprintf("UPDATE %s SET name = ?, description = ?, updated_at = ? WHERE id = ?", domain.TodoList{}.TableName())
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, payload.Name, payload.Description, time.Now(), id)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	// a missing list is reported by reading it back
+	return r.GetByID(ctx, id)
+}
+
+// NewMysqlTodoListRepository will create an object that represent the todo_list.Repository interface,
+// the todos of the lists are read from the given todo repository
+func NewMysqlTodoListRepository(database *sql.DB, todoRepo domain.TodoRepository) domain.TodoListRepository {
+	return &mysqlTodoListRepository{
+		Db:       database,
+		todoRepo: todoRepo,
+	}
+}
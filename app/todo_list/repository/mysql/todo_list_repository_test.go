@@ -0,0 +1,312 @@
+package mysql_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	todoMysql "github.com/ariefsn/go-resik/app/todo/repository/mysql"
+	"github.com/ariefsn/go-resik/app/todo_list/repository/mysql"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/mocks"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var MOCK_DTO = &domain.TodoListDto{
+	Name:        "List - 1",
+	Description: "Description - 1",
+}
+
+var MOCK_DATA_LIST = []domain.TodoList{
+	{
+		ID:          "1",
+		Name:        "List 1",
+		Description: "Description 1",
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	},
+	{
+		ID:          "2",
+		Name:        "List 2",
+		Description: "Description 2",
+		Audit: &domain.Audit{
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+	},
+}
+
+var COLUMNS = []string{"id", "name", "description", "created_at", "updated_at"}
+
+const SELECT_COLUMNS = "id, name, description, created_at, updated_at"
+
+func mockRows(data ...domain.TodoList) *sqlmock.Rows {
+	rows := sqlmock.NewRows(COLUMNS)
+
+	for _, v := range data {
+		rows.AddRow(v.ID, v.Name, v.Description, v.CreatedAt, v.UpdatedAt)
+	}
+
+	return rows
+}
+
+func newMock(t *testing.T) (domain.TodoListRepository, *mocks.TodoRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	todoRepo := new(mocks.TodoRepository)
+
+	return mysql.NewMysqlTodoListRepository(db, todoRepo), todoRepo, mock
+}
+
+func TestCreate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todo_lists ("+SELECT_COLUMNS+") VALUES (?, ?, ?, ?, ?)")).
+			WithArgs(sqlmock.AnyArg(), MOCK_DTO.Name, MOCK_DTO.Description, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, res.ID)
+		assert.Equal(t, MOCK_DTO.Name, res.Name)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todo_lists")).WillReturnError(errors.New("some error"))
+
+		res, err := mockRepo.Create(context.TODO(), MOCK_DTO)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todo_lists")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(MOCK_DATA_LIST)))
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT " + SELECT_COLUMNS + " FROM todo_lists ORDER BY id ASC LIMIT ?")).
+			WithArgs(10).
+			WillReturnRows(mockRows(MOCK_DATA_LIST...))
+
+		res, total, err := mockRepo.Get(context.TODO(), 0, 10)
+
+		assert.Nil(t, err)
+		assert.Equal(t, len(MOCK_DATA_LIST), len(res))
+		assert.EqualValues(t, len(MOCK_DATA_LIST), total)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success Without Limit", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todo_lists")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(len(MOCK_DATA_LIST)))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todo_lists ORDER BY id ASC LIMIT 18446744073709551615 OFFSET ?")).
+			WithArgs(1).
+			WillReturnRows(mockRows(MOCK_DATA_LIST[1]))
+
+		res, total, err := mockRepo.Get(context.TODO(), 1, -1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"2"}, []string{res[0].ID})
+		assert.EqualValues(t, len(MOCK_DATA_LIST), total)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todo_lists")).WillReturnError(errors.New("some error"))
+
+		res, total, err := mockRepo.Get(context.TODO(), 0, 10)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Equal(t, []domain.TodoList{}, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT " + SELECT_COLUMNS + " FROM todo_lists WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+		res, err := mockRepo.GetByID(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, MOCK_DATA_LIST[0].Name, res.Name)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todo_lists WHERE id = ?")).
+			WithArgs("3").
+			WillReturnRows(mockRows())
+
+		res, err := mockRepo.GetByID(context.TODO(), "3")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestGetWithTodos(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, todoRepo, mock := newMock(t)
+
+		todos := []domain.Todo{{ID: "1", Title: "Title 1", ListID: "1"}}
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todo_lists WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+		todoRepo.On("Get", context.TODO(), domain.Where("listId", domain.FoEq, "1"), []domain.Sort{{Field: "id", Order: domain.SortAsc}}, int64(0), int64(-1)).
+			Return(todos, int64(1), nil).Once()
+
+		res, err := mockRepo.GetWithTodos(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.Equal(t, MOCK_DATA_LIST[0].Name, res.Name)
+		assert.Equal(t, todos, res.Todos)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		todoRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockRepo, todoRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todo_lists WHERE id = ?")).
+			WithArgs("3").
+			WillReturnRows(mockRows())
+
+		res, err := mockRepo.GetWithTodos(context.TODO(), "3")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+		todoRepo.AssertNotCalled(t, "Get")
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todo_lists SET name = ?, description = ?, updated_at = ? WHERE id = ?")).
+			WithArgs(MOCK_DTO.Name, MOCK_DTO.Description, sqlmock.AnyArg(), "1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todo_lists WHERE id = ?")).
+			WithArgs("1").
+			WillReturnRows(mockRows(MOCK_DATA_LIST[0]))
+
+		res, err := mockRepo.Update(context.TODO(), "1", MOCK_DTO)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todo_lists")).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todo_lists WHERE id = ?")).
+			WithArgs("3").
+			WillReturnRows(mockRows())
+
+		res, err := mockRepo.Update(context.TODO(), "3", MOCK_DTO)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDelete(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todo_lists WHERE id = ?")).
+			WithArgs("1").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		err := mockRepo.Delete(context.TODO(), "1")
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockRepo, _, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM todo_lists WHERE id = ?")).
+			WithArgs("3").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		err := mockRepo.Delete(context.TODO(), "3")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+// TestContract runs the repository contract against a real server, it's skipped unless TEST_MYSQL_DSN is set
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+
+	db := helper.MySqlClient(dsn)
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	require.Nil(t, helper.MySqlExecSchema(context.TODO(), db, todoMysql.Schema))
+	require.Nil(t, helper.MySqlExecSchema(context.TODO(), db, mysql.Schema))
+
+	repotest.RunTodoListRepositoryTests(t, func(t *testing.T) (domain.TodoListRepository, domain.TodoRepository) {
+		for _, table := range []string{"todo_tags", "todos", "todo_lists"} {
+			_, err := db.Exec("DELETE FROM " + table)
+			require.Nil(t, err)
+		}
+
+		todoRepo := todoMysql.NewMysqlTodoRepository(db)
+
+		return mysql.NewMysqlTodoListRepository(db, todoRepo), todoRepo
+	})
+}
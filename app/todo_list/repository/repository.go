@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ariefsn/go-resik/app/todo_list/repository/memory"
+	"github.com/ariefsn/go-resik/app/todo_list/repository/mongo"
+	"github.com/ariefsn/go-resik/app/todo_list/repository/mysql"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+)
+
+// NewTodoListRepository will create the domain.TodoListRepository matching the driver of the given database,
+// the memory and mysql ones read the todos of the lists from the given todo repository
+func NewTodoListRepository(db *helper.Database, todoRepo domain.TodoRepository) (domain.TodoListRepository, error) {
+	switch db.Driver {
+	case helper.DbDriverMongo:
+		return mongo.NewMongoTodoListRepository(db.Mongo), nil
+	case helper.DbDriverMysql:
+		return mysql.NewMysqlTodoListRepository(db.Mysql, todoRepo), nil
+	case helper.DbDriverMemory:
+		return memory.NewMemoryTodoListRepository(todoRepo), nil
+	}
+
+	return nil, fmt.Errorf("unsupported db driver: %s", db.Driver)
+}
+
+// EnsureTodoListSchema will create the mysql tables the domain.TodoListRepository needs, the mongo collection needs
+// no index
+func EnsureTodoListSchema(ctx context.Context, db *helper.Database) error {
+	if db.Driver == helper.DbDriverMysql {
+		return helper.MySqlExecSchema(ctx, db.Mysql, mysql.Schema)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ariefsn/go-resik/domain"
+)
+
+type todoListService struct {
+	todoListRepo domain.TodoListRepository
+	todoRepo     domain.TodoRepository
+	revisionRepo domain.TodoRevisionRepository
+	transactor   domain.Transactor
+}
+
+// checkTarget checks the list the todos are reassigned to exists and isn't the deleted one, an empty one moves them out of any list
+func (s *todoListService) checkTarget(ctx context.Context, id string, to string) error {
+	if to == "" {
+		return nil
+	}
+
+	if to == id {
+		return domain.NewFieldValidationError(domain.FieldError{
+			Field:   "to",
+			Rule:    "ne",
+			Message: "to must be another todo list",
+		})
+	}
+
+	if _, err := s.todoListRepo.GetByID(ctx, to); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewFieldValidationError(domain.FieldError{
+				Field:   "to",
+				Rule:    "exists",
+				Message: "to must be an existing todo list",
+			})
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// Create implements domain.TodoListService.
+func (s *todoListService) Create(ctx context.Context, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	return s.todoListRepo.Create(ctx, payload)
+}
+
+// Delete implements domain.TodoListService.
+func (s *todoListService) Delete(ctx context.Context, id string, policy domain.ListDeletePolicy, to string) error {
+	// the todos are changed, recorded and the list is deleted in one transaction
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if _, err := s.todoListRepo.GetByID(ctx, id); err != nil {
+			return err
+		}
+
+		if policy == domain.ListDeleteReassign {
+			if err := s.checkTarget(ctx, id, to); err != nil {
+				return err
+			}
+		}
+
+		todos, total, err := s.todoRepo.Get(ctx, domain.Where("listId", domain.FoEq, id), nil, 0, -1)
+
+		if err != nil {
+			return err
+		}
+
+		if total > 0 {
+			ids := []string{}
+			values := []interface{}{}
+			revisions := []domain.TodoRevision{}
+
+			for _, v := range todos {
+				ids = append(ids, v.ID)
+				values = append(values, v.ID)
+			}
+
+			switch policy {
+			case domain.ListDeleteCascade:
+				// the subtasks out of the list would be left with a missing parent
+				_, others, err := s.todoRepo.Get(ctx, domain.And(
+					domain.Where("parentId", domain.FoIn, values),
+					domain.Where("listId", domain.FoNe, id),
+				), nil, 0, 1, "id")
+
+				if err != nil {
+					return err
+				}
+
+				if others > 0 {
+					return domain.NewConflictError("todos out of the list are subtasks of its todos, delete or move them first")
+				}
+
+				if err := s.todoRepo.DeleteMany(ctx, ids); err != nil {
+					return err
+				}
+
+				for _, v := range todos {
+					revisions = append(revisions, domain.NewTodoRevision(ctx, domain.RevisionDelete, &v, v))
+				}
+			case domain.ListDeleteReassign:
+				if err := s.todoRepo.UpdateListMany(ctx, ids, to); err != nil {
+					return err
+				}
+
+				for _, v := range todos {
+					after := v
+					after.ListID = to
+
+					revisions = append(revisions, domain.NewTodoRevision(ctx, domain.RevisionUpdate, &v, after))
+				}
+			default:
+				return domain.NewConflictError("todo list has todos, delete or move them first")
+			}
+
+			if _, err := s.revisionRepo.CreateMany(ctx, revisions); err != nil {
+				return err
+			}
+		}
+
+		return s.todoListRepo.Delete(ctx, id)
+	})
+}
+
+// Get implements domain.TodoListService.
+func (s *todoListService) Get(ctx context.Context, skip int64, limit int64) ([]domain.TodoList, int64, error) {
+	return s.todoListRepo.Get(ctx, skip, limit)
+}
+
+// GetByID implements domain.TodoListService.
+func (s *todoListService) GetByID(ctx context.Context, id string) (*domain.TodoListDetail, error) {
+	return s.todoListRepo.GetWithTodos(ctx, id)
+}
+
+// Update implements domain.TodoListService.
+func (s *todoListService) Update(ctx context.Context, id string, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	return s.todoListRepo.Update(ctx, id, payload)
+}
+
+// NewTodoListService will create new an todoListService object representation of domain.TodoListService interface,
+// the changes of the todos of a deleted list are recorded in the revision repository within the transaction of the
+// transactor deleting it
+func NewTodoListService(todoListRepo domain.TodoListRepository, todoRepo domain.TodoRepository, revisionRepo domain.TodoRevisionRepository, transactor domain.Transactor) domain.TodoListService {
+	return &todoListService{
+		todoListRepo: todoListRepo,
+		todoRepo:     todoRepo,
+		revisionRepo: revisionRepo,
+		transactor:   transactor,
+	}
+}
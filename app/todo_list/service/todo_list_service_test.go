@@ -0,0 +1,276 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ariefsn/go-resik/app/todo_list/service"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type txKey struct{}
+
+// transactor runs the units in a context telling they're in a transaction
+type transactor struct{}
+
+func (transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(context.WithValue(ctx, txKey{}, true))
+}
+
+// inTx matches the contexts of a transaction, the writes must be made in one along with their revisions
+var inTx = mock.MatchedBy(func(ctx context.Context) bool {
+	return ctx.Value(txKey{}) == true
+})
+
+var MOCK_DTO = &domain.TodoListDto{
+	Name:        "List 1",
+	Description: "Description 1",
+}
+
+var MOCK_DATA_SINGLE = domain.TodoList{
+	ID:          "10",
+	Name:        "List 1",
+	Description: "Description 1",
+}
+
+func TestCreate(t *testing.T) {
+	mockTodoListRepo := new(mocks.TodoListRepository)
+
+	mockTodoListRepo.On("Create", mock.Anything, MOCK_DTO).Return(&MOCK_DATA_SINGLE, nil).Once()
+
+	svc := service.NewTodoListService(mockTodoListRepo, new(mocks.TodoRepository), new(mocks.TodoRevisionRepository), transactor{})
+	res, err := svc.Create(context.TODO(), MOCK_DTO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &MOCK_DATA_SINGLE, res)
+}
+
+func TestGet(t *testing.T) {
+	mockTodoListRepo := new(mocks.TodoListRepository)
+
+	mockTodoListRepo.On("Get", mock.Anything, int64(0), int64(10)).Return([]domain.TodoList{MOCK_DATA_SINGLE}, int64(1), nil).Once()
+
+	svc := service.NewTodoListService(mockTodoListRepo, new(mocks.TodoRepository), new(mocks.TodoRevisionRepository), transactor{})
+	res, total, err := svc.Get(context.TODO(), 0, 10)
+
+	assert.Nil(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.Equal(t, []domain.TodoList{MOCK_DATA_SINGLE}, res)
+}
+
+func TestGetByID(t *testing.T) {
+	mockTodoListRepo := new(mocks.TodoListRepository)
+
+	detail := &domain.TodoListDetail{TodoList: MOCK_DATA_SINGLE, Todos: []domain.Todo{{ID: "1", ListID: "10"}}}
+
+	mockTodoListRepo.On("GetWithTodos", mock.Anything, "10").Return(detail, nil).Once()
+
+	svc := service.NewTodoListService(mockTodoListRepo, new(mocks.TodoRepository), new(mocks.TodoRevisionRepository), transactor{})
+	res, err := svc.GetByID(context.TODO(), "10")
+
+	assert.Nil(t, err)
+	assert.Equal(t, detail, res)
+}
+
+func TestUpdate(t *testing.T) {
+	mockTodoListRepo := new(mocks.TodoListRepository)
+
+	mockTodoListRepo.On("Update", mock.Anything, "10", MOCK_DTO).Return(&MOCK_DATA_SINGLE, nil).Once()
+
+	svc := service.NewTodoListService(mockTodoListRepo, new(mocks.TodoRepository), new(mocks.TodoRevisionRepository), transactor{})
+	res, err := svc.Update(context.TODO(), "10", MOCK_DTO)
+
+	assert.Nil(t, err)
+	assert.Equal(t, &MOCK_DATA_SINGLE, res)
+}
+
+func TestDelete(t *testing.T) {
+	inList := domain.Where("listId", domain.FoEq, "10")
+	todos := []domain.Todo{{ID: "1", ListID: "10"}, {ID: "2", ListID: "10"}}
+	subtasks := domain.And(
+		domain.Where("parentId", domain.FoIn, []interface{}{"1", "2"}),
+		domain.Where("listId", domain.FoNe, "10"),
+	)
+	mockError := errors.New("some error")
+
+	t.Run("Success - Empty", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, inList, []domain.Sort(nil), int64(0), int64(-1)).Return([]domain.Todo{}, int64(0), nil).Once()
+		mockTodoListRepo.On("Delete", mock.Anything, "10").Return(nil).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteRefuse, "")
+
+		assert.Nil(t, err)
+		mockTodoListRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(nil, domain.NewNotFoundError("todo list not found")).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteCascade, "")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		mockTodoRepo.AssertNotCalled(t, "Get", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed - Refuse", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, inList, []domain.Sort(nil), int64(0), int64(-1)).Return(todos, int64(2), nil).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteRefuse, "")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockTodoListRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Cascade", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, inList, []domain.Sort(nil), int64(0), int64(-1)).Return(todos, int64(2), nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, subtasks, []domain.Sort(nil), int64(0), int64(1), "id").Return([]domain.Todo{}, int64(0), nil).Once()
+		mockTodoRepo.On("DeleteMany", inTx, []string{"1", "2"}).Return(nil).Once()
+		mockRevisionRepo.On("CreateMany", inTx, []domain.TodoRevision{
+			domain.NewTodoRevision(context.TODO(), domain.RevisionDelete, &todos[0], todos[0]),
+			domain.NewTodoRevision(context.TODO(), domain.RevisionDelete, &todos[1], todos[1]),
+		}).Return(nil, nil).Once()
+		mockTodoListRepo.On("Delete", inTx, "10").Return(nil).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteCascade, "")
+
+		assert.Nil(t, err)
+		mockTodoRepo.AssertExpectations(t)
+		mockTodoListRepo.AssertExpectations(t)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed - Cascade Subtasks Out Of The List", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, inList, []domain.Sort(nil), int64(0), int64(-1)).Return(todos, int64(2), nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, subtasks, []domain.Sort(nil), int64(0), int64(1), "id").Return([]domain.Todo{{ID: "3"}}, int64(1), nil).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteCascade, "")
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		mockTodoRepo.AssertNotCalled(t, "DeleteMany", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failed - Cascade", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, inList, []domain.Sort(nil), int64(0), int64(-1)).Return(todos, int64(2), nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, subtasks, []domain.Sort(nil), int64(0), int64(1), "id").Return([]domain.Todo{}, int64(0), nil).Once()
+		mockTodoRepo.On("DeleteMany", mock.Anything, []string{"1", "2"}).Return(mockError).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteCascade, "")
+
+		assert.Equal(t, mockError, err)
+		mockTodoListRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - Reassign", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+		mockTodoListRepo.On("GetByID", mock.Anything, "11").Return(&domain.TodoList{ID: "11"}, nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, inList, []domain.Sort(nil), int64(0), int64(-1)).Return(todos, int64(2), nil).Once()
+		mockTodoRepo.On("UpdateListMany", mock.Anything, []string{"1", "2"}, "11").Return(nil).Once()
+		mockRevisionRepo.On("CreateMany", mock.Anything, mock.MatchedBy(func(v []domain.TodoRevision) bool {
+			return len(v) == 2 && v[0].Before.ListID == "10" && v[0].State.ListID == "11" && v[1].TodoID == "2"
+		})).Return(nil, nil).Once()
+		mockTodoListRepo.On("Delete", mock.Anything, "10").Return(nil).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteReassign, "11")
+
+		assert.Nil(t, err)
+		mockTodoRepo.AssertExpectations(t)
+		mockTodoListRepo.AssertExpectations(t)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - Reassign Out Of Any List", func(t *testing.T) {
+		mockTodoListRepo := new(mocks.TodoListRepository)
+		mockTodoRepo := new(mocks.TodoRepository)
+		mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+		mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+		mockTodoRepo.On("Get", mock.Anything, inList, []domain.Sort(nil), int64(0), int64(-1)).Return(todos, int64(2), nil).Once()
+		mockTodoRepo.On("UpdateListMany", mock.Anything, []string{"1", "2"}, "").Return(nil).Once()
+		mockRevisionRepo.On("CreateMany", mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mockTodoListRepo.On("Delete", mock.Anything, "10").Return(nil).Once()
+
+		svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+		err := svc.Delete(context.TODO(), "10", domain.ListDeleteReassign, "")
+
+		assert.Nil(t, err)
+		mockTodoRepo.AssertExpectations(t)
+	})
+
+	cases := []struct {
+		name    string
+		to      string
+		rule    string
+		missing bool
+	}{
+		{name: "Failed - Reassign To Itself", to: "10", rule: "ne"},
+		{name: "Failed - Reassign To Missing List", to: "12", rule: "exists", missing: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mockTodoListRepo := new(mocks.TodoListRepository)
+			mockTodoRepo := new(mocks.TodoRepository)
+			mockRevisionRepo := new(mocks.TodoRevisionRepository)
+
+			mockTodoListRepo.On("GetByID", mock.Anything, "10").Return(&MOCK_DATA_SINGLE, nil).Once()
+
+			if c.missing {
+				mockTodoListRepo.On("GetByID", mock.Anything, c.to).Return(nil, domain.NewNotFoundError("todo list not found")).Once()
+			}
+
+			svc := service.NewTodoListService(mockTodoListRepo, mockTodoRepo, mockRevisionRepo, transactor{})
+			err := svc.Delete(context.TODO(), "10", domain.ListDeleteReassign, c.to)
+
+			var domainErr *domain.Error
+
+			assert.True(t, errors.As(err, &domainErr))
+			assert.Equal(t, "to", domainErr.Fields[0].Field)
+			assert.Equal(t, c.rule, domainErr.Fields[0].Rule)
+			mockTodoRepo.AssertNotCalled(t, "UpdateListMany", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNotFound = domain.NewNotFoundError("todo revision not found")
+
+type memoryTodoRevisionRepository struct {
+	mu    sync.RWMutex
+	items []domain.TodoRevision
+}
+
+func cloneState(s domain.TodoState) domain.TodoState {
+	s.Tags = slices.Clone(s.Tags)
+
+	if s.DueAt != nil {
+		v := *s.DueAt
+		s.DueAt = &v
+	}
+
+	return s
+}
+
+func clone(r domain.TodoRevision) domain.TodoRevision {
+	r.State = cloneState(r.State)
+	r.Changes = nil
+
+	if r.Before != nil {
+		before := cloneState(*r.Before)
+		r.Before = &before
+	}
+
+	return r
+}
+
+// CreateMany implements domain.TodoRevisionRepository.
+func (r *memoryTodoRevisionRepository) CreateMany(ctx context.Context, payload []domain.TodoRevision) ([]domain.TodoRevision, error) {
+	result := []domain.TodoRevision{}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range payload {
+		data := clone(v)
+		data.ID = primitive.NewObjectID().Hex()
+		data.CreatedAt = now
+
+		r.items = append(r.items, clone(data))
+		result = append(result, data)
+	}
+
+	return result, nil
+}
+
+// Get implements domain.TodoRevisionRepository.
+func (r *memoryTodoRevisionRepository) Get(ctx context.Context, todoID string, skip int64, limit int64) ([]domain.TodoRevision, int64, error) {
+	result := []domain.TodoRevision{}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	matched := []domain.TodoRevision{}
+
+	for _, v := range r.items {
+		if v.TodoID == todoID {
+			matched = append(matched, v)
+		}
+	}
+
+	// the most recent revisions come first
+	slices.SortFunc(matched, func(a, b domain.TodoRevision) int {
+		if res := b.CreatedAt.Compare(a.CreatedAt); res != 0 {
+			return res
+		}
+
+		return strings.Compare(b.ID, a.ID)
+	})
+
+	for i, v := range matched {
+		if int64(i) < skip {
+			continue
+		}
+
+		if limit > -1 && int64(len(result)) >= limit {
+			break
+		}
+
+		result = append(result, clone(v))
+	}
+
+	return result, int64(len(matched)), nil
+}
+
+// GetByID implements domain.TodoRevisionRepository.
+func (r *memoryTodoRevisionRepository) GetByID(ctx context.Context, todoID string, id string) (*domain.TodoRevision, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i := slices.IndexFunc(r.items, func(v domain.TodoRevision) bool { return v.ID == id && v.TodoID == todoID })
+	if i < 0 {
+		return nil, errNotFound
+	}
+
+	result := clone(r.items[i])
+
+	return &result, nil
+}
+
+// NewMemoryTodoRevisionRepository will create an object that represent the todo_revision.Repository interface,
+// the data only lives as long as the process and is safe for concurrent use
+func NewMemoryTodoRevisionRepository() domain.TodoRevisionRepository {
+	return &memoryTodoRevisionRepository{
+		items: []domain.TodoRevision{},
+	}
+}
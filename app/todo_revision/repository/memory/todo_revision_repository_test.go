@@ -0,0 +1,41 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ariefsn/go-resik/app/todo_revision/repository/memory"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateMany(t *testing.T) {
+	mockRepo := memory.NewMemoryTodoRevisionRepository()
+
+	res, err := mockRepo.CreateMany(context.TODO(), []domain.TodoRevision{
+		{
+			TodoID: "1",
+			Action: domain.RevisionCreate,
+			Actor:  "alice",
+			State:  domain.TodoState{Title: "Title 1", Tags: []string{"home"}},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, res[0].ID)
+
+	// the stored revision isn't shared with the caller
+	res[0].State.Tags[0] = "changed"
+
+	stored, err := mockRepo.GetByID(context.TODO(), "1", res[0].ID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"home"}, stored.State.Tags)
+}
+
+func TestContract(t *testing.T) {
+	repotest.RunTodoRevisionRepositoryTests(t, func(t *testing.T) domain.TodoRevisionRepository {
+		return memory.NewMemoryTodoRevisionRepository()
+	})
+}
@@ -0,0 +1,138 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/ariefsn/go-resik/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// todoIndex backs the history of a todo, the revisions are read from the most recent one
+var todoIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "todoId", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}},
+	Options: options.Index().SetName("todo_revisions_todo"),
+}
+
+// EnsureIndexes creates the indexes of the todo revisions collection, creating an existing index is a no-op
+func EnsureIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(domain.TodoRevision{}.TableName()).Indexes().CreateMany(ctx, []mongo.IndexModel{todoIndex})
+
+	if err != nil {
+		logger.Error(err)
+		return helper.ParseMongoError(err)
+	}
+
+	return nil
+}
+
+type mongoTodoRevisionRepository struct {
+	Db *mongo.Database
+}
+
+// CreateMany implements domain.TodoRevisionRepository.
+func (r *mongoTodoRevisionRepository) CreateMany(ctx context.Context, payload []domain.TodoRevision) ([]domain.TodoRevision, error) {
+	result := []domain.TodoRevision{}
+	docs := []interface{}{}
+	now := time.Now()
+
+	for _, v := range payload {
+		v.ID = primitive.NewObjectID().Hex()
+		v.CreatedAt = now
+		v.Changes = nil
+
+		result = append(result, v)
+		docs = append(docs, v)
+	}
+
+	if len(docs) == 0 {
+		return result, nil
+	}
+
+	_, err := r.Db.Collection(domain.TodoRevision{}.TableName()).InsertMany(ctx, docs)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	return result, nil
+}
+
+// Get implements domain.TodoRevisionRepository.
+func (r *mongoTodoRevisionRepository) Get(ctx context.Context, todoID string, skip int64, limit int64) ([]domain.TodoRevision, int64, error) {
+	result := []domain.TodoRevision{}
+	filter := bson.M{"todoId": todoID}
+
+	count, err := r.Db.Collection(domain.TodoRevision{}.TableName()).CountDocuments(ctx, filter)
+
+	if err != nil && err != mongo.ErrNilDocument {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	// the most recent revisions come first
+	pipe := helper.MongoPipe(helper.MongoAggregate{
+		Match: filter,
+		Sort: []helper.MongoSort{
+			{SortField: "createdAt", SortBy: helper.SortByDesc},
+			{SortField: "_id", SortBy: helper.SortByDesc},
+		},
+		Skip:  &skip,
+		Limit: &limit,
+	})
+
+	cur, err := r.Db.Collection(domain.TodoRevision{}.TableName()).Aggregate(ctx, pipe)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, helper.ParseMongoError(err)
+	}
+
+	defer cur.Close(ctx)
+
+	for cur.Next(ctx) {
+		var row domain.TodoRevision
+
+		err = cur.Decode(&row)
+		if err != nil {
+			logger.Error(err)
+			return []domain.TodoRevision{}, 0, helper.ParseMongoError(err)
+		}
+
+		result = append(result, row)
+	}
+
+	if err := cur.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TodoRevision{}, 0, helper.ParseMongoError(err)
+	}
+
+	return result, count, nil
+}
+
+// GetByID implements domain.TodoRevisionRepository.
+func (r *mongoTodoRevisionRepository) GetByID(ctx context.Context, todoID string, id string) (*domain.TodoRevision, error) {
+	result := domain.TodoRevision{}
+
+	err := r.Db.Collection(result.TableName()).FindOne(ctx, bson.M{"_id": id, "todoId": todoID}).Decode(&result)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, helper.ParseMongoError(err)
+	}
+
+	return &result, nil
+}
+
+// NewMongoTodoRevisionRepository will create an object that represent the todo_revision.Repository interface
+func NewMongoTodoRevisionRepository(database *mongo.Database) domain.TodoRevisionRepository {
+	return &mongoTodoRevisionRepository{
+		Db: database,
+	}
+}
@@ -0,0 +1,212 @@
+package mongo_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/ariefsn/go-resik/app/todo_revision/repository/mongo"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mdb "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var MOCK_DATA = []domain.TodoRevision{
+	{
+		TodoID: "1",
+		Action: domain.RevisionCreate,
+		Actor:  "alice",
+		State:  domain.TodoState{Title: "Title 1", Tags: []string{}},
+	},
+	{
+		TodoID: "1",
+		Action: domain.RevisionUpdate,
+		Actor:  "bob",
+		Before: &domain.TodoState{Title: "Title 1", Tags: []string{}},
+		State:  domain.TodoState{Title: "Title 1 - Updated", Tags: []string{}},
+	},
+}
+
+var MOCK_DATA_SINGLE_BSOND = bson.D{
+	{Key: "_id", Value: "10"},
+	{Key: "todoId", Value: "1"},
+	{Key: "action", Value: "update"},
+	{Key: "actor", Value: "bob"},
+	{Key: "before", Value: bson.D{{Key: "title", Value: "Title 1"}}},
+	{Key: "state", Value: bson.D{{Key: "title", Value: "Title 1 - Updated"}}},
+}
+
+func TestCreateMany(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRevisionRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+
+		res, err := mockRepo.CreateMany(context.TODO(), MOCK_DATA)
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 2)
+		assert.NotEmpty(t, res[0].ID)
+
+		command := t.GetStartedEvent().Command
+
+		assert.Equal(t, "todo_revisions", command.Lookup("insert").StringValue())
+
+		docs := command.Lookup("documents").Array()
+
+		assert.Equal(t, "alice", docs.Index(0).Value().Document().Lookup("actor").StringValue())
+		assert.Equal(t, "Title 1", docs.Index(1).Value().Document().Lookup("before", "title").StringValue())
+	})
+
+	mt.Run("Success - Empty", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRevisionRepository(t.Client.Database("mock-db"))
+
+		res, err := mockRepo.CreateMany(context.TODO(), []domain.TodoRevision{})
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+		assert.Nil(t, t.GetStartedEvent())
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRevisionRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 1, Code: 12, Message: "some error"}))
+
+		res, err := mockRepo.CreateMany(context.TODO(), MOCK_DATA)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGet(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRevisionRepository(t.Client.Database("mock-db"))
+
+		// Counts
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todo_revisions", mtest.FirstBatch, bson.D{{Key: "n", Value: 3}}))
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_revisions", mtest.FirstBatch, MOCK_DATA_SINGLE_BSOND))
+
+		res, total, err := mockRepo.Get(context.TODO(), "1", 1, 1)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Len(t, res, 1)
+		assert.Equal(t, "Title 1", res[0].Before.Title)
+		assert.Equal(t, "Title 1 - Updated", res[0].State.Title)
+
+		// skips the count
+		t.GetStartedEvent()
+
+		pipeline := t.GetStartedEvent().Command.Lookup("pipeline").Array()
+
+		assert.Equal(t, "1", pipeline.Index(0).Value().Document().Lookup("$match", "todoId").StringValue())
+		assert.EqualValues(t, -1, pipeline.Index(1).Value().Document().Lookup("$sort", "createdAt").AsInt64())
+		assert.EqualValues(t, -1, pipeline.Index(1).Value().Document().Lookup("$sort", "_id").AsInt64())
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRevisionRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		res, total, err := mockRepo.Get(context.TODO(), "1", 0, 10)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Empty(t, res)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRevisionRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(1, "test.todo_revisions", mtest.FirstBatch, MOCK_DATA_SINGLE_BSOND))
+
+		res, err := mockRepo.GetByID(context.TODO(), "1", "10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "10", res.ID)
+		assert.Equal(t, domain.RevisionUpdate, res.Action)
+
+		filter := t.GetStartedEvent().Command.Lookup("filter").Document()
+
+		assert.Equal(t, "10", filter.Lookup("_id").StringValue())
+		assert.Equal(t, "1", filter.Lookup("todoId").StringValue())
+	})
+
+	mt.Run("Failed - Not Found", func(t *mtest.T) {
+		mockRepo := mongo.NewMongoTodoRevisionRepository(t.Client.Database("mock-db"))
+
+		t.AddMockResponses(mtest.CreateCursorResponse(0, "test.todo_revisions", mtest.FirstBatch))
+
+		res, err := mockRepo.GetByID(context.TODO(), "1", "10")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := mongo.EnsureIndexes(context.TODO(), t.Client.Database("mock-db"))
+
+		assert.Nil(t, err)
+
+		index := t.GetStartedEvent().Command.Lookup("indexes").Array().Index(0).Value().Document()
+
+		assert.Equal(t, "todo_revisions_todo", index.Lookup("name").StringValue())
+		assert.EqualValues(t, 1, index.Lookup("key", "todoId").AsInt64())
+	})
+
+	mt.Run("Failed", func(t *mtest.T) {
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 8000, Message: "some error"}))
+
+		err := mongo.EnsureIndexes(context.TODO(), t.Client.Database("mock-db"))
+
+		assert.NotNil(t, err)
+	})
+}
+
+// TestContract runs the repository contract against a real server, it's skipped unless TEST_MONGO_URI is set
+func TestContract(t *testing.T) {
+	uri := os.Getenv("TEST_MONGO_URI")
+	if uri == "" {
+		t.Skip("TEST_MONGO_URI is not set")
+	}
+
+	client, err := mdb.Connect(context.TODO(), options.Client().ApplyURI(uri))
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		client.Disconnect(context.TODO())
+	})
+
+	repotest.RunTodoRevisionRepositoryTests(t, func(t *testing.T) domain.TodoRevisionRepository {
+		db := client.Database("resik_test_" + primitive.NewObjectID().Hex())
+
+		t.Cleanup(func() {
+			db.Drop(context.TODO())
+		})
+
+		return mongo.NewMongoTodoRevisionRepository(db)
+	})
+}
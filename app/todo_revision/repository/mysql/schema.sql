@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS todo_revisions (
+  id VARCHAR(24) NOT NULL,
+  todo_id VARCHAR(24) NOT NULL,
+  action VARCHAR(20) NOT NULL,
+  actor VARCHAR(100) NOT NULL,
+  before_state JSON NULL,
+  state JSON NOT NULL,
+  created_at DATETIME(6) NOT NULL,
+  PRIMARY KEY (id),
+  KEY todo_revisions_todo (todo_id, created_at, id)
+);
@@ -0,0 +1,189 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/ariefsn/go-resik/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Schema is the DDL of the tables used by the todo revision repository
+//
+//go:embed schema.sql
+var Schema string
+
+// todoRevisionColumns are the columns of the revisions, the states are kept as json documents
+const todoRevisionColumns = "id, todo_id, action, actor, before_state, state, created_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+type mysqlTodoRevisionRepository struct {
+	Db *sql.DB
+}
+
+// conn returns what the queries run on, the transaction of the context if any
+func (r *mysqlTodoRevisionRepository) conn(ctx context.Context) helper.MySqlConn {
+	return helper.MySqlConnOf(ctx, r.Db)
+}
+
+func scanTodoRevision(row rowScanner) (*domain.TodoRevision, error) {
+	data := domain.TodoRevision{}
+
+	var before sql.NullString
+	var state string
+
+	err := row.Scan(&data.ID, &data.TodoID, &data.Action, &data.Actor, &before, &state, &data.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if before.Valid {
+		data.Before = &domain.TodoState{}
+
+		if err := json.Unmarshal([]byte(before.String), data.Before); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := json.Unmarshal([]byte(state), &data.State); err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// CreateMany implements domain.TodoRevisionRepository.
+func (r *mysqlTodoRevisionRepository) CreateMany(ctx context.Context, payload []domain.TodoRevision) ([]domain.TodoRevision, error) {
+	result := []domain.TodoRevision{}
+	placeholders := []string{}
+	args := []interface{}{}
+	now := time.Now()
+
+	for _, v := range payload {
+		v.ID = primitive.NewObjectID().Hex()
+		v.CreatedAt = now
+		v.Changes = nil
+
+		var before interface{}
+
+		if v.Before != nil {
+			b, err := json.Marshal(v.Before)
+			if err != nil {
+				return nil, err
+			}
+
+			before = string(b)
+		}
+
+		state, err := json.Marshal(v.State)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, v)
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?, ?, ?)")
+		args = append(args, v.ID, v.TodoID, v.Action, v.Actor, before, string(state), v.CreatedAt)
+	}
+
+	if len(placeholders) == 0 {
+		return result, nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", domain.TodoRevision{}.TableName(), todoRevisionColumns, strings.Join(placeholders, ", "))
+
+	_, err := r.conn(ctx).ExecContext(ctx, query, args...)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get implements domain.TodoRevisionRepository.
+func (r *mysqlTodoRevisionRepository) Get(ctx context.Context, todoID string, skip int64, limit int64) ([]domain.TodoRevision, int64, error) {
+	result := []domain.TodoRevision{}
+
+	var count int64
+
+	err := r.conn(ctx).QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE todo_id = ?", domain.TodoRevision{}.TableName()), todoID).Scan(&count)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	// the most recent revisions come first
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE todo_id = ? ORDER BY created_at DESC, id DESC", todoRevisionColumns, domain.TodoRevision{}.TableName())
+	args := []interface{}{todoID}
+
+	paging, pagingArgs := helper.MySqlLimit(skip, limit)
+	query += paging
+	args = append(args, pagingArgs...)
+
+	rows, err := r.conn(ctx).QueryContext(ctx, query, args...)
+
+	if err != nil {
+		logger.Error(err)
+		return result, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanTodoRevision(rows)
+		if err != nil {
+			logger.Error(err)
+			return []domain.TodoRevision{}, 0, err
+		}
+
+		result = append(result, *row)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error(err)
+		return []domain.TodoRevision{}, 0, err
+	}
+
+	return result, count, nil
+}
+
+// GetByID implements domain.TodoRevisionRepository.
+func (r *mysqlTodoRevisionRepository) GetByID(ctx context.Context, todoID string, id string) (*domain.TodoRevision, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE id = ? AND todo_id = ?", todoRevisionColumns, domain.TodoRevision{}.TableName())
+
+	result, err := scanTodoRevision(r.conn(ctx).QueryRowContext(ctx, query, id, todoID))
+
+	if err != nil {
+		logger.Error(err)
+
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.NewNotFoundError("todo revision not found", err)
+		}
+
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// NewMysqlTodoRevisionRepository will create an object that represent the todo_revision.Repository interface.
+// The connection must be opened with parseTime=true so the created_at column can be scanned.
+func NewMysqlTodoRevisionRepository(database *sql.DB) domain.TodoRevisionRepository {
+	return &mysqlTodoRevisionRepository{
+		Db: database,
+	}
+}
@@ -0,0 +1,205 @@
+package mysql_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ariefsn/go-resik/app/todo_revision/repository/mysql"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/domain/repotest"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var MOCK_DATA = []domain.TodoRevision{
+	{
+		TodoID: "1",
+		Action: domain.RevisionCreate,
+		Actor:  "alice",
+		State:  domain.TodoState{Title: "Title 1", Tags: []string{}},
+	},
+	{
+		TodoID: "1",
+		Action: domain.RevisionUpdate,
+		Actor:  "bob",
+		Before: &domain.TodoState{Title: "Title 1", Tags: []string{}},
+		State:  domain.TodoState{Title: "Title 1 - Updated", Tags: []string{}},
+	},
+}
+
+var COLUMNS = []string{"id", "todo_id", "action", "actor", "before_state", "state", "created_at"}
+
+const SELECT_COLUMNS = "id, todo_id, action, actor, before_state, state, created_at"
+
+func newMock(t *testing.T) (domain.TodoRevisionRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return mysql.NewMysqlTodoRevisionRepository(db), mock
+}
+
+func mockRows() *sqlmock.Rows {
+	return sqlmock.NewRows(COLUMNS).
+		AddRow("10", "1", "update", "bob", `{"title":"Title 1","tags":[]}`, `{"title":"Title 1 - Updated","tags":[]}`, time.Now())
+}
+
+func TestCreateMany(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todo_revisions ("+SELECT_COLUMNS+") VALUES (?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?)")).
+			WithArgs(
+				sqlmock.AnyArg(), "1", domain.RevisionCreate, "alice", nil, sqlmock.AnyArg(), sqlmock.AnyArg(),
+				sqlmock.AnyArg(), "1", domain.RevisionUpdate, "bob", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		res, err := mockRepo.CreateMany(context.TODO(), MOCK_DATA)
+
+		assert.Nil(t, err)
+		assert.Len(t, res, 2)
+		assert.NotEmpty(t, res[0].ID)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Empty", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		res, err := mockRepo.CreateMany(context.TODO(), []domain.TodoRevision{})
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todo_revisions")).
+			WillReturnError(errors.New("some error"))
+
+		res, err := mockRepo.CreateMany(context.TODO(), MOCK_DATA)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+	})
+}
+
+func TestGet(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todo_revisions WHERE todo_id = ?")).
+			WithArgs("1").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT "+SELECT_COLUMNS+" FROM todo_revisions WHERE todo_id = ? ORDER BY created_at DESC, id DESC LIMIT ? OFFSET ?")).
+			WithArgs("1", 1, 1).
+			WillReturnRows(mockRows())
+
+		res, total, err := mockRepo.Get(context.TODO(), "1", 1, 1)
+
+		assert.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Len(t, res, 1)
+		assert.Equal(t, "Title 1", res[0].Before.Title)
+		assert.Equal(t, "Title 1 - Updated", res[0].State.Title)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Success - Skip Without Limit", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todo_revisions")).
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+		mock.ExpectQuery(regexp.QuoteMeta("ORDER BY created_at DESC, id DESC LIMIT 18446744073709551615 OFFSET ?")).
+			WithArgs("1", 1).
+			WillReturnRows(mockRows())
+
+		_, _, err := mockRepo.Get(context.TODO(), "1", 1, -1)
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM todo_revisions")).
+			WillReturnError(errors.New("some error"))
+
+		res, total, err := mockRepo.Get(context.TODO(), "1", 0, 10)
+
+		assert.NotNil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Empty(t, res)
+	})
+}
+
+func TestGetByID(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("SELECT "+SELECT_COLUMNS+" FROM todo_revisions WHERE id = ? AND todo_id = ?")).
+			WithArgs("10", "1").
+			WillReturnRows(mockRows())
+
+		res, err := mockRepo.GetByID(context.TODO(), "1", "10")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "10", res.ID)
+		assert.Equal(t, domain.RevisionUpdate, res.Action)
+		assert.Equal(t, []string{}, res.State.Tags)
+	})
+
+	t.Run("Failed - Not Found", func(t *testing.T) {
+		mockRepo, mock := newMock(t)
+
+		mock.ExpectQuery(regexp.QuoteMeta("FROM todo_revisions WHERE id = ? AND todo_id = ?")).
+			WithArgs("10", "1").
+			WillReturnError(sql.ErrNoRows)
+
+		res, err := mockRepo.GetByID(context.TODO(), "1", "10")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
+
+// TestContract runs the repository contract against a real server, it's skipped unless TEST_MYSQL_DSN is set
+func TestContract(t *testing.T) {
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("TEST_MYSQL_DSN is not set")
+	}
+
+	db := helper.MySqlClient(dsn)
+
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	require.Nil(t, helper.MySqlExecSchema(context.TODO(), db, mysql.Schema))
+
+	repotest.RunTodoRevisionRepositoryTests(t, func(t *testing.T) domain.TodoRevisionRepository {
+		_, err := db.Exec("DELETE FROM todo_revisions")
+		require.Nil(t, err)
+
+		return mysql.NewMysqlTodoRevisionRepository(db)
+	})
+}
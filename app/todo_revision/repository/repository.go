@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ariefsn/go-resik/app/todo_revision/repository/memory"
+	"github.com/ariefsn/go-resik/app/todo_revision/repository/mongo"
+	"github.com/ariefsn/go-resik/app/todo_revision/repository/mysql"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+)
+
+// NewTodoRevisionRepository will create the domain.TodoRevisionRepository matching the driver of the given database
+func NewTodoRevisionRepository(db *helper.Database) (domain.TodoRevisionRepository, error) {
+	switch db.Driver {
+	case helper.DbDriverMongo:
+		return mongo.NewMongoTodoRevisionRepository(db.Mongo), nil
+	case helper.DbDriverMysql:
+		return mysql.NewMysqlTodoRevisionRepository(db.Mysql), nil
+	case helper.DbDriverMemory:
+		return memory.NewMemoryTodoRevisionRepository(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported db driver: %s", db.Driver)
+}
+
+// EnsureTodoRevisionSchema will create the mongo indexes or the mysql tables the domain.TodoRevisionRepository needs
+func EnsureTodoRevisionSchema(ctx context.Context, db *helper.Database) error {
+	switch db.Driver {
+	case helper.DbDriverMongo:
+		return mongo.EnsureIndexes(ctx, db.Mongo)
+	case helper.DbDriverMysql:
+		return helper.MySqlExecSchema(ctx, db.Mysql, mysql.Schema)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/ariefsn/go-resik/app/todo_revision/repository"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestNewTodoRevisionRepository(t *testing.T) {
+	cases := []struct {
+		name    string
+		success bool
+		db      *helper.Database
+	}{
+		{
+			name:    "Mongo",
+			success: true,
+			db: &helper.Database{
+				Driver: helper.DbDriverMongo,
+				Mongo:  &mongo.Database{},
+			},
+		},
+		{
+			name:    "Mysql",
+			success: true,
+			db: &helper.Database{
+				Driver: helper.DbDriverMysql,
+				Mysql:  &sql.DB{},
+			},
+		},
+		{
+			name:    "Memory",
+			success: true,
+			db: &helper.Database{
+				Driver: helper.DbDriverMemory,
+			},
+		},
+		{
+			name:    "Unsupported",
+			success: false,
+			db: &helper.Database{
+				Driver: "unknown",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := repository.NewTodoRevisionRepository(c.db)
+
+			if c.success {
+				assert.Nil(t, err)
+				assert.NotNil(t, res)
+			} else {
+				assert.NotNil(t, err)
+				assert.Nil(t, res)
+			}
+		})
+	}
+}
@@ -4,10 +4,19 @@ import "encoding/json"
 
 type M map[string]interface{}
 
+// ResponseError describe a single invalid field of a failed request
+type ResponseError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 type ResponseModel struct {
-	Status  bool        `json:"status"`
-	Data    interface{} `json:"data"`
-	Message string      `json:"message"`
+	Status  bool            `json:"status"`
+	Data    interface{}     `json:"data"`
+	Message string          `json:"message"`
+	Code    string          `json:"code,omitempty"`
+	Errors  []ResponseError `json:"errors,omitempty"`
 }
 
 func (m ResponseModel) ToM() M {
@@ -18,3 +27,55 @@ func (m ResponseModel) ToM() M {
 
 	return res
 }
+
+// ProblemModel is the RFC 7807 problem details document, Code and Errors are extension members
+type ProblemModel struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Detail   string          `json:"detail,omitempty"`
+	Instance string          `json:"instance,omitempty"`
+	Code     string          `json:"code,omitempty"`
+	Errors   []ResponseError `json:"errors,omitempty"`
+}
+
+func (m ProblemModel) ToM() M {
+	res := M{}
+
+	jByte, _ := json.Marshal(m)
+	json.Unmarshal(jByte, &res)
+
+	return res
+}
+
+// PageLinks: PageLinks model struct, the links of the pages which don't exist are empty
+type PageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// ToLinks returns the url and relation pairs of the existing links
+func (l PageLinks) ToLinks() []string {
+	res := []string{}
+
+	for _, v := range [][2]string{{l.Self, "self"}, {l.First, "first"}, {l.Prev, "prev"}, {l.Next, "next"}, {l.Last, "last"}} {
+		if v[0] != "" {
+			res = append(res, v[0], v[1])
+		}
+	}
+
+	return res
+}
+
+// PageModel: PageModel model struct
+type PageModel struct {
+	Items   interface{} `json:"items"`
+	Skip    int64       `json:"skip"`
+	Limit   int64       `json:"limit"`
+	Total   int64       `json:"total"`
+	HasMore bool        `json:"hasMore"`
+	Links   PageLinks   `json:"links"`
+}
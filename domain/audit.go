@@ -4,6 +4,7 @@ import "time"
 
 // Audit
 type Audit struct {
-	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
-	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt" bson:"updatedAt"`
+	CreatedAt time.Time  `json:"createdAt" bson:"createdAt"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
 }
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrorKind: ErrorKind classify the domain errors, the delivery layer maps them to a response
+type ErrorKind string
+
+const (
+	ErrKindNotFound     ErrorKind = "NOT_FOUND"
+	ErrKindValidation   ErrorKind = "VALIDATION"
+	ErrKindConflict     ErrorKind = "CONFLICT"
+	ErrKindPrecondition ErrorKind = "PRECONDITION_FAILED"
+	ErrKindUnauthorized ErrorKind = "UNAUTHORIZED"
+	ErrKindTimeout      ErrorKind = "TIMEOUT"
+	ErrKindUnavailable  ErrorKind = "UNAVAILABLE"
+)
+
+// FieldError: FieldError describe a single invalid field of a validation error
+type FieldError struct {
+	Field   string
+	Rule    string
+	Message string
+}
+
+// Error: Error is the typed error returned by services and repositories, Err keeps the original cause
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// Sentinels to be used with errors.Is, they match any Error of the same kind
+var (
+	ErrNotFound     = &Error{Kind: ErrKindNotFound, Message: "not found"}
+	ErrValidation   = &Error{Kind: ErrKindValidation, Message: "validation failed"}
+	ErrConflict     = &Error{Kind: ErrKindConflict, Message: "conflict"}
+	ErrPrecondition = &Error{Kind: ErrKindPrecondition, Message: "precondition failed"}
+	ErrUnauthorized = &Error{Kind: ErrKindUnauthorized, Message: "unauthorized"}
+	ErrTimeout      = &Error{Kind: ErrKindTimeout, Message: "timeout"}
+	ErrUnavailable  = &Error{Kind: ErrKindUnavailable, Message: "unavailable"}
+)
+
+func (e *Error) Error() string {
+	if e.Message == "" && e.Err != nil {
+		return e.Err.Error()
+	}
+
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+
+	return ok && t.Kind == e.Kind
+}
+
+func newError(kind ErrorKind, message string, cause []error) error {
+	e := &Error{
+		Kind:    kind,
+		Message: message,
+	}
+
+	if len(cause) > 0 {
+		e.Err = cause[0]
+	}
+
+	return e
+}
+
+func NewNotFoundError(message string, cause ...error) error {
+	return newError(ErrKindNotFound, message, cause)
+}
+
+func NewValidationError(message string, cause ...error) error {
+	return newError(ErrKindValidation, message, cause)
+}
+
+// NewFieldValidationError creates a validation error listing every offending field and the rule it failed
+func NewFieldValidationError(fields ...FieldError) error {
+	invalid := []string{}
+
+	for _, v := range fields {
+		invalid = append(invalid, fmt.Sprintf("%s (%s)", v.Field, v.Rule))
+	}
+
+	return &Error{
+		Kind:    ErrKindValidation,
+		Message: "validation failed: " + strings.Join(invalid, ", "),
+		Fields:  fields,
+	}
+}
+
+func NewConflictError(message string, cause ...error) error {
+	return newError(ErrKindConflict, message, cause)
+}
+
+// NewPreconditionError creates the error of a conditional write made against another version than the expected one
+func NewPreconditionError(message string, cause ...error) error {
+	return newError(ErrKindPrecondition, message, cause)
+}
+
+func NewUnauthorizedError(message string, cause ...error) error {
+	return newError(ErrKindUnauthorized, message, cause)
+}
+
+func NewTimeoutError(message string, cause ...error) error {
+	return newError(ErrKindTimeout, message, cause)
+}
+
+func NewUnavailableError(message string, cause ...error) error {
+	return newError(ErrKindUnavailable, message, cause)
+}
+
+// ErrorKindOf returns the kind of the first domain error in the chain, or an empty kind if there is none
+func ErrorKindOf(err error) ErrorKind {
+	var e *Error
+
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	return ""
+}
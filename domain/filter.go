@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"slices"
+	"time"
+)
+
+type FilterOperator string
+
+const (
+	FoContains  FilterOperator = "$contains"
+	FoStartWith FilterOperator = "$startWith"
+	FoEndWith   FilterOperator = "$endWith"
+	FoRegex     FilterOperator = "$regex"
+	FoEq        FilterOperator = "$eq"
+	FoNe        FilterOperator = "$ne"
+	FoIn        FilterOperator = "$in"
+	FoNin       FilterOperator = "$nin"
+	FoGt        FilterOperator = "$gt"
+	FoGte       FilterOperator = "$gte"
+	FoLt        FilterOperator = "$lt"
+	FoLte       FilterOperator = "$lte"
+	FoAll       FilterOperator = "$all"
+)
+
+// IsList reports whether the operator takes a list of values
+func (o FilterOperator) IsList() bool {
+	return o == FoIn || o == FoNin || o == FoAll
+}
+
+// MaxRegexLength caps the length of the $regex patterns, the other string operators match their value literally
+const MaxRegexLength = 100
+
+type FieldType string
+
+const (
+	FieldTypeString   FieldType = "string"
+	FieldTypeBool     FieldType = "bool"
+	FieldTypeTime     FieldType = "time"
+	FieldTypeTags     FieldType = "tags"
+	FieldTypePriority FieldType = "priority"
+)
+
+// FieldOperators are the operators supported by each field type
+var FieldOperators = map[FieldType][]FilterOperator{
+	FieldTypeString:   {FoEq, FoNe, FoIn, FoNin, FoGt, FoGte, FoLt, FoLte, FoContains, FoStartWith, FoEndWith, FoRegex},
+	FieldTypeBool:     {FoEq, FoNe, FoGt, FoGte, FoLt, FoLte},
+	FieldTypeTime:     {FoEq, FoNe, FoIn, FoNin, FoGt, FoGte, FoLt, FoLte},
+	FieldTypeTags:     {FoEq, FoNe, FoIn, FoNin, FoAll},
+	FieldTypePriority: {FoEq, FoNe, FoIn, FoNin, FoGt, FoGte, FoLt, FoLte},
+}
+
+// Filter: Filter is a node of the filter tree, either a condition on a single field or an And, Or or Not group.
+// Field is the json name of the field and Value holds its typed value (string, bool, time.Time or Priority),
+// or a []interface{} of them for $in, $nin and $all. A tags field matches when any of its tags does,
+// $all needs every value among the tags. A nil filter and groups without children match everything.
+// A nil Value of $eq and $ne matches the fields without a value or with one, $ne and $nin match
+// the fields without a value while the other operators don't.
+type Filter struct {
+	Field    string
+	Operator FilterOperator
+	Value    interface{}
+	And      []*Filter
+	Or       []*Filter
+	Not      *Filter
+}
+
+// Where creates a filter comparing the field with the value
+func Where(field string, operator FilterOperator, value interface{}) *Filter {
+	return &Filter{
+		Field:    field,
+		Operator: operator,
+		Value:    value,
+	}
+}
+
+// And creates a filter matching the items satisfying all of the filters
+func And(filters ...*Filter) *Filter {
+	return &Filter{And: filters}
+}
+
+// Or creates a filter matching the items satisfying any of the filters
+func Or(filters ...*Filter) *Filter {
+	return &Filter{Or: filters}
+}
+
+// Not creates a filter matching the items which don't satisfy the filter
+func Not(filter *Filter) *Filter {
+	return &Filter{Not: filter}
+}
+
+// IsCondition reports whether the filter compares a single field
+func (f *Filter) IsCondition() bool {
+	return f != nil && f.Field != ""
+}
+
+func validFilterValue(fieldType FieldType, value interface{}) bool {
+	switch fieldType {
+	case FieldTypeString, FieldTypeTags:
+		_, ok := value.(string)
+		return ok
+	case FieldTypeBool:
+		_, ok := value.(bool)
+		return ok
+	case FieldTypeTime:
+		_, ok := value.(time.Time)
+		return ok
+	case FieldTypePriority:
+		_, ok := value.(Priority)
+		return ok
+	}
+
+	return false
+}
+
+// isRepeat reports whether the expression matches its sub-expression a variable number of times, a fixed count
+// like {3} can only match one way
+func isRepeat(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		return true
+	case syntax.OpRepeat:
+		return re.Max == -1 || re.Max > re.Min && re.Max > 1
+	}
+
+	return false
+}
+
+// hasNestedRepeat reports whether a repetition holds another repetition or an alternation, like (a+)+ or (a|ab)*.
+// Those make a backtracking engine try an exponential number of ways to match.
+func hasNestedRepeat(re *syntax.Regexp, inRepeat bool) bool {
+	if inRepeat && (isRepeat(re) || re.Op == syntax.OpAlternate) {
+		return true
+	}
+
+	for _, sub := range re.Sub {
+		if hasNestedRepeat(sub, inRepeat || isRepeat(re)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ValidateRegex checks the pattern of a $regex condition. It's checked with the RE2 syntax, which has no
+// backreferences, yet the databases run it with backtracking engines, so the nested repetitions are rejected too.
+func ValidateRegex(pattern string) error {
+	if len(pattern) > MaxRegexLength {
+		return fmt.Errorf("must not be longer than %d characters", MaxRegexLength)
+	}
+
+	re, err := syntax.Parse(pattern, syntax.Perl)
+
+	if err != nil {
+		return fmt.Errorf("must be a valid regular expression")
+	}
+
+	if hasNestedRepeat(re, false) {
+		return fmt.Errorf("must not repeat a repetition or an alternation")
+	}
+
+	return nil
+}
+
+// Validate checks the fields, operators and value types of the whole tree against the given fields
+func (f *Filter) Validate(fields map[string]FieldType) error {
+	if f == nil {
+		return nil
+	}
+
+	if f.IsCondition() {
+		fieldType, ok := fields[f.Field]
+		if !ok {
+			return NewValidationError(fmt.Sprintf("filter field %s is not supported", f.Field))
+		}
+
+		if !slices.Contains(FieldOperators[fieldType], f.Operator) {
+			return NewValidationError(fmt.Sprintf("filter operator %s is not supported by %s", f.Operator, f.Field))
+		}
+
+		values := []interface{}{f.Value}
+		if f.Operator.IsList() {
+			values, ok = f.Value.([]interface{})
+			if !ok {
+				return NewValidationError(fmt.Sprintf("filter value of %s %s must be a list", f.Field, f.Operator))
+			}
+		}
+
+		for _, v := range values {
+			if v == nil && (f.Operator == FoEq || f.Operator == FoNe) {
+				continue
+			}
+
+			if !validFilterValue(fieldType, v) {
+				return NewValidationError(fmt.Sprintf("filter value of %s must be a %s", f.Field, fieldType))
+			}
+		}
+
+		if f.Operator == FoRegex {
+			if err := ValidateRegex(f.Value.(string)); err != nil {
+				return NewValidationError(fmt.Sprintf("filter value of %s %s %s", f.Field, f.Operator, err.Error()))
+			}
+		}
+	}
+
+	for _, v := range append(append([]*Filter{}, f.And...), f.Or...) {
+		if err := v.Validate(fields); err != nil {
+			return err
+		}
+	}
+
+	return f.Not.Validate(fields)
+}
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/ariefsn/go-resik/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // TodoRepository is an autogenerated mock type for the TodoRepository type
@@ -14,6 +16,32 @@ type TodoRepository struct {
 	mock.Mock
 }
 
+// AddTags provides a mock function with given fields: ctx, id, tags
+func (_m *TodoRepository) AddTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, tags)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*domain.Todo, error)); ok {
+		return rf(ctx, id, tags)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *domain.Todo); ok {
+		r0 = rf(ctx, id, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, id, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, payload
 func (_m *TodoRepository) Create(ctx context.Context, payload *domain.TodoDto) (*domain.Todo, error) {
 	ret := _m.Called(ctx, payload)
@@ -40,13 +68,13 @@ func (_m *TodoRepository) Create(ctx context.Context, payload *domain.TodoDto) (
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *TodoRepository) Delete(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *TodoRepository) Delete(ctx context.Context, id string, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -54,17 +82,196 @@ func (_m *TodoRepository) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: ctx, filter, skip, limit
-func (_m *TodoRepository) Get(ctx context.Context, filter interface{}, skip int64, limit int64) ([]domain.Todo, int64, error) {
+// DeleteMany provides a mock function with given fields: ctx, ids
+func (_m *TodoRepository) DeleteMany(ctx context.Context, ids []string) error {
+	ret := _m.Called(ctx, ids)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, filter, sort, skip, limit, fields
+func (_m *TodoRepository) Get(ctx context.Context, filter *domain.Filter, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, filter, sort, skip, limit)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []domain.Todo
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) ([]domain.Todo, int64, error)); ok {
+		return rf(ctx, filter, sort, skip, limit, fields...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) []domain.Todo); ok {
+		r0 = rf(ctx, filter, sort, skip, limit, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) int64); ok {
+		r1 = rf(ctx, filter, sort, skip, limit, fields...)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) error); ok {
+		r2 = rf(ctx, filter, sort, skip, limit, fields...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByCursor provides a mock function with given fields: ctx, filter, sort, cursor, limit, fields
+func (_m *TodoRepository) GetByCursor(ctx context.Context, filter *domain.Filter, sort []domain.Sort, cursor *domain.Cursor, limit int64, fields ...string) ([]domain.Todo, bool, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, filter, sort, cursor, limit)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []domain.Todo
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, *domain.Cursor, int64, ...string) ([]domain.Todo, bool, error)); ok {
+		return rf(ctx, filter, sort, cursor, limit, fields...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, *domain.Cursor, int64, ...string) []domain.Todo); ok {
+		r0 = rf(ctx, filter, sort, cursor, limit, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Filter, []domain.Sort, *domain.Cursor, int64, ...string) bool); ok {
+		r1 = rf(ctx, filter, sort, cursor, limit, fields...)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.Filter, []domain.Sort, *domain.Cursor, int64, ...string) error); ok {
+		r2 = rf(ctx, filter, sort, cursor, limit, fields...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: ctx, id, fields
+func (_m *TodoRepository) GetByID(ctx context.Context, id string, fields ...string) (*domain.Todo, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) (*domain.Todo, error)); ok {
+		return rf(ctx, id, fields...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) *domain.Todo); ok {
+		r0 = rf(ctx, id, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...string) error); ok {
+		r1 = rf(ctx, id, fields...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubtree provides a mock function with given fields: ctx, id
+func (_m *TodoRepository) GetSubtree(ctx context.Context, id string) ([]domain.TodoNode, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []domain.TodoNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.TodoNode, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.TodoNode); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TodoNode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTags provides a mock function with given fields: ctx
+func (_m *TodoRepository) GetTags(ctx context.Context) ([]domain.TagCount, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.TagCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.TagCount, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.TagCount); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: ctx, filter, skip, limit
+func (_m *TodoRepository) GetTrash(ctx context.Context, filter *domain.Filter, skip int64, limit int64) ([]domain.Todo, int64, error) {
 	ret := _m.Called(ctx, filter, skip, limit)
 
 	var r0 []domain.Todo
 	var r1 int64
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int64, int64) ([]domain.Todo, int64, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, int64, int64) ([]domain.Todo, int64, error)); ok {
 		return rf(ctx, filter, skip, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int64, int64) []domain.Todo); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, int64, int64) []domain.Todo); ok {
 		r0 = rf(ctx, filter, skip, limit)
 	} else {
 		if ret.Get(0) != nil {
@@ -72,13 +279,13 @@ func (_m *TodoRepository) Get(ctx context.Context, filter interface{}, skip int6
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int64, int64) int64); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Filter, int64, int64) int64); ok {
 		r1 = rf(ctx, filter, skip, limit)
 	} else {
 		r1 = ret.Get(1).(int64)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int64, int64) error); ok {
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.Filter, int64, int64) error); ok {
 		r2 = rf(ctx, filter, skip, limit)
 	} else {
 		r2 = ret.Error(2)
@@ -87,8 +294,91 @@ func (_m *TodoRepository) Get(ctx context.Context, filter interface{}, skip int6
 	return r0, r1, r2
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *TodoRepository) GetByID(ctx context.Context, id string) (*domain.Todo, error) {
+// Purge provides a mock function with given fields: ctx, before
+func (_m *TodoRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTags provides a mock function with given fields: ctx, id, tags
+func (_m *TodoRepository) RemoveTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, tags)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*domain.Todo, error)); ok {
+		return rf(ctx, id, tags)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *domain.Todo); ok {
+		r0 = rf(ctx, id, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, id, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Replace provides a mock function with given fields: ctx, id, payload
+func (_m *TodoRepository) Replace(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, bool, error) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 *domain.Todo
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto) (*domain.Todo, bool, error)); ok {
+		return rf(ctx, id, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto) *domain.Todo); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.TodoDto) bool); ok {
+		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *domain.TodoDto) error); ok {
+		r2 = rf(ctx, id, payload)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *TodoRepository) Restore(ctx context.Context, id string) (*domain.Todo, error) {
 	ret := _m.Called(ctx, id)
 
 	var r0 *domain.Todo
@@ -113,25 +403,84 @@ func (_m *TodoRepository) GetByID(ctx context.Context, id string) (*domain.Todo,
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, id, payload
-func (_m *TodoRepository) Update(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, error) {
-	ret := _m.Called(ctx, id, payload)
+// Revert provides a mock function with given fields: ctx, id, state
+func (_m *TodoRepository) Revert(ctx context.Context, id string, state domain.TodoState) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, state)
 
 	var r0 *domain.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto) (*domain.Todo, error)); ok {
-		return rf(ctx, id, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TodoState) (*domain.Todo, error)); ok {
+		return rf(ctx, id, state)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto) *domain.Todo); ok {
-		r0 = rf(ctx, id, payload)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.TodoState) *domain.Todo); ok {
+		r0 = rf(ctx, id, state)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.TodoDto) error); ok {
-		r1 = rf(ctx, id, payload)
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.TodoState) error); ok {
+		r1 = rf(ctx, id, state)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query, skip, limit
+func (_m *TodoRepository) Search(ctx context.Context, query string, skip int64, limit int64) ([]domain.TodoSearchResult, int64, error) {
+	ret := _m.Called(ctx, query, skip, limit)
+
+	var r0 []domain.TodoSearchResult
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) ([]domain.TodoSearchResult, int64, error)); ok {
+		return rf(ctx, query, skip, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) []domain.TodoSearchResult); ok {
+		r0 = rf(ctx, query, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TodoSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) int64); ok {
+		r1 = rf(ctx, query, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, int64) error); ok {
+		r2 = rf(ctx, query, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Update provides a mock function with given fields: ctx, id, payload, version
+func (_m *TodoRepository) Update(ctx context.Context, id string, payload *domain.TodoDto, version int64) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, payload, version)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto, int64) (*domain.Todo, error)); ok {
+		return rf(ctx, id, payload, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto, int64) *domain.Todo); ok {
+		r0 = rf(ctx, id, payload, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.TodoDto, int64) error); ok {
+		r1 = rf(ctx, id, payload, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,25 +488,39 @@ func (_m *TodoRepository) Update(ctx context.Context, id string, payload *domain
 	return r0, r1
 }
 
-// UpdateStatus provides a mock function with given fields: ctx, id, isCompleted
-func (_m *TodoRepository) UpdateStatus(ctx context.Context, id string, isCompleted bool) (*domain.Todo, error) {
-	ret := _m.Called(ctx, id, isCompleted)
+// UpdateListMany provides a mock function with given fields: ctx, ids, listID
+func (_m *TodoRepository) UpdateListMany(ctx context.Context, ids []string, listID string) error {
+	ret := _m.Called(ctx, ids, listID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string) error); ok {
+		r0 = rf(ctx, ids, listID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, id, isCompleted, version
+func (_m *TodoRepository) UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, isCompleted, version)
 
 	var r0 *domain.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*domain.Todo, error)); ok {
-		return rf(ctx, id, isCompleted)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, int64) (*domain.Todo, error)); ok {
+		return rf(ctx, id, isCompleted, version)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *domain.Todo); ok {
-		r0 = rf(ctx, id, isCompleted)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, int64) *domain.Todo); ok {
+		r0 = rf(ctx, id, isCompleted, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
-		r1 = rf(ctx, id, isCompleted)
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, int64) error); ok {
+		r1 = rf(ctx, id, isCompleted, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -165,6 +528,20 @@ func (_m *TodoRepository) UpdateStatus(ctx context.Context, id string, isComplet
 	return r0, r1
 }
 
+// UpdateStatusMany provides a mock function with given fields: ctx, ids, isCompleted
+func (_m *TodoRepository) UpdateStatusMany(ctx context.Context, ids []string, isCompleted bool) error {
+	ret := _m.Called(ctx, ids, isCompleted)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, bool) error); ok {
+		r0 = rf(ctx, ids, isCompleted)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewTodoRepository creates a new instance of TodoRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewTodoRepository(t interface {
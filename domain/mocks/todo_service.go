@@ -14,6 +14,58 @@ type TodoService struct {
 	mock.Mock
 }
 
+// AddTags provides a mock function with given fields: ctx, id, tags
+func (_m *TodoService) AddTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, tags)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*domain.Todo, error)); ok {
+		return rf(ctx, id, tags)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *domain.Todo); ok {
+		r0 = rf(ctx, id, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, id, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Complete provides a mock function with given fields: ctx, id, cascade, version
+func (_m *TodoService) Complete(ctx context.Context, id string, cascade bool, version int64) (*domain.TodoCompletion, error) {
+	ret := _m.Called(ctx, id, cascade, version)
+
+	var r0 *domain.TodoCompletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, int64) (*domain.TodoCompletion, error)); ok {
+		return rf(ctx, id, cascade, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, int64) *domain.TodoCompletion); ok {
+		r0 = rf(ctx, id, cascade, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TodoCompletion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, int64) error); ok {
+		r1 = rf(ctx, id, cascade, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, payload
 func (_m *TodoService) Create(ctx context.Context, payload *domain.TodoDto) (*domain.Todo, error) {
 	ret := _m.Called(ctx, payload)
@@ -40,13 +92,13 @@ func (_m *TodoService) Create(ctx context.Context, payload *domain.TodoDto) (*do
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: ctx, id
-func (_m *TodoService) Delete(ctx context.Context, id string) error {
-	ret := _m.Called(ctx, id)
+// Delete provides a mock function with given fields: ctx, id, version
+func (_m *TodoService) Delete(ctx context.Context, id string, version int64) error {
+	ret := _m.Called(ctx, id, version)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, id)
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, id, version)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -54,32 +106,39 @@ func (_m *TodoService) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
-// Get provides a mock function with given fields: ctx, filter, skip, limit
-func (_m *TodoService) Get(ctx context.Context, filter interface{}, skip int64, limit int64) ([]domain.Todo, int64, error) {
-	ret := _m.Called(ctx, filter, skip, limit)
+// Get provides a mock function with given fields: ctx, filter, sort, skip, limit, fields
+func (_m *TodoService) Get(ctx context.Context, filter *domain.Filter, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, filter, sort, skip, limit)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 []domain.Todo
 	var r1 int64
 	var r2 error
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int64, int64) ([]domain.Todo, int64, error)); ok {
-		return rf(ctx, filter, skip, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) ([]domain.Todo, int64, error)); ok {
+		return rf(ctx, filter, sort, skip, limit, fields...)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, interface{}, int64, int64) []domain.Todo); ok {
-		r0 = rf(ctx, filter, skip, limit)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) []domain.Todo); ok {
+		r0 = rf(ctx, filter, sort, skip, limit, fields...)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, interface{}, int64, int64) int64); ok {
-		r1 = rf(ctx, filter, skip, limit)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) int64); ok {
+		r1 = rf(ctx, filter, sort, skip, limit, fields...)
 	} else {
 		r1 = ret.Get(1).(int64)
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, interface{}, int64, int64) error); ok {
-		r2 = rf(ctx, filter, skip, limit)
+	if rf, ok := ret.Get(2).(func(context.Context, *domain.Filter, []domain.Sort, int64, int64, ...string) error); ok {
+		r2 = rf(ctx, filter, sort, skip, limit, fields...)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -87,20 +146,159 @@ func (_m *TodoService) Get(ctx context.Context, filter interface{}, skip int64,
 	return r0, r1, r2
 }
 
-// GetByID provides a mock function with given fields: ctx, id
-func (_m *TodoService) GetByID(ctx context.Context, id string) (*domain.Todo, error) {
-	ret := _m.Called(ctx, id)
+// GetByCursor provides a mock function with given fields: ctx, filter, sort, cursor, limit, fields
+func (_m *TodoService) GetByCursor(ctx context.Context, filter *domain.Filter, sort []domain.Sort, cursor *domain.Cursor, limit int64, fields ...string) (*domain.TodoPage, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, filter, sort, cursor, limit)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *domain.TodoPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, *domain.Cursor, int64, ...string) (*domain.TodoPage, error)); ok {
+		return rf(ctx, filter, sort, cursor, limit, fields...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Filter, []domain.Sort, *domain.Cursor, int64, ...string) *domain.TodoPage); ok {
+		r0 = rf(ctx, filter, sort, cursor, limit, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TodoPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.Filter, []domain.Sort, *domain.Cursor, int64, ...string) error); ok {
+		r1 = rf(ctx, filter, sort, cursor, limit, fields...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: ctx, id, fields
+func (_m *TodoService) GetByID(ctx context.Context, id string, fields ...string) (*domain.Todo, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, id)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
 
 	var r0 *domain.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Todo, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) (*domain.Todo, error)); ok {
+		return rf(ctx, id, fields...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...string) *domain.Todo); ok {
+		r0 = rf(ctx, id, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...string) error); ok {
+		r1 = rf(ctx, id, fields...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHistory provides a mock function with given fields: ctx, id, skip, limit
+func (_m *TodoService) GetHistory(ctx context.Context, id string, skip int64, limit int64) ([]domain.TodoRevision, int64, error) {
+	ret := _m.Called(ctx, id, skip, limit)
+
+	var r0 []domain.TodoRevision
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) ([]domain.TodoRevision, int64, error)); ok {
+		return rf(ctx, id, skip, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) []domain.TodoRevision); ok {
+		r0 = rf(ctx, id, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TodoRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) int64); ok {
+		r1 = rf(ctx, id, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, int64) error); ok {
+		r2 = rf(ctx, id, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetOverdue provides a mock function with given fields: ctx, sort, skip, limit, fields
+func (_m *TodoService) GetOverdue(ctx context.Context, sort []domain.Sort, skip int64, limit int64, fields ...string) ([]domain.Todo, int64, error) {
+	_va := make([]interface{}, len(fields))
+	for _i := range fields {
+		_va[_i] = fields[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, sort, skip, limit)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 []domain.Todo
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Sort, int64, int64, ...string) ([]domain.Todo, int64, error)); ok {
+		return rf(ctx, sort, skip, limit, fields...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Sort, int64, int64, ...string) []domain.Todo); ok {
+		r0 = rf(ctx, sort, skip, limit, fields...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.Sort, int64, int64, ...string) int64); ok {
+		r1 = rf(ctx, sort, skip, limit, fields...)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []domain.Sort, int64, int64, ...string) error); ok {
+		r2 = rf(ctx, sort, skip, limit, fields...)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetSubtree provides a mock function with given fields: ctx, id
+func (_m *TodoService) GetSubtree(ctx context.Context, id string) (*domain.TodoNode, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.TodoNode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TodoNode, error)); ok {
 		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Todo); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TodoNode); ok {
 		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Todo)
+			r0 = ret.Get(0).(*domain.TodoNode)
 		}
 	}
 
@@ -113,13 +311,123 @@ func (_m *TodoService) GetByID(ctx context.Context, id string) (*domain.Todo, er
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: ctx, id, payload
-func (_m *TodoService) Update(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, error) {
-	ret := _m.Called(ctx, id, payload)
+// GetTags provides a mock function with given fields: ctx
+func (_m *TodoService) GetTags(ctx context.Context) ([]domain.TagCount, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []domain.TagCount
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.TagCount, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.TagCount); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TagCount)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTrash provides a mock function with given fields: ctx, skip, limit
+func (_m *TodoService) GetTrash(ctx context.Context, skip int64, limit int64) ([]domain.Todo, int64, error) {
+	ret := _m.Called(ctx, skip, limit)
+
+	var r0 []domain.Todo
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]domain.Todo, int64, error)); ok {
+		return rf(ctx, skip, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []domain.Todo); ok {
+		r0 = rf(ctx, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) int64); ok {
+		r1 = rf(ctx, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, int64) error); ok {
+		r2 = rf(ctx, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Purge provides a mock function with given fields: ctx
+func (_m *TodoService) Purge(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveTags provides a mock function with given fields: ctx, id, tags
+func (_m *TodoService) RemoveTags(ctx context.Context, id string, tags []string) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, tags)
 
 	var r0 *domain.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto) (*domain.Todo, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (*domain.Todo, error)); ok {
+		return rf(ctx, id, tags)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) *domain.Todo); ok {
+		r0 = rf(ctx, id, tags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, id, tags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Replace provides a mock function with given fields: ctx, id, payload
+func (_m *TodoService) Replace(ctx context.Context, id string, payload *domain.TodoDto) (*domain.Todo, bool, error) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 *domain.Todo
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto) (*domain.Todo, bool, error)); ok {
 		return rf(ctx, id, payload)
 	}
 	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto) *domain.Todo); ok {
@@ -130,8 +438,125 @@ func (_m *TodoService) Update(ctx context.Context, id string, payload *domain.To
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.TodoDto) error); ok {
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.TodoDto) bool); ok {
 		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *domain.TodoDto) error); ok {
+		r2 = rf(ctx, id, payload)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *TodoService) Restore(ctx context.Context, id string) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Todo, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Todo); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revert provides a mock function with given fields: ctx, id, revisionID
+func (_m *TodoService) Revert(ctx context.Context, id string, revisionID string) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, revisionID)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.Todo, error)); ok {
+		return rf(ctx, id, revisionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.Todo); ok {
+		r0 = rf(ctx, id, revisionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, id, revisionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Search provides a mock function with given fields: ctx, query, skip, limit
+func (_m *TodoService) Search(ctx context.Context, query string, skip int64, limit int64) ([]domain.TodoSearchResult, int64, error) {
+	ret := _m.Called(ctx, query, skip, limit)
+
+	var r0 []domain.TodoSearchResult
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) ([]domain.TodoSearchResult, int64, error)); ok {
+		return rf(ctx, query, skip, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) []domain.TodoSearchResult); ok {
+		r0 = rf(ctx, query, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TodoSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) int64); ok {
+		r1 = rf(ctx, query, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, int64) error); ok {
+		r2 = rf(ctx, query, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Update provides a mock function with given fields: ctx, id, payload, version
+func (_m *TodoService) Update(ctx context.Context, id string, payload *domain.TodoDto, version int64) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, payload, version)
+
+	var r0 *domain.Todo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto, int64) (*domain.Todo, error)); ok {
+		return rf(ctx, id, payload, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoDto, int64) *domain.Todo); ok {
+		r0 = rf(ctx, id, payload, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Todo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.TodoDto, int64) error); ok {
+		r1 = rf(ctx, id, payload, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,25 +564,25 @@ func (_m *TodoService) Update(ctx context.Context, id string, payload *domain.To
 	return r0, r1
 }
 
-// UpdateStatus provides a mock function with given fields: ctx, id, isCompleted
-func (_m *TodoService) UpdateStatus(ctx context.Context, id string, isCompleted bool) (*domain.Todo, error) {
-	ret := _m.Called(ctx, id, isCompleted)
+// UpdateStatus provides a mock function with given fields: ctx, id, isCompleted, version
+func (_m *TodoService) UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*domain.Todo, error) {
+	ret := _m.Called(ctx, id, isCompleted, version)
 
 	var r0 *domain.Todo
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) (*domain.Todo, error)); ok {
-		return rf(ctx, id, isCompleted)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, int64) (*domain.Todo, error)); ok {
+		return rf(ctx, id, isCompleted, version)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, bool) *domain.Todo); ok {
-		r0 = rf(ctx, id, isCompleted)
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool, int64) *domain.Todo); ok {
+		r0 = rf(ctx, id, isCompleted, version)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Todo)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, bool) error); ok {
-		r1 = rf(ctx, id, isCompleted)
+	if rf, ok := ret.Get(1).(func(context.Context, string, bool, int64) error); ok {
+		r1 = rf(ctx, id, isCompleted, version)
 	} else {
 		r1 = ret.Error(1)
 	}
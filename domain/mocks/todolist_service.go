@@ -0,0 +1,154 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/ariefsn/go-resik/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TodoListService is an autogenerated mock type for the TodoListService type
+type TodoListService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, payload
+func (_m *TodoListService) Create(ctx context.Context, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	ret := _m.Called(ctx, payload)
+
+	var r0 *domain.TodoList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TodoListDto) (*domain.TodoList, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.TodoListDto) *domain.TodoList); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TodoList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.TodoListDto) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id, policy, to
+func (_m *TodoListService) Delete(ctx context.Context, id string, policy domain.ListDeletePolicy, to string) error {
+	ret := _m.Called(ctx, id, policy, to)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.ListDeletePolicy, string) error); ok {
+		r0 = rf(ctx, id, policy, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, skip, limit
+func (_m *TodoListService) Get(ctx context.Context, skip int64, limit int64) ([]domain.TodoList, int64, error) {
+	ret := _m.Called(ctx, skip, limit)
+
+	var r0 []domain.TodoList
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]domain.TodoList, int64, error)); ok {
+		return rf(ctx, skip, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []domain.TodoList); ok {
+		r0 = rf(ctx, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TodoList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) int64); ok {
+		r1 = rf(ctx, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, int64) error); ok {
+		r2 = rf(ctx, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *TodoListService) GetByID(ctx context.Context, id string) (*domain.TodoListDetail, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *domain.TodoListDetail
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.TodoListDetail, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.TodoListDetail); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TodoListDetail)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, id, payload
+func (_m *TodoListService) Update(ctx context.Context, id string, payload *domain.TodoListDto) (*domain.TodoList, error) {
+	ret := _m.Called(ctx, id, payload)
+
+	var r0 *domain.TodoList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoListDto) (*domain.TodoList, error)); ok {
+		return rf(ctx, id, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.TodoListDto) *domain.TodoList); ok {
+		r0 = rf(ctx, id, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TodoList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *domain.TodoListDto) error); ok {
+		r1 = rf(ctx, id, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTodoListService creates a new instance of TodoListService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTodoListService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TodoListService {
+	mock := &TodoListService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,114 @@
+// Code generated by mockery v2.34.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/ariefsn/go-resik/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TodoRevisionRepository is an autogenerated mock type for the TodoRevisionRepository type
+type TodoRevisionRepository struct {
+	mock.Mock
+}
+
+// CreateMany provides a mock function with given fields: ctx, payload
+func (_m *TodoRevisionRepository) CreateMany(ctx context.Context, payload []domain.TodoRevision) ([]domain.TodoRevision, error) {
+	ret := _m.Called(ctx, payload)
+
+	var r0 []domain.TodoRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.TodoRevision) ([]domain.TodoRevision, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.TodoRevision) []domain.TodoRevision); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TodoRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.TodoRevision) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: ctx, todoID, skip, limit
+func (_m *TodoRevisionRepository) Get(ctx context.Context, todoID string, skip int64, limit int64) ([]domain.TodoRevision, int64, error) {
+	ret := _m.Called(ctx, todoID, skip, limit)
+
+	var r0 []domain.TodoRevision
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) ([]domain.TodoRevision, int64, error)); ok {
+		return rf(ctx, todoID, skip, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64, int64) []domain.TodoRevision); ok {
+		r0 = rf(ctx, todoID, skip, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.TodoRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64, int64) int64); ok {
+		r1 = rf(ctx, todoID, skip, limit)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64, int64) error); ok {
+		r2 = rf(ctx, todoID, skip, limit)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: ctx, todoID, id
+func (_m *TodoRevisionRepository) GetByID(ctx context.Context, todoID string, id string) (*domain.TodoRevision, error) {
+	ret := _m.Called(ctx, todoID, id)
+
+	var r0 *domain.TodoRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.TodoRevision, error)); ok {
+		return rf(ctx, todoID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.TodoRevision); ok {
+		r0 = rf(ctx, todoID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.TodoRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, todoID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTodoRevisionRepository creates a new instance of TodoRevisionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTodoRevisionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TodoRevisionRepository {
+	mock := &TodoRevisionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
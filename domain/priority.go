@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Priority: Priority of a todo, it's stored by its rank so the todos sort from low to urgent.
+// The zero value is the priority of the todos created before the priorities, it's encoded as null.
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityMedium
+	PriorityHigh
+	PriorityUrgent
+)
+
+// PriorityNames are the names of the priorities by their rank
+var PriorityNames = map[Priority]string{
+	PriorityLow:    "low",
+	PriorityMedium: "medium",
+	PriorityHigh:   "high",
+	PriorityUrgent: "urgent",
+}
+
+// ParsePriority returns the priority by its name
+func ParsePriority(name string) (Priority, bool) {
+	for k, v := range PriorityNames {
+		if v == name {
+			return k, true
+		}
+	}
+
+	return 0, false
+}
+
+func (p Priority) String() string {
+	return PriorityNames[p]
+}
+
+func (p Priority) MarshalJSON() ([]byte, error) {
+	name, ok := PriorityNames[p]
+	if !ok {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(name)
+}
+
+func (p *Priority) UnmarshalJSON(b []byte) error {
+	var name *string
+
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+
+	if name == nil {
+		*p = 0
+		return nil
+	}
+
+	v, ok := ParsePriority(*name)
+	if !ok {
+		return fmt.Errorf("invalid priority %s", *name)
+	}
+
+	*p = v
+
+	return nil
+}
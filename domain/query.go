@@ -0,0 +1,164 @@
+package domain
+
+import "slices"
+
+type SortOrder string
+
+const (
+	SortAsc  SortOrder = "ASC"
+	SortDesc SortOrder = "DESC"
+)
+
+// Sort: Sort describe a single sort key, Field is the json name of the field
+type Sort struct {
+	Field string
+	Order SortOrder
+}
+
+// ProjectFields returns the fields to read for the projection, the id and the extra fields are always read.
+// An empty projection reads every field and is returned as nil.
+func ProjectFields(fields []string, extra ...string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	result := []string{"id"}
+
+	for _, v := range append(append([]string{}, fields...), extra...) {
+		if !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// Cursor: Cursor is the position of a keyset page boundary, Values are the values of the boundary item
+// for each of the KeysetKeys. A Backward cursor reads the items before the position instead of after.
+type Cursor struct {
+	Values   []interface{}
+	Backward bool
+}
+
+// KeysetKeys returns the sort keys followed by the id as a tiebreaker, unless the id is sorted already
+func KeysetKeys(sort []Sort) []Sort {
+	keys := append([]Sort{}, sort...)
+
+	for _, v := range sort {
+		if v.Field == "id" {
+			return keys
+		}
+	}
+
+	return append(keys, Sort{Field: "id", Order: SortAsc})
+}
+
+// KeysetFields returns the fields of the keyset keys, they must be read to position the cursors
+func KeysetFields(sort []Sort) []string {
+	fields := []string{}
+
+	for _, v := range KeysetKeys(sort) {
+		fields = append(fields, v.Field)
+	}
+
+	return fields
+}
+
+// KeysetSort returns the keyset keys in the reading order of the cursor
+func KeysetSort(sort []Sort, cursor *Cursor) []Sort {
+	keys := KeysetKeys(sort)
+
+	if cursor == nil || !cursor.Backward {
+		return keys
+	}
+
+	for i, v := range keys {
+		keys[i].Order = SortDesc
+		if v.Order == SortDesc {
+			keys[i].Order = SortAsc
+		}
+	}
+
+	return keys
+}
+
+// KeysetFilter builds the filter matching the items after the cursor, or before it for a backward cursor.
+// The items without a value of the nullable fields are sorted before the others, like the databases do.
+// It returns nil when there is no cursor.
+func KeysetFilter(sort []Sort, cursor *Cursor, nullable ...string) *Filter {
+	if cursor == nil {
+		return nil
+	}
+
+	keys := KeysetSort(sort, cursor)
+	alternatives := []*Filter{}
+
+	// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+	for i, v := range keys {
+		if i >= len(cursor.Values) {
+			break
+		}
+
+		after := keysetAfter(v, cursor.Values[i], slices.Contains(nullable, v.Field))
+		if after == nil {
+			continue
+		}
+
+		conditions := []*Filter{}
+
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, Where(keys[j].Field, FoEq, cursor.Values[j]))
+		}
+
+		alternatives = append(alternatives, And(append(conditions, after)...))
+	}
+
+	return Or(alternatives...)
+}
+
+// keysetAfter builds the condition of the key matching the values after the value, it returns nil when there are none
+func keysetAfter(key Sort, value interface{}, nullable bool) *Filter {
+	if value == nil {
+		// the null values come first, only the other values are after them in ascending order
+		if key.Order == SortDesc {
+			return nil
+		}
+
+		return Where(key.Field, FoNe, nil)
+	}
+
+	if key.Order != SortDesc {
+		return Where(key.Field, FoGt, value)
+	}
+
+	if nullable {
+		return Or(Where(key.Field, FoLt, value), Where(key.Field, FoEq, nil))
+	}
+
+	return Where(key.Field, FoLt, value)
+}
+
+// TrimKeyset trims the items read with a limit of one extra item, it reports whether there are more items
+// and restores the sort order of the items read by a backward cursor
+func TrimKeyset[T any](items []T, cursor *Cursor, limit int64) ([]T, bool) {
+	hasMore := limit > -1 && int64(len(items)) > limit
+
+	if hasMore {
+		items = items[:limit]
+	}
+
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(items)
+	}
+
+	return items, hasMore
+}
+
+// KeysetLimit returns the limit to read a keyset page with, one extra item tells whether there are more items
+func KeysetLimit(limit int64) int64 {
+	if limit < 0 {
+		return limit
+	}
+
+	return limit + 1
+}
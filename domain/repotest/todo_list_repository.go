@@ -0,0 +1,184 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TodoListRepositoryFactory creates an empty domain.TodoListRepository along with the domain.TodoRepository
+// holding the todos of its lists, it's called once per test case
+type TodoListRepositoryFactory func(t *testing.T) (domain.TodoListRepository, domain.TodoRepository)
+
+func seedTodoLists(t *testing.T, repo domain.TodoListRepository, n int) []domain.TodoList {
+	result := []domain.TodoList{}
+
+	for i := 1; i <= n; i++ {
+		res, err := repo.Create(context.TODO(), &domain.TodoListDto{
+			Name:        fmt.Sprintf("List %d", i),
+			Description: fmt.Sprintf("Description %d", i),
+		})
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+
+		result = append(result, *res)
+	}
+
+	return result
+}
+
+func todoListIDs(data []domain.TodoList) []string {
+	ids := []string{}
+
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+
+	return ids
+}
+
+// RunTodoListRepositoryTests verifies the given implementation against the domain.TodoListRepository contract
+func RunTodoListRepositoryTests(t *testing.T, newRepo TodoListRepositoryFactory) {
+	ctx := context.TODO()
+
+	t.Run("Create", func(t *testing.T) {
+		repo, _ := newRepo(t)
+
+		res, err := repo.Create(ctx, &domain.TodoListDto{
+			Name:        "List 1",
+			Description: "Description 1",
+		})
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+		assert.NotEmpty(t, res.ID)
+		assert.Equal(t, "List 1", res.Name)
+		assert.Equal(t, "Description 1", res.Description)
+		require.NotNil(t, res.Audit)
+		assert.False(t, res.CreatedAt.IsZero())
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		data := seedTodoLists(t, repo, 3)
+
+		res, total, err := repo.Get(ctx, 0, 2)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, todoListIDs(data[:2]), todoListIDs(res))
+
+		res, total, err = repo.Get(ctx, 2, -1)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, todoListIDs(data[2:]), todoListIDs(res))
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		data := seedTodoLists(t, repo, 1)
+
+		res, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, data[0].Name, res.Name)
+
+		res, err = repo.GetByID(ctx, "000000000000000000000000")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("GetWithTodos", func(t *testing.T) {
+		repo, todoRepo := newRepo(t)
+		data := seedTodoLists(t, repo, 2)
+		todos := []domain.Todo{}
+
+		for i, v := range []string{data[0].ID, "", data[0].ID, data[1].ID} {
+			res, err := todoRepo.Create(ctx, &domain.TodoDto{
+				Title:       fmt.Sprintf("Title %d", i+1),
+				Description: fmt.Sprintf("Description %d", i+1),
+				ListID:      v,
+			})
+			require.Nil(t, err)
+
+			todos = append(todos, *res)
+		}
+
+		res, err := repo.GetWithTodos(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, data[0].Name, res.Name)
+		assert.Equal(t, []string{todos[0].ID, todos[2].ID}, todoIDs(res.Todos))
+		assert.Equal(t, "Title 1", res.Todos[0].Title)
+
+		res, err = repo.GetWithTodos(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, []string{todos[3].ID}, todoIDs(res.Todos))
+
+		// the todos in the trash are left out
+		require.Nil(t, todoRepo.Delete(ctx, todos[2].ID, 0))
+
+		res, err = repo.GetWithTodos(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, []string{todos[0].ID}, todoIDs(res.Todos))
+
+		empty := seedTodoLists(t, repo, 1)
+
+		res, err = repo.GetWithTodos(ctx, empty[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, []domain.Todo{}, res.Todos)
+
+		res, err = repo.GetWithTodos(ctx, "000000000000000000000000")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		data := seedTodoLists(t, repo, 1)
+
+		res, err := repo.Update(ctx, data[0].ID, &domain.TodoListDto{Name: "List 1 - Updated"})
+
+		require.Nil(t, err)
+		assert.Equal(t, "List 1 - Updated", res.Name)
+		assert.Empty(t, res.Description)
+
+		stored, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, "List 1 - Updated", stored.Name)
+
+		res, err = repo.Update(ctx, "000000000000000000000000", &domain.TodoListDto{Name: "List"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo, _ := newRepo(t)
+		data := seedTodoLists(t, repo, 2)
+
+		err := repo.Delete(ctx, data[0].ID)
+
+		require.Nil(t, err)
+
+		_, err = repo.GetByID(ctx, data[0].ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		err = repo.Delete(ctx, data[0].ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,1301 @@
+// Package repotest holds the behavioral contract every domain repository implementation must satisfy.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TodoRepositoryFactory creates an empty domain.TodoRepository, it's called once per test case
+type TodoRepositoryFactory func(t *testing.T) domain.TodoRepository
+
+func seedTodos(t *testing.T, repo domain.TodoRepository, n int) []domain.Todo {
+	result := []domain.Todo{}
+
+	for i := 1; i <= n; i++ {
+		res, err := repo.Create(context.TODO(), &domain.TodoDto{
+			Title:       fmt.Sprintf("Title %d", i),
+			Description: fmt.Sprintf("Description %d", i),
+		})
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+
+		result = append(result, *res)
+	}
+
+	return result
+}
+
+func todoIDs(data []domain.Todo) []string {
+	ids := []string{}
+
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+
+	return ids
+}
+
+func searchIDs(data []domain.TodoSearchResult) []string {
+	ids := []string{}
+
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+
+	return ids
+}
+
+// RunTodoRepositoryTests verifies the given implementation against the domain.TodoRepository contract
+func RunTodoRepositoryTests(t *testing.T, newRepo TodoRepositoryFactory) {
+	ctx := context.TODO()
+
+	t.Run("Create", func(t *testing.T) {
+		repo := newRepo(t)
+
+		res, err := repo.Create(ctx, &domain.TodoDto{
+			Title:       "Title 1",
+			Description: "Description 1",
+		})
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+		assert.NotEmpty(t, res.ID)
+		assert.Equal(t, "Title 1", res.Title)
+		assert.Equal(t, "Description 1", res.Description)
+		assert.False(t, res.IsCompleted)
+		require.NotNil(t, res.Audit)
+		assert.False(t, res.CreatedAt.IsZero())
+		assert.False(t, res.UpdatedAt.IsZero())
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+
+		res, err := repo.GetByID(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, data[1].ID, res.ID)
+		assert.Equal(t, data[1].Title, res.Title)
+		assert.Equal(t, data[1].Description, res.Description)
+		assert.Equal(t, data[1].IsCompleted, res.IsCompleted)
+	})
+
+	t.Run("GetByID - Not Found", func(t *testing.T) {
+		repo := newRepo(t)
+		seedTodos(t, repo, 1)
+
+		res, err := repo.GetByID(ctx, "000000000000000000000000")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 3)
+
+		res, total, err := repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, len(data), total)
+		assert.ElementsMatch(t, todoIDs(data), todoIDs(res))
+	})
+
+	t.Run("Get - Empty", func(t *testing.T) {
+		repo := newRepo(t)
+
+		res, total, err := repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("Get - Filter", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 12)
+
+		// "title 1" matches Title 1, Title 10, Title 11 and Title 12 regardless of the case
+		res, total, err := repo.Get(ctx, domain.Where("title", domain.FoContains, "title 1"), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 4, total)
+		assert.ElementsMatch(t, todoIDs([]domain.Todo{data[0], data[9], data[10], data[11]}), todoIDs(res))
+
+		res, total, err = repo.Get(ctx, domain.And(
+			domain.Where("title", domain.FoContains, "title 1"),
+			domain.Where("description", domain.FoContains, "ION 12"),
+		), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.ElementsMatch(t, []string{data[11].ID}, todoIDs(res))
+
+		res, total, err = repo.Get(ctx, domain.Where("title", domain.FoContains, "unknown"), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("Get - Filter Operators", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 12)
+
+		_, err := repo.UpdateStatus(ctx, data[1].ID, true, 0)
+		require.Nil(t, err)
+
+		cases := []struct {
+			filter   *domain.Filter
+			expected []domain.Todo
+		}{
+			{
+				filter:   domain.Where("isCompleted", domain.FoEq, true),
+				expected: []domain.Todo{data[1]},
+			},
+			{
+				filter:   domain.And(domain.Where("isCompleted", domain.FoNe, true), domain.Where("title", domain.FoStartWith, "title 1")),
+				expected: []domain.Todo{data[0], data[9], data[10], data[11]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoEndWith, "2"),
+				expected: []domain.Todo{data[1], data[11]},
+			},
+			{
+				filter:   domain.Where("id", domain.FoIn, []interface{}{data[2].ID, data[4].ID}),
+				expected: []domain.Todo{data[2], data[4]},
+			},
+			{
+				filter:   domain.And(domain.Where("id", domain.FoNin, []interface{}{data[0].ID}), domain.Where("title", domain.FoLt, "Title 2")),
+				expected: []domain.Todo{data[9], data[10], data[11]},
+			},
+			{
+				filter:   domain.Where("createdAt", domain.FoLte, time.Now().Add(time.Hour)),
+				expected: data,
+			},
+			{
+				filter:   domain.Where("createdAt", domain.FoGt, time.Now().Add(time.Hour)),
+				expected: []domain.Todo{},
+			},
+			{
+				filter:   domain.Or(domain.Where("isCompleted", domain.FoEq, true), domain.Where("title", domain.FoEq, "Title 3")),
+				expected: []domain.Todo{data[1], data[2]},
+			},
+			{
+				filter:   domain.And(domain.Where("title", domain.FoStartWith, "title 1"), domain.Not(domain.Where("id", domain.FoIn, []interface{}{data[9].ID, data[10].ID}))),
+				expected: []domain.Todo{data[0], data[11]},
+			},
+			{
+				filter:   domain.Or(domain.Not(domain.Or(domain.Where("title", domain.FoContains, "1"), domain.Where("isCompleted", domain.FoEq, true))), domain.Where("id", domain.FoEq, data[0].ID)),
+				expected: []domain.Todo{data[0], data[2], data[3], data[4], data[5], data[6], data[7], data[8]},
+			},
+			{
+				filter:   domain.And(domain.Or(), domain.And()),
+				expected: data,
+			},
+			{
+				filter:   domain.Not(domain.And()),
+				expected: []domain.Todo{},
+			},
+		}
+
+		for _, c := range cases {
+			res, total, err := repo.Get(ctx, c.filter, nil, 0, 20)
+
+			require.Nil(t, err)
+			assert.EqualValues(t, len(c.expected), total)
+			assert.ElementsMatch(t, todoIDs(c.expected), todoIDs(res))
+		}
+	})
+
+	t.Run("Get - Literal Match", func(t *testing.T) {
+		repo := newRepo(t)
+		data := []domain.Todo{}
+
+		for _, v := range []string{"Price (a+)+$", "a.c", "abc", "50% off", "under_score", "underscore", `back\slash`} {
+			res, err := repo.Create(ctx, &domain.TodoDto{Title: v, Description: v})
+			require.Nil(t, err)
+
+			data = append(data, *res)
+		}
+
+		cases := []struct {
+			filter   *domain.Filter
+			expected []domain.Todo
+		}{
+			{
+				filter:   domain.Where("title", domain.FoContains, "(a+)+$"),
+				expected: []domain.Todo{data[0]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoContains, ".*"),
+				expected: []domain.Todo{},
+			},
+			{
+				filter:   domain.Where("title", domain.FoStartWith, "a.c"),
+				expected: []domain.Todo{data[1]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoEndWith, "+$"),
+				expected: []domain.Todo{data[0]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoContains, "%"),
+				expected: []domain.Todo{data[3]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoContains, "r_s"),
+				expected: []domain.Todo{data[4]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoContains, `k\s`),
+				expected: []domain.Todo{data[6]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoRegex, "^a.c$"),
+				expected: []domain.Todo{data[1], data[2]},
+			},
+			{
+				filter:   domain.Where("title", domain.FoRegex, "^UNDER"),
+				expected: []domain.Todo{data[4], data[5]},
+			},
+		}
+
+		for _, c := range cases {
+			res, total, err := repo.Get(ctx, c.filter, nil, 0, 10)
+
+			require.Nil(t, err)
+			assert.EqualValues(t, len(c.expected), total)
+			assert.ElementsMatch(t, todoIDs(c.expected), todoIDs(res))
+		}
+	})
+
+	t.Run("Get - Invalid Filter", func(t *testing.T) {
+		repo := newRepo(t)
+
+		filters := []*domain.Filter{
+			domain.Where("password", domain.FoEq, "secret"),
+			domain.Where("isCompleted", domain.FoContains, "true"),
+			domain.Where("isCompleted", domain.FoEq, "true"),
+			domain.Or(domain.Where("createdAt", domain.FoIn, time.Now())),
+			domain.Not(domain.Where("title", domain.FilterOperator("$where"), "true")),
+			domain.Where("title", domain.FoRegex, "(a+"),
+			domain.Where("title", domain.FoRegex, strings.Repeat("a", domain.MaxRegexLength+1)),
+		}
+
+		for _, v := range filters {
+			_, _, err := repo.Get(ctx, v, nil, 0, 10)
+
+			assert.ErrorIs(t, err, domain.ErrValidation)
+		}
+	})
+
+	t.Run("Get - Paginate", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 5)
+
+		firstPage, total, err := repo.Get(ctx, nil, nil, 0, 2)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, len(data), total)
+		assert.Equal(t, 2, len(firstPage))
+
+		secondPage, total, err := repo.Get(ctx, nil, nil, 2, 2)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, len(data), total)
+		assert.Equal(t, 2, len(secondPage))
+
+		lastPage, total, err := repo.Get(ctx, nil, nil, 4, 2)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, len(data), total)
+		assert.Equal(t, 1, len(lastPage))
+
+		pages := append(append(firstPage, secondPage...), lastPage...)
+		assert.ElementsMatch(t, todoIDs(data), todoIDs(pages))
+	})
+
+	t.Run("Get - Sort", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 3)
+
+		_, err := repo.UpdateStatus(ctx, data[1].ID, true, 0)
+		require.Nil(t, err)
+
+		res, total, err := repo.Get(ctx, nil, []domain.Sort{
+			{Field: "title", Order: domain.SortDesc},
+		}, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, todoIDs([]domain.Todo{data[2], data[1], data[0]}), todoIDs(res))
+
+		res, _, err = repo.Get(ctx, nil, []domain.Sort{
+			{Field: "isCompleted", Order: domain.SortAsc},
+			{Field: "title", Order: domain.SortDesc},
+		}, 0, 2)
+
+		require.Nil(t, err)
+		assert.Equal(t, todoIDs([]domain.Todo{data[2], data[0]}), todoIDs(res))
+
+		res, _, err = repo.Get(ctx, nil, []domain.Sort{
+			{Field: "createdAt", Order: domain.SortAsc},
+		}, 1, 10)
+
+		require.Nil(t, err)
+		assert.Equal(t, todoIDs([]domain.Todo{data[1], data[2]}), todoIDs(res))
+	})
+
+	t.Run("GetByCursor", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 8)
+
+		for _, i := range []int{1, 4, 6} {
+			_, err := repo.UpdateStatus(ctx, data[i].ID, true, 0)
+			require.Nil(t, err)
+		}
+
+		sort := []domain.Sort{
+			{Field: "isCompleted", Order: domain.SortAsc},
+			{Field: "title", Order: domain.SortDesc},
+		}
+		filter := domain.Not(domain.Where("id", domain.FoEq, data[7].ID))
+
+		expected, _, err := repo.Get(ctx, filter, sort, 0, -1)
+		require.Nil(t, err)
+		require.Equal(t, 7, len(expected))
+
+		// forward, page by page
+		pages := [][]domain.Todo{}
+		var cursor *domain.Cursor
+
+		for {
+			res, hasMore, err := repo.GetByCursor(ctx, filter, sort, cursor, 3)
+			require.Nil(t, err)
+
+			pages = append(pages, res)
+
+			if !hasMore {
+				break
+			}
+
+			cursor = domain.NewTodoCursor(res[len(res)-1], sort, false)
+		}
+
+		require.Equal(t, 3, len(pages))
+		assert.Equal(t, todoIDs(expected[0:3]), todoIDs(pages[0]))
+		assert.Equal(t, todoIDs(expected[3:6]), todoIDs(pages[1]))
+		assert.Equal(t, todoIDs(expected[6:]), todoIDs(pages[2]))
+
+		// backward from the last page
+		res, hasMore, err := repo.GetByCursor(ctx, filter, sort, domain.NewTodoCursor(pages[2][0], sort, true), 3)
+
+		require.Nil(t, err)
+		assert.True(t, hasMore)
+		assert.Equal(t, todoIDs(expected[3:6]), todoIDs(res))
+
+		res, hasMore, err = repo.GetByCursor(ctx, filter, sort, domain.NewTodoCursor(res[0], sort, true), 3)
+
+		require.Nil(t, err)
+		assert.False(t, hasMore)
+		assert.Equal(t, todoIDs(expected[0:3]), todoIDs(res))
+
+		// items created before the cursor position don't shift the following pages
+		_, err = repo.Create(ctx, &domain.TodoDto{Title: "Title 9", Description: "Description 9"})
+		require.Nil(t, err)
+
+		res, _, err = repo.GetByCursor(ctx, filter, sort, domain.NewTodoCursor(pages[0][2], sort, false), 3)
+
+		require.Nil(t, err)
+		assert.Equal(t, todoIDs(expected[3:6]), todoIDs(res))
+	})
+
+	t.Run("Projection", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 3)
+
+		res, total, err := repo.Get(ctx, nil, []domain.Sort{{Field: "title", Order: domain.SortAsc}}, 0, 10, "title")
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, domain.Todo{ID: data[0].ID, Title: data[0].Title}, res[0])
+
+		row, err := repo.GetByID(ctx, data[1].ID, "isCompleted", "createdAt")
+
+		require.Nil(t, err)
+		assert.Equal(t, data[1].ID, row.ID)
+		assert.Equal(t, "", row.Title)
+		assert.Equal(t, "", row.Description)
+		require.NotNil(t, row.Audit)
+		assert.False(t, row.CreatedAt.IsZero())
+		assert.True(t, row.UpdatedAt.IsZero())
+
+		sort := []domain.Sort{{Field: "description", Order: domain.SortDesc}}
+
+		page, hasMore, err := repo.GetByCursor(ctx, nil, sort, nil, 2, "title")
+
+		require.Nil(t, err)
+		assert.True(t, hasMore)
+		assert.Equal(t, domain.Todo{ID: data[2].ID, Title: data[2].Title, Description: data[2].Description}, page[0])
+
+		page, _, err = repo.GetByCursor(ctx, nil, sort, domain.NewTodoCursor(page[1], sort, false), 2, "title")
+
+		require.Nil(t, err)
+		assert.Equal(t, []string{data[0].ID}, todoIDs(page))
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		repo := newRepo(t)
+		data := []domain.Todo{}
+
+		for _, v := range []domain.TodoDto{
+			{Title: "Buy groceries", Description: "Milk and bread"},
+			{Title: "Walk the dog", Description: "Buy a leash first"},
+			{Title: "Read a novel", Description: "Something light"},
+			{Title: "Buy flowers", Description: "Buy them at the market"},
+		} {
+			res, err := repo.Create(ctx, &v)
+			require.Nil(t, err)
+
+			data = append(data, *res)
+		}
+
+		res, total, err := repo.Search(ctx, "buy", 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.ElementsMatch(t, todoIDs([]domain.Todo{data[0], data[1], data[3]}), searchIDs(res))
+		assert.Equal(t, data[3].ID, res[0].ID)
+
+		for i := 1; i < len(res); i++ {
+			assert.GreaterOrEqual(t, res[i-1].Score, res[i].Score)
+		}
+
+		page, total, err := repo.Search(ctx, "buy", 1, 1)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, searchIDs(res[1:2]), searchIDs(page))
+
+		res, total, err = repo.Search(ctx, "unknown", 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Equal(t, 0, len(res))
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		repo := newRepo(t)
+		data := []domain.Todo{}
+
+		for i, v := range [][]string{{"home", "urgent"}, {"work"}, {"home", "work"}, nil} {
+			res, err := repo.Create(ctx, &domain.TodoDto{
+				Title:       fmt.Sprintf("Title %d", i+1),
+				Description: fmt.Sprintf("Description %d", i+1),
+				Tags:        v,
+			})
+			require.Nil(t, err)
+
+			data = append(data, *res)
+		}
+
+		row, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []string{"home", "urgent"}, row.Tags)
+
+		cases := []struct {
+			filter   *domain.Filter
+			expected []domain.Todo
+		}{
+			{
+				filter:   domain.Where("tags", domain.FoEq, "home"),
+				expected: []domain.Todo{data[0], data[2]},
+			},
+			{
+				filter:   domain.Where("tags", domain.FoIn, []interface{}{"urgent", "work"}),
+				expected: []domain.Todo{data[0], data[1], data[2]},
+			},
+			{
+				filter:   domain.Where("tags", domain.FoAll, []interface{}{"home", "work"}),
+				expected: []domain.Todo{data[2]},
+			},
+			{
+				filter:   domain.Where("tags", domain.FoNe, "home"),
+				expected: []domain.Todo{data[1], data[3]},
+			},
+			{
+				filter:   domain.Where("tags", domain.FoNin, []interface{}{"home", "work"}),
+				expected: []domain.Todo{data[3]},
+			},
+		}
+
+		for _, c := range cases {
+			res, total, err := repo.Get(ctx, c.filter, nil, 0, 10)
+
+			require.Nil(t, err)
+			assert.EqualValues(t, len(c.expected), total)
+			assert.ElementsMatch(t, todoIDs(c.expected), todoIDs(res))
+		}
+
+		counts, err := repo.GetTags(ctx)
+
+		require.Nil(t, err)
+		assert.Equal(t, []domain.TagCount{{Tag: "home", Count: 2}, {Tag: "work", Count: 2}, {Tag: "urgent", Count: 1}}, counts)
+
+		row, err = repo.AddTags(ctx, data[3].ID, []string{"home", "later"})
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []string{"home", "later"}, row.Tags)
+
+		// the tags are kept sorted whatever order they're added in
+		row, err = repo.AddTags(ctx, data[3].ID, []string{"home", "errand"})
+
+		require.Nil(t, err)
+		assert.Equal(t, []string{"errand", "home", "later"}, row.Tags)
+
+		row, err = repo.GetByID(ctx, data[3].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, []string{"errand", "home", "later"}, row.Tags)
+
+		row, err = repo.RemoveTags(ctx, data[0].ID, []string{"home", "unknown"})
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []string{"urgent"}, row.Tags)
+
+		row, err = repo.Update(ctx, data[1].ID, &domain.TodoDto{Title: "Title 2", Description: "Description 2"}, 0)
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []string{"work"}, row.Tags)
+
+		row, err = repo.Update(ctx, data[2].ID, &domain.TodoDto{Title: "Title 3", Description: "Description 3", Tags: []string{}}, 0)
+
+		require.Nil(t, err)
+		assert.Empty(t, row.Tags)
+
+		counts, err = repo.GetTags(ctx)
+
+		require.Nil(t, err)
+		assert.Equal(t, []domain.TagCount{{Tag: "errand", Count: 1}, {Tag: "home", Count: 1}, {Tag: "later", Count: 1}, {Tag: "urgent", Count: 1}, {Tag: "work", Count: 1}}, counts)
+	})
+
+	t.Run("Priority And Due Date", func(t *testing.T) {
+		repo := newRepo(t)
+		data := []domain.Todo{}
+
+		day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		dueAt := []*time.Time{}
+		for _, v := range []int{1, -1, 0, -1, 2} {
+			if v < 0 {
+				dueAt = append(dueAt, nil)
+				continue
+			}
+
+			due := day.AddDate(0, 0, v)
+			dueAt = append(dueAt, &due)
+		}
+
+		for i, v := range []string{"low", "urgent", "", "high", "medium"} {
+			res, err := repo.Create(ctx, &domain.TodoDto{
+				Title:       fmt.Sprintf("Title %d", i+1),
+				Description: fmt.Sprintf("Description %d", i+1),
+				Priority:    v,
+				DueAt:       dueAt[i],
+			})
+			require.Nil(t, err)
+
+			data = append(data, *res)
+		}
+
+		row, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, domain.PriorityLow, row.Priority)
+		require.NotNil(t, row.DueAt)
+		assert.True(t, dueAt[0].Equal(*row.DueAt))
+
+		row, err = repo.GetByID(ctx, data[2].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, domain.PriorityMedium, row.Priority)
+
+		row, err = repo.GetByID(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		assert.Nil(t, row.DueAt)
+
+		sorts := []struct {
+			sort     []domain.Sort
+			expected []domain.Todo
+		}{
+			{
+				sort:     []domain.Sort{{Field: "priority", Order: domain.SortDesc}},
+				expected: []domain.Todo{data[1], data[3], data[2], data[4], data[0]},
+			},
+			{
+				// the todos without a due date come first
+				sort:     []domain.Sort{{Field: "dueAt", Order: domain.SortAsc}},
+				expected: []domain.Todo{data[1], data[3], data[2], data[0], data[4]},
+			},
+			{
+				sort:     []domain.Sort{{Field: "dueAt", Order: domain.SortDesc}},
+				expected: []domain.Todo{data[4], data[0], data[2], data[1], data[3]},
+			},
+		}
+
+		for _, c := range sorts {
+			res, _, err := repo.Get(ctx, nil, c.sort, 0, -1)
+
+			require.Nil(t, err)
+			assert.Equal(t, todoIDs(c.expected), todoIDs(res))
+
+			// the keyset pages walk the same order across the todos without a due date
+			pages := []domain.Todo{}
+			var cursor *domain.Cursor
+
+			for {
+				res, hasMore, err := repo.GetByCursor(ctx, nil, c.sort, cursor, 2)
+				require.Nil(t, err)
+
+				pages = append(pages, res...)
+
+				if !hasMore {
+					break
+				}
+
+				cursor = domain.NewTodoCursor(res[len(res)-1], c.sort, false)
+			}
+
+			assert.Equal(t, todoIDs(c.expected), todoIDs(pages))
+
+			res, hasMore, err := repo.GetByCursor(ctx, nil, c.sort, domain.NewTodoCursor(pages[3], c.sort, true), 2)
+
+			require.Nil(t, err)
+			assert.True(t, hasMore)
+			assert.Equal(t, todoIDs(c.expected[1:3]), todoIDs(res))
+		}
+
+		filters := []struct {
+			filter   *domain.Filter
+			expected []domain.Todo
+		}{
+			{
+				filter:   domain.Where("priority", domain.FoIn, []interface{}{domain.PriorityHigh, domain.PriorityUrgent}),
+				expected: []domain.Todo{data[1], data[3]},
+			},
+			{
+				filter:   domain.Where("priority", domain.FoLte, domain.PriorityMedium),
+				expected: []domain.Todo{data[0], data[2], data[4]},
+			},
+			{
+				filter:   domain.Where("dueAt", domain.FoLt, *dueAt[0]),
+				expected: []domain.Todo{data[2]},
+			},
+			{
+				filter:   domain.Where("dueAt", domain.FoNe, *dueAt[0]),
+				expected: []domain.Todo{data[1], data[2], data[3], data[4]},
+			},
+			{
+				filter:   domain.Where("dueAt", domain.FoEq, nil),
+				expected: []domain.Todo{data[1], data[3]},
+			},
+		}
+
+		for _, c := range filters {
+			res, total, err := repo.Get(ctx, c.filter, nil, 0, 10)
+
+			require.Nil(t, err)
+			assert.EqualValues(t, len(c.expected), total)
+			assert.ElementsMatch(t, todoIDs(c.expected), todoIDs(res))
+		}
+
+		_, err = repo.UpdateStatus(ctx, data[2].ID, true, 0)
+		require.Nil(t, err)
+
+		res, total, err := repo.Get(ctx, domain.OverdueFilter(dueAt[0].Add(time.Hour)), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, todoIDs([]domain.Todo{data[0]}), todoIDs(res))
+
+		row, err = repo.Update(ctx, data[0].ID, &domain.TodoDto{Title: "Title 1", Description: "Description 1", Priority: "urgent"}, 0)
+
+		require.Nil(t, err)
+		assert.Equal(t, domain.PriorityUrgent, row.Priority)
+		assert.Nil(t, row.DueAt)
+
+		row, err = repo.Update(ctx, data[1].ID, &domain.TodoDto{Title: "Title 2", Description: "Description 2", DueAt: dueAt[4]}, 0)
+
+		require.Nil(t, err)
+		assert.Equal(t, domain.PriorityMedium, row.Priority)
+		require.NotNil(t, row.DueAt)
+		assert.True(t, dueAt[4].Equal(*row.DueAt))
+	})
+
+	t.Run("Subtasks", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 1)
+
+		// 1 <- 2 <- 4, 1 <- 3
+		for i, parent := range []int{0, 0, 1} {
+			res, err := repo.Create(ctx, &domain.TodoDto{
+				Title:       fmt.Sprintf("Subtask %d", i+1),
+				Description: fmt.Sprintf("Subtask %d", i+1),
+				ParentID:    data[parent].ID,
+			})
+			require.Nil(t, err)
+			assert.Equal(t, data[parent].ID, res.ParentID)
+
+			data = append(data, *res)
+		}
+
+		outside := seedTodos(t, repo, 1)
+
+		nodes, err := repo.GetSubtree(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		require.Len(t, nodes, 4)
+		assert.Equal(t, data[0].ID, nodes[0].ID)
+		assert.EqualValues(t, 0, nodes[0].Depth)
+		assert.ElementsMatch(t, []string{data[1].ID, data[2].ID}, todoIDs([]domain.Todo{nodes[1].Todo, nodes[2].Todo}))
+		assert.EqualValues(t, 1, nodes[1].Depth)
+		assert.EqualValues(t, 1, nodes[2].Depth)
+		assert.Less(t, nodes[1].ID, nodes[2].ID)
+		assert.Equal(t, data[3].ID, nodes[3].ID)
+		assert.EqualValues(t, 2, nodes[3].Depth)
+
+		nodes, err = repo.GetSubtree(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, todoIDs([]domain.Todo{data[1], data[3]}), todoIDs([]domain.Todo{nodes[0].Todo, nodes[1].Todo}))
+
+		_, err = repo.GetSubtree(ctx, "000000000000000000000000")
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		res, total, err := repo.Get(ctx, domain.Where("parentId", domain.FoEq, data[0].ID), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.ElementsMatch(t, todoIDs(data[1:3]), todoIDs(res))
+
+		res, _, err = repo.Get(ctx, domain.Where("parentId", domain.FoEq, nil), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []string{data[0].ID, outside[0].ID}, todoIDs(res))
+
+		err = repo.UpdateStatusMany(ctx, []string{data[1].ID, data[3].ID}, true)
+
+		require.Nil(t, err)
+
+		res, _, err = repo.Get(ctx, domain.Where("isCompleted", domain.FoEq, true), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []string{data[1].ID, data[3].ID}, todoIDs(res))
+
+		row, err := repo.Update(ctx, data[3].ID, &domain.TodoDto{Title: "Subtask 3", Description: "Subtask 3"}, 0)
+
+		require.Nil(t, err)
+		assert.Empty(t, row.ParentID)
+
+		nodes, err = repo.GetSubtree(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Len(t, nodes, 3)
+	})
+
+	t.Run("Lists", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 1)
+
+		listID := "6512d6f0a7e2b1c3d4e5f601"
+		otherID := "6512d6f0a7e2b1c3d4e5f602"
+
+		for i := 1; i <= 3; i++ {
+			res, err := repo.Create(ctx, &domain.TodoDto{
+				Title:       fmt.Sprintf("Listed %d", i),
+				Description: fmt.Sprintf("Listed %d", i),
+				ListID:      listID,
+			})
+			require.Nil(t, err)
+			assert.Equal(t, listID, res.ListID)
+
+			data = append(data, *res)
+		}
+
+		row, err := repo.GetByID(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, listID, row.ListID)
+
+		res, total, err := repo.Get(ctx, domain.Where("listId", domain.FoEq, listID), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.ElementsMatch(t, todoIDs(data[1:]), todoIDs(res))
+
+		res, _, err = repo.Get(ctx, domain.Where("listId", domain.FoNe, listID), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.Equal(t, todoIDs(data[:1]), todoIDs(res))
+
+		err = repo.UpdateListMany(ctx, []string{data[1].ID, data[2].ID}, otherID)
+
+		require.Nil(t, err)
+
+		res, _, err = repo.Get(ctx, domain.Where("listId", domain.FoEq, otherID), nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, todoIDs(data[1:3]), todoIDs(res))
+
+		err = repo.UpdateListMany(ctx, []string{data[1].ID}, "")
+
+		require.Nil(t, err)
+
+		row, err = repo.GetByID(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		assert.Empty(t, row.ListID)
+
+		err = repo.DeleteMany(ctx, []string{data[2].ID, data[3].ID})
+
+		require.Nil(t, err)
+
+		res, total, err = repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.ElementsMatch(t, todoIDs(data[:2]), todoIDs(res))
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+
+		res, err := repo.Update(ctx, data[0].ID, &domain.TodoDto{
+			Title:       "Title 1 - Updated",
+			Description: "Description 1 - Updated",
+		}, 0)
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, data[0].ID, res.ID)
+		assert.Equal(t, "Title 1 - Updated", res.Title)
+		assert.Equal(t, "Description 1 - Updated", res.Description)
+
+		stored, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, "Title 1 - Updated", stored.Title)
+		assert.Equal(t, "Description 1 - Updated", stored.Description)
+
+		untouched, err := repo.GetByID(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, data[1].Title, untouched.Title)
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 1)
+
+		res, err := repo.UpdateStatus(ctx, data[0].ID, true, 0)
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+		assert.True(t, res.IsCompleted)
+		assert.Equal(t, data[0].Title, res.Title)
+
+		stored, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.True(t, stored.IsCompleted)
+
+		res, err = repo.UpdateStatus(ctx, data[0].ID, false, 0)
+
+		require.Nil(t, err)
+		assert.False(t, res.IsCompleted)
+	})
+
+	t.Run("Revert", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+
+		updated, err := repo.Update(ctx, data[0].ID, &domain.TodoDto{
+			Title:       "Title 1 - Updated",
+			Description: "Description 1 - Updated",
+			Tags:        []string{"home"},
+			Priority:    "high",
+		}, 0)
+
+		require.Nil(t, err)
+
+		res, err := repo.Revert(ctx, data[0].ID, domain.TodoState{
+			Title:       data[0].Title,
+			Description: data[0].Description,
+			IsCompleted: true,
+			Tags:        []string{},
+			Priority:    domain.PriorityMedium,
+		})
+
+		require.Nil(t, err)
+		assert.Equal(t, data[0].Title, res.Title)
+		assert.True(t, res.IsCompleted)
+		assert.Empty(t, res.Tags)
+		assert.Equal(t, domain.PriorityMedium, res.Priority)
+		// the whole state is a single write
+		assert.Equal(t, updated.Version+1, res.Version)
+
+		stored, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, res.Version, stored.Version)
+		assert.True(t, stored.IsCompleted)
+		assert.Empty(t, stored.Tags)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+
+		err := repo.Delete(ctx, data[0].ID, 0)
+
+		require.Nil(t, err)
+
+		res, err := repo.GetByID(ctx, data[0].ID)
+
+		assert.NotNil(t, err)
+		assert.Nil(t, res)
+
+		list, total, err := repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.ElementsMatch(t, []string{data[1].ID}, todoIDs(list))
+	})
+
+	t.Run("Delete - Not Found", func(t *testing.T) {
+		repo := newRepo(t)
+		seedTodos(t, repo, 1)
+
+		err := repo.Delete(ctx, "000000000000000000000000", 0)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("Writes - Not Found", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+
+		require.Nil(t, repo.Delete(ctx, data[1].ID, 0))
+
+		// the writes of a missing or trashed todo never create it
+		for _, id := range []string{"000000000000000000000000", data[1].ID} {
+			_, err := repo.Update(ctx, id, &domain.TodoDto{Title: "Title", Description: "Description"}, 0)
+
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			_, err = repo.UpdateStatus(ctx, id, true, 0)
+
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			_, err = repo.Revert(ctx, id, domain.TodoState{Title: "Title", Tags: []string{}})
+
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			_, err = repo.AddTags(ctx, id, []string{"home"})
+
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			_, err = repo.RemoveTags(ctx, id, []string{"home"})
+
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+
+			_, err = repo.GetByID(ctx, id)
+
+			assert.ErrorIs(t, err, domain.ErrNotFound)
+		}
+
+		list, total, err := repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, []string{data[0].ID}, todoIDs(list))
+
+		tags, err := repo.GetTags(ctx)
+
+		require.Nil(t, err)
+		assert.Empty(t, tags)
+	})
+
+	t.Run("Replace", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+		id := "000000000000000000000001"
+
+		res, created, err := repo.Replace(ctx, id, &domain.TodoDto{
+			Title:       "Title 3",
+			Description: "Description 3",
+			Tags:        []string{"home"},
+		})
+
+		require.Nil(t, err)
+		assert.True(t, created)
+		assert.Equal(t, id, res.ID)
+		assert.EqualValues(t, 1, res.Version)
+
+		// the created todo has the audit data of one created by Create
+		stored, err := repo.GetByID(ctx, id)
+
+		require.Nil(t, err)
+		assert.Equal(t, "Title 3", stored.Title)
+		assert.False(t, stored.IsCompleted)
+		assert.Equal(t, []string{"home"}, stored.Tags)
+		assert.Equal(t, domain.PriorityMedium, stored.Priority)
+		assert.EqualValues(t, 1, stored.Version)
+		require.NotNil(t, stored.Audit)
+		assert.False(t, stored.CreatedAt.IsZero())
+		assert.False(t, stored.UpdatedAt.IsZero())
+
+		res, created, err = repo.Replace(ctx, id, &domain.TodoDto{
+			Title:       "Title 3 - Replaced",
+			Description: "Description 3",
+		})
+
+		require.Nil(t, err)
+		assert.False(t, created)
+		assert.EqualValues(t, 2, res.Version)
+
+		replaced, err := repo.GetByID(ctx, id)
+
+		require.Nil(t, err)
+		assert.Equal(t, "Title 3 - Replaced", replaced.Title)
+		assert.Equal(t, []string{"home"}, replaced.Tags)
+		assert.True(t, stored.CreatedAt.Equal(replaced.CreatedAt))
+
+		_, total, err := repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+
+		// a trashed todo keeps its id until it's purged
+		require.Nil(t, repo.Delete(ctx, data[1].ID, 0))
+
+		res, created, err = repo.Replace(ctx, data[1].ID, &domain.TodoDto{Title: "Title 2", Description: "Description 2"})
+
+		assert.ErrorIs(t, err, domain.ErrConflict)
+		assert.False(t, created)
+		assert.Nil(t, res)
+
+		trash, _, err := repo.GetTrash(ctx, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.Equal(t, []string{data[1].ID}, todoIDs(trash))
+	})
+
+	t.Run("Trash - Many Writes", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+		listID := "6512d6f0a7e2b1c3d4e5f601"
+
+		require.Nil(t, repo.Delete(ctx, data[1].ID, 0))
+
+		before, _, err := repo.GetTrash(ctx, nil, 0, 10)
+
+		require.Nil(t, err)
+		require.Len(t, before, 1)
+
+		// the todos in the trash are left as they were deleted
+		require.Nil(t, repo.UpdateStatusMany(ctx, todoIDs(data), true))
+		require.Nil(t, repo.UpdateListMany(ctx, todoIDs(data), listID))
+
+		live, err := repo.GetByID(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.True(t, live.IsCompleted)
+		assert.Equal(t, listID, live.ListID)
+
+		trash, _, err := repo.GetTrash(ctx, nil, 0, 10)
+
+		require.Nil(t, err)
+		require.Len(t, trash, 1)
+		assert.False(t, trash[0].IsCompleted)
+		assert.Empty(t, trash[0].ListID)
+		assert.Equal(t, before[0].Version, trash[0].Version)
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+
+		for i, parent := range []string{"", data[0].ID} {
+			res, err := repo.Create(ctx, &domain.TodoDto{
+				Title:       fmt.Sprintf("Buy milk %d", i+1),
+				Description: fmt.Sprintf("Buy milk %d", i+1),
+				Tags:        []string{"shop"},
+				ParentID:    parent,
+			})
+			require.Nil(t, err)
+
+			data = append(data, *res)
+		}
+
+		require.Nil(t, repo.Delete(ctx, data[2].ID, 0))
+
+		// the deletion times must differ for the order of the trash
+		time.Sleep(10 * time.Millisecond)
+
+		require.Nil(t, repo.DeleteMany(ctx, []string{data[3].ID, data[1].ID}))
+
+		assert.ErrorIs(t, repo.Delete(ctx, data[2].ID, 0), domain.ErrNotFound)
+
+		_, err := repo.GetByID(ctx, data[2].ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		res, total, err := repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 1, total)
+		assert.Equal(t, todoIDs(data[:1]), todoIDs(res))
+
+		found, total, err := repo.Search(ctx, "milk", 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Empty(t, found)
+
+		tags, err := repo.GetTags(ctx)
+
+		require.Nil(t, err)
+		assert.Empty(t, tags)
+
+		nodes, err := repo.GetSubtree(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.Len(t, nodes, 1)
+
+		trash, total, err := repo.GetTrash(ctx, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		require.Len(t, trash, 3)
+		assert.ElementsMatch(t, []string{data[1].ID, data[3].ID}, todoIDs(trash[:2]))
+		assert.Equal(t, data[2].ID, trash[2].ID)
+		assert.True(t, trash[0].DeletedAt.After(*trash[2].DeletedAt))
+
+		trash, total, err = repo.GetTrash(ctx, domain.Where("title", domain.FoContains, "milk"), 0, 1)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 2, total)
+		assert.Equal(t, todoIDs(data[3:4]), todoIDs(trash))
+
+		row, err := repo.Restore(ctx, data[2].ID)
+
+		require.Nil(t, err)
+		assert.Equal(t, data[2].Title, row.Title)
+		assert.Equal(t, []string{"shop"}, row.Tags)
+		assert.Nil(t, row.DeletedAt)
+
+		_, err = repo.Restore(ctx, data[2].ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		res, _, err = repo.Get(ctx, nil, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.ElementsMatch(t, []string{data[0].ID, data[2].ID}, todoIDs(res))
+
+		purged, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 0, purged)
+
+		purged, err = repo.Purge(ctx, time.Now().Add(time.Hour))
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 2, purged)
+
+		_, total, err = repo.GetTrash(ctx, nil, 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 0, total)
+
+		_, err = repo.Restore(ctx, data[1].ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("Version", func(t *testing.T) {
+		repo := newRepo(t)
+		data := seedTodos(t, repo, 2)
+
+		assert.EqualValues(t, 1, data[0].Version)
+
+		row, err := repo.Update(ctx, data[0].ID, &domain.TodoDto{Title: "Title 1", Description: "Description 1"}, 1)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 2, row.Version)
+
+		row, err = repo.UpdateStatus(ctx, data[0].ID, true, 2)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, row.Version)
+
+		row, err = repo.AddTags(ctx, data[0].ID, []string{"home"})
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 4, row.Version)
+
+		// a stale version writes nothing
+		_, err = repo.Update(ctx, data[0].ID, &domain.TodoDto{Title: "Title 1 - Stale", Description: "Description 1"}, 3)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+
+		_, err = repo.UpdateStatus(ctx, data[0].ID, false, 3)
+
+		assert.ErrorIs(t, err, domain.ErrPrecondition)
+
+		assert.ErrorIs(t, repo.Delete(ctx, data[0].ID, 3), domain.ErrPrecondition)
+
+		row, err = repo.GetByID(ctx, data[0].ID, "title", "version")
+
+		require.Nil(t, err)
+		assert.Equal(t, "Title 1", row.Title)
+		assert.EqualValues(t, 4, row.Version)
+
+		_, err = repo.Update(ctx, "000000000000000000000000", &domain.TodoDto{Title: "Title", Description: "Description"}, 1)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+
+		require.Nil(t, repo.UpdateStatusMany(ctx, []string{data[1].ID}, true))
+		require.Nil(t, repo.UpdateListMany(ctx, []string{data[1].ID}, ""))
+
+		row, err = repo.GetByID(ctx, data[1].ID)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, row.Version)
+
+		require.Nil(t, repo.Delete(ctx, data[0].ID, 4))
+
+		assert.ErrorIs(t, repo.Delete(ctx, data[0].ID, 5), domain.ErrNotFound)
+
+		row, err = repo.Restore(ctx, data[0].ID)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 6, row.Version)
+	})
+}
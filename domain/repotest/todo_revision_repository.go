@@ -0,0 +1,171 @@
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TodoRevisionRepositoryFactory creates an empty domain.TodoRevisionRepository, it's called once per test case
+type TodoRevisionRepositoryFactory func(t *testing.T) domain.TodoRevisionRepository
+
+func newTodoRevision(todoID string, title string) domain.TodoRevision {
+	return domain.TodoRevision{
+		TodoID: todoID,
+		Action: domain.RevisionUpdate,
+		Actor:  "alice",
+		Before: &domain.TodoState{Title: title + " - Before", Tags: []string{}},
+		State:  domain.TodoState{Title: title, Tags: []string{}},
+	}
+}
+
+func seedTodoRevisions(t *testing.T, repo domain.TodoRevisionRepository, todoID string, n int) []domain.TodoRevision {
+	result := []domain.TodoRevision{}
+
+	for i := 1; i <= n; i++ {
+		res, err := repo.CreateMany(context.TODO(), []domain.TodoRevision{newTodoRevision(todoID, fmt.Sprintf("Title %d", i))})
+
+		require.Nil(t, err)
+		require.Len(t, res, 1)
+
+		result = append(result, res[0])
+
+		// keeps the creation order apparent to stores with a coarse clock
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return result
+}
+
+func todoRevisionIDs(data []domain.TodoRevision) []string {
+	ids := []string{}
+
+	for _, v := range data {
+		ids = append(ids, v.ID)
+	}
+
+	return ids
+}
+
+// RunTodoRevisionRepositoryTests verifies the given implementation against the domain.TodoRevisionRepository contract
+func RunTodoRevisionRepositoryTests(t *testing.T, newRepo TodoRevisionRepositoryFactory) {
+	ctx := context.TODO()
+
+	t.Run("CreateMany", func(t *testing.T) {
+		repo := newRepo(t)
+
+		res, err := repo.CreateMany(ctx, []domain.TodoRevision{newTodoRevision("1", "Title 1"), newTodoRevision("1", "Title 2")})
+
+		require.Nil(t, err)
+		require.Len(t, res, 2)
+		assert.NotEmpty(t, res[0].ID)
+		assert.NotEqual(t, res[0].ID, res[1].ID)
+		assert.False(t, res[0].CreatedAt.IsZero())
+
+		res, err = repo.CreateMany(ctx, []domain.TodoRevision{})
+
+		assert.Nil(t, err)
+		assert.Empty(t, res)
+	})
+
+	t.Run("Get Newest First", func(t *testing.T) {
+		repo := newRepo(t)
+		seeded := seedTodoRevisions(t, repo, "1", 3)
+		seedTodoRevisions(t, repo, "2", 1)
+
+		res, total, err := repo.Get(ctx, "1", 0, -1)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, []string{seeded[2].ID, seeded[1].ID, seeded[0].ID}, todoRevisionIDs(res))
+	})
+
+	t.Run("Get Paging", func(t *testing.T) {
+		repo := newRepo(t)
+		seeded := seedTodoRevisions(t, repo, "1", 3)
+
+		res, total, err := repo.Get(ctx, "1", 1, 1)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, []string{seeded[1].ID}, todoRevisionIDs(res))
+
+		res, total, err = repo.Get(ctx, "1", 1, -1)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 3, total)
+		assert.Equal(t, []string{seeded[1].ID, seeded[0].ID}, todoRevisionIDs(res))
+	})
+
+	t.Run("Get Unknown Todo", func(t *testing.T) {
+		repo := newRepo(t)
+		seedTodoRevisions(t, repo, "1", 1)
+
+		res, total, err := repo.Get(ctx, "2", 0, 10)
+
+		require.Nil(t, err)
+		assert.EqualValues(t, 0, total)
+		assert.Empty(t, res)
+	})
+
+	t.Run("GetByID", func(t *testing.T) {
+		repo := newRepo(t)
+		dueAt := time.Now().Truncate(time.Second)
+
+		payload := newTodoRevision("1", "Title 1")
+		payload.State.Tags = []string{"home", "work"}
+		payload.State.Priority = domain.PriorityHigh
+		payload.State.DueAt = &dueAt
+		payload.State.IsCompleted = true
+
+		created, err := repo.CreateMany(ctx, []domain.TodoRevision{payload})
+		require.Nil(t, err)
+
+		res, err := repo.GetByID(ctx, "1", created[0].ID)
+
+		require.Nil(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, created[0].ID, res.ID)
+		assert.Equal(t, domain.RevisionUpdate, res.Action)
+		assert.Equal(t, "alice", res.Actor)
+		require.NotNil(t, res.Before)
+		assert.Equal(t, "Title 1 - Before", res.Before.Title)
+		assert.Equal(t, "Title 1", res.State.Title)
+		assert.Equal(t, []string{"home", "work"}, res.State.Tags)
+		assert.Equal(t, domain.PriorityHigh, res.State.Priority)
+		assert.True(t, res.State.IsCompleted)
+		require.NotNil(t, res.State.DueAt)
+		assert.True(t, dueAt.Equal(*res.State.DueAt))
+	})
+
+	t.Run("GetByID Without Before", func(t *testing.T) {
+		repo := newRepo(t)
+
+		payload := newTodoRevision("1", "Title 1")
+		payload.Action = domain.RevisionCreate
+		payload.Before = nil
+
+		created, err := repo.CreateMany(ctx, []domain.TodoRevision{payload})
+		require.Nil(t, err)
+
+		res, err := repo.GetByID(ctx, "1", created[0].ID)
+
+		require.Nil(t, err)
+		assert.Nil(t, res.Before)
+	})
+
+	t.Run("GetByID Other Todo", func(t *testing.T) {
+		repo := newRepo(t)
+		seeded := seedTodoRevisions(t, repo, "1", 1)
+
+		res, err := repo.GetByID(ctx, "2", seeded[0].ID)
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Nil(t, res)
+	})
+}
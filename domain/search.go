@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var searchWordPattern = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// SearchTerms splits the search query into its lower case terms
+func SearchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// MatchTerm reports whether the word matches one of the terms, a term matches the words it's a prefix of
+// so the plural and other suffixed forms are matched as well
+func MatchTerm(word string, terms []string) bool {
+	word = strings.ToLower(word)
+
+	for _, v := range terms {
+		if strings.HasPrefix(word, v) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Highlight wraps the words of the text matching the terms in <mark> tags, it reports whether any word matched.
+// The text is HTML escaped around the tags so it's safe to render, the words hold letters and numbers only.
+func Highlight(text string, terms []string) (string, bool) {
+	matched := false
+	res := strings.Builder{}
+	last := 0
+
+	for _, loc := range searchWordPattern.FindAllStringIndex(text, -1) {
+		word := text[loc[0]:loc[1]]
+
+		if !MatchTerm(word, terms) {
+			continue
+		}
+
+		matched = true
+
+		res.WriteString(html.EscapeString(text[last:loc[0]]))
+		res.WriteString("<mark>" + word + "</mark>")
+		last = loc[1]
+	}
+
+	res.WriteString(html.EscapeString(text[last:]))
+
+	return res.String(), matched
+}
@@ -0,0 +1,52 @@
+package domain
+
+// TodoNode: TodoNode is a todo of a subtree, Depth is 0 for the root of the subtree, 1 for its subtasks and so on.
+// The repositories read a subtree as the flat list of its nodes, the root first then the others by depth and id.
+type TodoNode struct {
+	Todo     `bson:",inline"`
+	Depth    int64      `json:"depth" bson:"depth"`
+	Subtasks []TodoNode `json:"subtasks" bson:"-"`
+}
+
+// TodoCompletion: TodoCompletion is the completed todo along with its subtasks which were completed with it
+// or are still incomplete
+type TodoCompletion struct {
+	Todo
+	CompletedSubtasks  []string `json:"completedSubtasks,omitempty"`
+	IncompleteSubtasks []string `json:"incompleteSubtasks,omitempty"`
+}
+
+// TodoStatusDto: TodoStatusDto model struct, Cascade completes the incomplete subtasks along with the todo
+type TodoStatusDto struct {
+	IsCompleted bool `json:"isCompleted"`
+	Cascade     bool `json:"cascade"`
+}
+
+// NestTodoNodes nests the flat subtree read by the repositories, the first node is the root.
+// The subtasks keep the order of the nodes.
+func NestTodoNodes(nodes []TodoNode) *TodoNode {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	children := map[string][]TodoNode{}
+
+	for _, v := range nodes[1:] {
+		children[v.ParentID] = append(children[v.ParentID], v)
+	}
+
+	var nest func(node TodoNode) TodoNode
+	nest = func(node TodoNode) TodoNode {
+		node.Subtasks = []TodoNode{}
+
+		for _, v := range children[node.ID] {
+			node.Subtasks = append(node.Subtasks, nest(v))
+		}
+
+		return node
+	}
+
+	root := nest(nodes[0])
+
+	return &root
+}
@@ -2,14 +2,23 @@ package domain
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"time"
 )
 
 // Todo: Todo model struct
 type Todo struct {
-	ID          string `json:"id" bson:"_id"`
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
-	IsCompleted bool   `json:"isCompleted" bson:"isCompleted"`
+	ID          string     `json:"id" bson:"_id"`
+	Title       string     `json:"title" validate:"required"`
+	Description string     `json:"description" validate:"required"`
+	IsCompleted bool       `json:"isCompleted" bson:"isCompleted"`
+	Tags        []string   `json:"tags,omitempty" bson:"tags,omitempty"`
+	Priority    Priority   `json:"priority,omitempty" bson:"priority"`
+	DueAt       *time.Time `json:"dueAt,omitempty" bson:"dueAt,omitempty"`
+	ParentID    string     `json:"parentId,omitempty" bson:"parentId,omitempty"`
+	ListID      string     `json:"listId,omitempty" bson:"listId,omitempty"`
+	Version     int64      `json:"version" bson:"version"`
 	*Audit
 }
 
@@ -17,28 +26,228 @@ func (t Todo) TableName() string {
 	return "todos"
 }
 
-// TodoDto: TodoDto model struct
+// FieldValue returns the value of the field by its json name
+func (t Todo) FieldValue(field string) interface{} {
+	switch field {
+	case "id":
+		return t.ID
+	case "title":
+		return t.Title
+	case "description":
+		return t.Description
+	case "isCompleted":
+		return t.IsCompleted
+	case "tags":
+		return t.Tags
+	case "priority":
+		return t.Priority
+	case "dueAt":
+		// a todo without a due date has a null value, it's sorted before the dates
+		if t.DueAt == nil {
+			return nil
+		}
+
+		return *t.DueAt
+	case "parentId":
+		// a root todo has a null parent
+		if t.ParentID == "" {
+			return nil
+		}
+
+		return t.ParentID
+	case "listId":
+		// a todo out of any list has a null list
+		if t.ListID == "" {
+			return nil
+		}
+
+		return t.ListID
+	case "version":
+		return t.Version
+	case "createdAt", "updatedAt":
+		if t.Audit == nil {
+			return time.Time{}
+		}
+
+		if field == "createdAt" {
+			return t.CreatedAt
+		}
+
+		return t.UpdatedAt
+	}
+
+	return nil
+}
+
+// TodoFields are the fields of the todos which can be selected
+var TodoFields = []string{"id", "title", "description", "isCompleted", "tags", "priority", "dueAt", "parentId", "listId", "version", "createdAt", "updatedAt"}
+
+// TodoSortFields are the fields the todos can be sorted by
+var TodoSortFields = []string{"id", "title", "isCompleted", "priority", "dueAt", "createdAt", "updatedAt"}
+
+// TodoNullableFields are the fields of the todos which may have no value
+var TodoNullableFields = []string{"dueAt", "parentId", "listId"}
+
+// TodoFilterFields are the fields the todos can be filtered by along with their type
+var TodoFilterFields = map[string]FieldType{
+	"id":          FieldTypeString,
+	"title":       FieldTypeString,
+	"description": FieldTypeString,
+	"isCompleted": FieldTypeBool,
+	"tags":        FieldTypeTags,
+	"priority":    FieldTypePriority,
+	"dueAt":       FieldTypeTime,
+	"parentId":    FieldTypeString,
+	"listId":      FieldTypeString,
+	"createdAt":   FieldTypeTime,
+	"updatedAt":   FieldTypeTime,
+}
+
+// TodoPage: TodoPage is a keyset page of todos, Next and Prev are nil when there is no such page
+type TodoPage struct {
+	Items []Todo
+	Next  *Cursor
+	Prev  *Cursor
+}
+
+// NewTodoCursor creates the cursor positioned at the todo for the given sort keys
+func NewTodoCursor(t Todo, sort []Sort, backward bool) *Cursor {
+	values := []interface{}{}
+
+	for _, v := range KeysetKeys(sort) {
+		values = append(values, t.FieldValue(v.Field))
+	}
+
+	return &Cursor{
+		Values:   values,
+		Backward: backward,
+	}
+}
+
+// TodoSearchResult: TodoSearchResult model struct, Highlights holds the matching fields with the matched words marked
+type TodoSearchResult struct {
+	Todo       `bson:",inline"`
+	Score      float64           `json:"score" bson:"score"`
+	Highlights map[string]string `json:"highlights,omitempty" bson:"-"`
+}
+
+// TodoDto: TodoDto model struct, nil Tags keep the tags of the todo on update.
+// An empty Priority is the default medium one, a nil DueAt has no due date, an empty ParentID makes a root todo
+// and an empty ListID keeps it out of any list.
 type TodoDto struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
+	Title       string     `json:"title" validate:"required,notblank,max=255"`
+	Description string     `json:"description" validate:"required,max=2000"`
+	Tags        []string   `json:"tags" validate:"omitempty,max=20,dive,tag"`
+	Priority    string     `json:"priority" validate:"omitempty,oneof=low medium high urgent"`
+	DueAt       *time.Time `json:"dueAt"`
+	ParentID    string     `json:"parentId" validate:"omitempty,mongodb"`
+	ListID      string     `json:"listId" validate:"omitempty,mongodb"`
+}
+
+// PriorityValue returns the priority of the payload, the default is medium
+func (d TodoDto) PriorityValue() Priority {
+	if v, ok := ParsePriority(d.Priority); ok {
+		return v
+	}
+
+	return PriorityMedium
+}
+
+// OverdueFilter matches the incomplete todos which are due before the time
+func OverdueFilter(now time.Time) *Filter {
+	return And(
+		Where("isCompleted", FoEq, false),
+		Where("dueAt", FoLt, now),
+	)
+}
+
+// TodoMaxTags is the most tags a todo can have, the max of the validate tags
+const TodoMaxTags = 20
+
+// TodoTagsDto: TodoTagsDto model struct
+type TodoTagsDto struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=20,dive,tag"`
+}
+
+// TagCount: TagCount model struct, Count is the number of todos having the tag
+type TagCount struct {
+	Tag   string `json:"tag" bson:"_id"`
+	Count int64  `json:"count" bson:"count"`
+}
+
+// NormalizeTags trims and lower cases the tags then sorts them without duplicates, nil stays nil
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	result := []string{}
+
+	for _, v := range tags {
+		v = strings.ToLower(strings.TrimSpace(v))
+
+		if v != "" && !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+
+	slices.Sort(result)
+
+	return result
 }
 
-// TodoService represent the todo's usecases
+// TodoService represent the todo's usecases, the version given to a write is the one the todo is expected to have,
+// zero writes whatever the version is. Update and UpdateStatus never create the todo, Replace creates it with the id
+// when it's missing and tells whether it did
 type TodoService interface {
-	Get(ctx context.Context, filter interface{}, skip, limit int64) ([]Todo, int64, error)
-	GetByID(ctx context.Context, id string) (*Todo, error)
-	Update(ctx context.Context, id string, payload *TodoDto) (*Todo, error)
-	UpdateStatus(ctx context.Context, id string, isCompleted bool) (*Todo, error)
+	Get(ctx context.Context, filter *Filter, sort []Sort, skip, limit int64, fields ...string) ([]Todo, int64, error)
+	GetByID(ctx context.Context, id string, fields ...string) (*Todo, error)
+	Update(ctx context.Context, id string, payload *TodoDto, version int64) (*Todo, error)
+	UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*Todo, error)
 	Create(ctx context.Context, payload *TodoDto) (*Todo, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
+	Replace(ctx context.Context, id string, payload *TodoDto) (*Todo, bool, error)
+	GetByCursor(ctx context.Context, filter *Filter, sort []Sort, cursor *Cursor, limit int64, fields ...string) (*TodoPage, error)
+	Search(ctx context.Context, query string, skip, limit int64) ([]TodoSearchResult, int64, error)
+	AddTags(ctx context.Context, id string, tags []string) (*Todo, error)
+	RemoveTags(ctx context.Context, id string, tags []string) (*Todo, error)
+	GetTags(ctx context.Context) ([]TagCount, error)
+	GetOverdue(ctx context.Context, sort []Sort, skip, limit int64, fields ...string) ([]Todo, int64, error)
+	GetSubtree(ctx context.Context, id string) (*TodoNode, error)
+	Complete(ctx context.Context, id string, cascade bool, version int64) (*TodoCompletion, error)
+	GetTrash(ctx context.Context, skip, limit int64) ([]Todo, int64, error)
+	Restore(ctx context.Context, id string) (*Todo, error)
+	Purge(ctx context.Context) (int64, error)
+	GetHistory(ctx context.Context, id string, skip, limit int64) ([]TodoRevision, int64, error)
+	Revert(ctx context.Context, id string, revisionID string) (*Todo, error)
 }
 
-// TodoRepository represent the todo's repository contract
+// TodoRepository represent the todo's repository contract, Delete and DeleteMany move the todos to the trash
+// where the other reads and writes don't see them until they're restored or purged.
+// Every write increments the version of the todos, Update, UpdateStatus and Delete fail with ErrPrecondition
+// when the todo has another version than the given one, zero writes whatever the version is.
+// Revert writes the whole state of a revision at once, the completion included, whatever the version is.
+// The writes of a single todo fail with ErrNotFound when it's missing, except Replace which creates it with the id
+// and fails with ErrConflict when the id is in the trash.
 type TodoRepository interface {
-	Get(ctx context.Context, filter interface{}, skip, limit int64) ([]Todo, int64, error)
-	GetByID(ctx context.Context, id string) (*Todo, error)
-	Update(ctx context.Context, id string, payload *TodoDto) (*Todo, error)
-	UpdateStatus(ctx context.Context, id string, isCompleted bool) (*Todo, error)
+	Get(ctx context.Context, filter *Filter, sort []Sort, skip, limit int64, fields ...string) ([]Todo, int64, error)
+	GetByID(ctx context.Context, id string, fields ...string) (*Todo, error)
+	Update(ctx context.Context, id string, payload *TodoDto, version int64) (*Todo, error)
+	UpdateStatus(ctx context.Context, id string, isCompleted bool, version int64) (*Todo, error)
 	Create(ctx context.Context, payload *TodoDto) (*Todo, error)
-	Delete(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string, version int64) error
+	Replace(ctx context.Context, id string, payload *TodoDto) (*Todo, bool, error)
+	GetByCursor(ctx context.Context, filter *Filter, sort []Sort, cursor *Cursor, limit int64, fields ...string) ([]Todo, bool, error)
+	Search(ctx context.Context, query string, skip, limit int64) ([]TodoSearchResult, int64, error)
+	AddTags(ctx context.Context, id string, tags []string) (*Todo, error)
+	RemoveTags(ctx context.Context, id string, tags []string) (*Todo, error)
+	GetTags(ctx context.Context) ([]TagCount, error)
+	GetSubtree(ctx context.Context, id string) ([]TodoNode, error)
+	UpdateStatusMany(ctx context.Context, ids []string, isCompleted bool) error
+	UpdateListMany(ctx context.Context, ids []string, listID string) error
+	DeleteMany(ctx context.Context, ids []string) error
+	GetTrash(ctx context.Context, filter *Filter, skip, limit int64) ([]Todo, int64, error)
+	Restore(ctx context.Context, id string) (*Todo, error)
+	Revert(ctx context.Context, id string, state TodoState) (*Todo, error)
+	Purge(ctx context.Context, before time.Time) (int64, error)
 }
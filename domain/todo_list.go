@@ -0,0 +1,73 @@
+package domain
+
+import "context"
+
+// TodoList: TodoList model struct
+type TodoList struct {
+	ID          string `json:"id" bson:"_id"`
+	Name        string `json:"name" bson:"name"`
+	Description string `json:"description" bson:"description"`
+	*Audit
+}
+
+func (l TodoList) TableName() string {
+	return "todo_lists"
+}
+
+// TodoListDetail: TodoListDetail is the list along with its todos ordered by id
+type TodoListDetail struct {
+	TodoList `bson:",inline"`
+	Todos    []Todo `json:"todos" bson:"todos"`
+}
+
+// TodoListDto: TodoListDto model struct
+type TodoListDto struct {
+	Name        string `json:"name" validate:"required,notblank,max=100"`
+	Description string `json:"description" validate:"max=2000"`
+}
+
+// ListDeletePolicy: ListDeletePolicy tells what happens to the todos of a deleted list
+type ListDeletePolicy string
+
+const (
+	// ListDeleteRefuse refuses to delete a list which still has todos
+	ListDeleteRefuse ListDeletePolicy = "refuse"
+	// ListDeleteCascade deletes the todos along with the list
+	ListDeleteCascade ListDeletePolicy = "cascade"
+	// ListDeleteReassign moves the todos to another list, or out of any list without one
+	ListDeleteReassign ListDeletePolicy = "reassign"
+)
+
+// TodoListDeleteDto: TodoListDeleteDto model struct, an empty Policy refuses. To is the list the todos are moved to on reassign.
+type TodoListDeleteDto struct {
+	Policy string `json:"policy" query:"policy" validate:"omitempty,oneof=refuse cascade reassign"`
+	To     string `json:"to" query:"to" validate:"omitempty,mongodb"`
+}
+
+// PolicyValue returns the delete policy of the payload, the default is refuse
+func (d TodoListDeleteDto) PolicyValue() ListDeletePolicy {
+	if d.Policy == "" {
+		return ListDeleteRefuse
+	}
+
+	return ListDeletePolicy(d.Policy)
+}
+
+// TodoListService represent the todo list's usecases
+type TodoListService interface {
+	Get(ctx context.Context, skip, limit int64) ([]TodoList, int64, error)
+	GetByID(ctx context.Context, id string) (*TodoListDetail, error)
+	Create(ctx context.Context, payload *TodoListDto) (*TodoList, error)
+	Update(ctx context.Context, id string, payload *TodoListDto) (*TodoList, error)
+	Delete(ctx context.Context, id string, policy ListDeletePolicy, to string) error
+}
+
+// TodoListRepository represent the todo list's repository contract
+type TodoListRepository interface {
+	Get(ctx context.Context, skip, limit int64) ([]TodoList, int64, error)
+	GetByID(ctx context.Context, id string) (*TodoList, error)
+	GetWithTodos(ctx context.Context, id string) (*TodoListDetail, error)
+	Create(ctx context.Context, payload *TodoListDto) (*TodoList, error)
+	Update(ctx context.Context, id string, payload *TodoListDto) (*TodoList, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// RevisionAction: RevisionAction tells which change of a todo a revision records
+type RevisionAction string
+
+const (
+	RevisionCreate  RevisionAction = "create"
+	RevisionUpdate  RevisionAction = "update"
+	RevisionStatus  RevisionAction = "status"
+	RevisionDelete  RevisionAction = "delete"
+	RevisionRestore RevisionAction = "restore"
+	RevisionRevert  RevisionAction = "revert"
+)
+
+// TodoStateFields are the fields of the todos the revisions keep track of
+var TodoStateFields = []string{"title", "description", "isCompleted", "tags", "priority", "dueAt", "parentId", "listId"}
+
+// TodoState: TodoState is the part of a todo the revisions keep track of, Tags is never nil
+type TodoState struct {
+	Title       string     `json:"title" bson:"title"`
+	Description string     `json:"description" bson:"description"`
+	IsCompleted bool       `json:"isCompleted" bson:"isCompleted"`
+	Tags        []string   `json:"tags" bson:"tags"`
+	Priority    Priority   `json:"priority" bson:"priority"`
+	DueAt       *time.Time `json:"dueAt" bson:"dueAt"`
+	ParentID    string     `json:"parentId" bson:"parentId"`
+	ListID      string     `json:"listId" bson:"listId"`
+}
+
+// NewTodoState returns the state of the todo
+func NewTodoState(t Todo) TodoState {
+	return TodoState{
+		Title:       t.Title,
+		Description: t.Description,
+		IsCompleted: t.IsCompleted,
+		Tags:        append([]string{}, t.Tags...),
+		Priority:    t.Priority,
+		DueAt:       t.DueAt,
+		ParentID:    t.ParentID,
+		ListID:      t.ListID,
+	}
+}
+
+// Dto returns the payload updating a todo to the state, the completion isn't part of it
+func (s TodoState) Dto() *TodoDto {
+	return &TodoDto{
+		Title:       s.Title,
+		Description: s.Description,
+		Tags:        append([]string{}, s.Tags...),
+		Priority:    s.Priority.String(),
+		DueAt:       s.DueAt,
+		ParentID:    s.ParentID,
+		ListID:      s.ListID,
+	}
+}
+
+// FieldValue returns the value of the field by its json name, the fields without a value are null
+func (s TodoState) FieldValue(field string) interface{} {
+	if field == "priority" && s.Priority == 0 {
+		return nil
+	}
+
+	return Todo{
+		Title:       s.Title,
+		Description: s.Description,
+		IsCompleted: s.IsCompleted,
+		Tags:        s.Tags,
+		Priority:    s.Priority,
+		DueAt:       s.DueAt,
+		ParentID:    s.ParentID,
+		ListID:      s.ListID,
+	}.FieldValue(field)
+}
+
+func sameValue(a, b interface{}) bool {
+	switch v := a.(type) {
+	case time.Time:
+		w, ok := b.(time.Time)
+		return ok && v.Equal(w)
+	case []string:
+		w, ok := b.([]string)
+		return ok && slices.Equal(v, w)
+	}
+
+	return a == b
+}
+
+// FieldChange: FieldChange is the change of a field of a todo, From and To are null for a field without a value
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Diff returns the fields changed from the previous state in the order of TodoStateFields, without a previous
+// state the fields having a value are changed
+func (s TodoState) Diff(prev *TodoState) []FieldChange {
+	if prev == nil {
+		prev = &TodoState{Tags: []string{}}
+	}
+
+	result := []FieldChange{}
+
+	for _, v := range TodoStateFields {
+		from, to := prev.FieldValue(v), s.FieldValue(v)
+
+		if !sameValue(from, to) {
+			result = append(result, FieldChange{Field: v, From: from, To: to})
+		}
+	}
+
+	return result
+}
+
+// TodoRevision: TodoRevision is an immutable record of a change of a todo. State is the todo right after the change,
+// or as it was deleted, Before is the todo right before it and is nil on create.
+// Changes is the field-level diff between both, it's derived when the revisions are read.
+type TodoRevision struct {
+	ID        string         `json:"id" bson:"_id"`
+	TodoID    string         `json:"todoId" bson:"todoId"`
+	Action    RevisionAction `json:"action" bson:"action"`
+	Actor     string         `json:"actor" bson:"actor"`
+	Before    *TodoState     `json:"-" bson:"before,omitempty"`
+	State     TodoState      `json:"state" bson:"state"`
+	Changes   []FieldChange  `json:"changes" bson:"-"`
+	CreatedAt time.Time      `json:"createdAt" bson:"createdAt"`
+}
+
+func (r TodoRevision) TableName() string {
+	return "todo_revisions"
+}
+
+// NewTodoRevision records the change of the todo made by the actor of the context, before is nil on create
+func NewTodoRevision(ctx context.Context, action RevisionAction, before *Todo, after Todo) TodoRevision {
+	result := TodoRevision{
+		TodoID: after.ID,
+		Action: action,
+		Actor:  ActorFromContext(ctx),
+		State:  NewTodoState(after),
+	}
+
+	if before != nil {
+		state := NewTodoState(*before)
+		result.Before = &state
+	}
+
+	return result
+}
+
+type actorKey struct{}
+
+// AnonymousActor is the actor of the changes made without one
+const AnonymousActor = "anonymous"
+
+// WithActor returns the context carrying who makes the changes
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns who makes the changes, it's AnonymousActor when the context carries none
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+
+	return AnonymousActor
+}
+
+// TodoRevisionRepository represent the todo revision's repository contract, the revisions of a todo are read
+// from the most recent one
+type TodoRevisionRepository interface {
+	CreateMany(ctx context.Context, payload []TodoRevision) ([]TodoRevision, error)
+	Get(ctx context.Context, todoID string, skip, limit int64) ([]TodoRevision, int64, error)
+	GetByID(ctx context.Context, todoID string, id string) (*TodoRevision, error)
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "context"
+
+// Transactor runs the writes of the repositories as one unit, either all of them are applied or none is.
+// The repositories join the unit through the context given to fn, it must be used by every call made in fn.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -0,0 +1,39 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ActorHeader is the request header telling who makes the changes, it's recorded in the revisions of the todos
+const ActorHeader = "X-Actor"
+
+// actorMaxLength is the longest actor the revisions can keep
+const actorMaxLength = 100
+
+// Actor is the fiber middleware carrying the actor of the request in its user context, the requests without one
+// are made by domain.AnonymousActor
+func Actor(c *fiber.Ctx) error {
+	// the header is only valid during the request, the actor outlives it in the revisions
+	actor := strings.Clone(strings.TrimSpace(c.Get(ActorHeader)))
+
+	if actor == "" {
+		return c.Next()
+	}
+
+	if utf8.RuneCountInString(actor) > actorMaxLength {
+		return domain.NewFieldValidationError(domain.FieldError{
+			Field:   ActorHeader,
+			Rule:    "max",
+			Message: fmt.Sprintf("%s must be at most %d characters", ActorHeader, actorMaxLength),
+		})
+	}
+
+	c.SetUserContext(domain.WithActor(c.UserContext(), actor))
+
+	return c.Next()
+}
@@ -0,0 +1,66 @@
+package helper_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: helper.ErrorHandler,
+	})
+
+	app.Use(helper.Actor)
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(domain.ActorFromContext(c.UserContext()))
+	})
+
+	cases := []struct {
+		name   string
+		actor  string
+		status int
+		result string
+	}{
+		{
+			name:   "Success",
+			actor:  " alice ",
+			status: http.StatusOK,
+			result: "alice",
+		},
+		{
+			name:   "Success - Anonymous",
+			status: http.StatusOK,
+			result: domain.AnonymousActor,
+		},
+		{
+			name:   "Failed - Too Long",
+			actor:  strings.Repeat("a", 101),
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(helper.ActorHeader, c.actor)
+
+			res, _ := app.Test(req)
+
+			assert.Equal(t, c.status, res.StatusCode)
+
+			if c.result != "" {
+				body, _ := io.ReadAll(res.Body)
+
+				assert.Equal(t, c.result, string(body))
+			}
+		})
+	}
+}
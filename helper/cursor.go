@@ -0,0 +1,124 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+)
+
+type cursorPayload struct {
+	Sort     string        `json:"s"`
+	Values   []interface{} `json:"v"`
+	Backward bool          `json:"b,omitempty"`
+}
+
+func cursorSort(sort []domain.Sort) string {
+	keys := []string{}
+
+	for _, v := range domain.KeysetKeys(sort) {
+		keys = append(keys, fmt.Sprintf("%s*%s", v.Field, strings.ToLower(string(v.Order))))
+	}
+
+	return strings.Join(keys, ",")
+}
+
+func invalidCursor(message string) error {
+	return domain.NewFieldValidationError(domain.FieldError{
+		Field:   "cursor",
+		Rule:    "cursor",
+		Message: message,
+	})
+}
+
+// EncodeCursor encodes the cursor into an opaque url safe string, a nil cursor is encoded as an empty string
+func EncodeCursor(cursor *domain.Cursor, sort []domain.Sort) string {
+	if cursor == nil {
+		return ""
+	}
+
+	b, _ := json.Marshal(cursorPayload{
+		Sort:     cursorSort(sort),
+		Values:   cursor.Values,
+		Backward: cursor.Backward,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor decodes the cursor made by EncodeCursor, the values are typed by the given fields.
+// An empty string is decoded as a nil cursor and a cursor made for another sort is rejected.
+func DecodeCursor(cursor string, sort []domain.Sort, fields map[string]domain.FieldType) (*domain.Cursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalidCursor("cursor is malformed")
+	}
+
+	payload := cursorPayload{}
+
+	if err := json.Unmarshal(b, &payload); err != nil {
+		return nil, invalidCursor("cursor is malformed")
+	}
+
+	keys := domain.KeysetKeys(sort)
+
+	if payload.Sort != cursorSort(sort) || len(payload.Values) != len(keys) {
+		return nil, invalidCursor("cursor does not match the sort")
+	}
+
+	values := []interface{}{}
+
+	for i, v := range keys {
+		value := payload.Values[i]
+
+		// the null values of the nullable fields are kept as they are
+		if value == nil && fields[v.Field] == domain.FieldTypeTime {
+			values = append(values, value)
+			continue
+		}
+
+		switch fields[v.Field] {
+		case domain.FieldTypeTime:
+			s, _ := value.(string)
+
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, invalidCursor("cursor is malformed")
+			}
+
+			value = t
+		case domain.FieldTypeBool:
+			if _, ok := value.(bool); !ok {
+				return nil, invalidCursor("cursor is malformed")
+			}
+		case domain.FieldTypePriority:
+			s, _ := value.(string)
+
+			// the todos without a priority have a null one
+			p, ok := domain.ParsePriority(s)
+			if !ok && value != nil {
+				return nil, invalidCursor("cursor is malformed")
+			}
+
+			value = p
+		default:
+			if _, ok := value.(string); !ok {
+				return nil, invalidCursor("cursor is malformed")
+			}
+		}
+
+		values = append(values, value)
+	}
+
+	return &domain.Cursor{
+		Values:   values,
+		Backward: payload.Backward,
+	}, nil
+}
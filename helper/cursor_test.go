@@ -0,0 +1,75 @@
+package helper_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	sort := []domain.Sort{
+		{Field: "isCompleted", Order: domain.SortAsc},
+		{Field: "createdAt", Order: domain.SortDesc},
+	}
+
+	cursor := &domain.Cursor{
+		Values:   []interface{}{true, time.Date(2023, 10, 1, 8, 0, 0, 123456789, time.UTC), "6512d6f0a7e2b1c3d4e5f607"},
+		Backward: true,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		encoded := helper.EncodeCursor(cursor, sort)
+
+		res, err := helper.DecodeCursor(encoded, sort, domain.TodoFilterFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, cursor, res)
+	})
+
+	t.Run("Success - Nullable", func(t *testing.T) {
+		sort := []domain.Sort{
+			{Field: "priority", Order: domain.SortDesc},
+			{Field: "dueAt", Order: domain.SortAsc},
+		}
+		cursor := &domain.Cursor{
+			Values: []interface{}{domain.PriorityHigh, nil, "6512d6f0a7e2b1c3d4e5f607"},
+		}
+
+		res, err := helper.DecodeCursor(helper.EncodeCursor(cursor, sort), sort, domain.TodoFilterFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, cursor, res)
+	})
+
+	t.Run("Success - Empty", func(t *testing.T) {
+		assert.Equal(t, "", helper.EncodeCursor(nil, sort))
+
+		res, err := helper.DecodeCursor("", sort, domain.TodoFilterFields)
+
+		assert.Nil(t, err)
+		assert.Nil(t, res)
+	})
+
+	cases := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Failed - Malformed", cursor: "not a cursor"},
+		{name: "Failed - Malformed Json", cursor: base64.RawURLEncoding.EncodeToString([]byte("{"))},
+		{name: "Failed - Another Sort", cursor: helper.EncodeCursor(cursor, []domain.Sort{{Field: "title", Order: domain.SortAsc}})},
+		{name: "Failed - Invalid Value", cursor: helper.EncodeCursor(&domain.Cursor{Values: []interface{}{"true", "yesterday", "1"}}, sort)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := helper.DecodeCursor(c.cursor, sort, domain.TodoFilterFields)
+
+			assert.ErrorIs(t, err, domain.ErrValidation)
+			assert.Nil(t, res)
+		})
+	}
+}
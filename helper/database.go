@@ -3,14 +3,113 @@ package helper
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/ariefsn/go-resik/logger"
 	_ "github.com/go-sql-driver/mysql"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+type DbDriver string
+
+const (
+	DbDriverMongo  DbDriver = "mongo"
+	DbDriverMysql  DbDriver = "mysql"
+	DbDriverMemory DbDriver = "memory"
+)
+
+// Database holds the client of the configured driver, only the one matching Driver is set
+type Database struct {
+	Driver DbDriver
+	Mongo  *mongo.Database
+	Mysql  *sql.DB
+
+	mu      sync.Mutex
+	mongoTx *bool
+}
+
+// Close releases the client of the configured driver
+func (d *Database) Close() error {
+	switch d.Driver {
+	case DbDriverMongo:
+		return d.Mongo.Client().Disconnect(context.Background())
+	case DbDriverMysql:
+		return d.Mysql.Close()
+	}
+
+	return nil
+}
+
+// WithinTransaction implements domain.Transactor. MySQL runs fn in a transaction the repositories read from the
+// context, Mongo in the transaction of a session when the server runs them. A standalone Mongo server and Memory run
+// fn as is, they can't roll back.
+func (d *Database) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	switch d.Driver {
+	case DbDriverMongo:
+		// the context already runs in the transaction of a session, fn joins it
+		if mongo.SessionFromContext(ctx) != nil {
+			return fn(ctx)
+		}
+
+		supported, err := d.mongoTransactions(ctx)
+		if err != nil {
+			logger.Error(err)
+			return ParseMongoError(err)
+		}
+
+		if !supported {
+			return fn(ctx)
+		}
+
+		session, err := d.Mongo.Client().StartSession()
+		if err != nil {
+			logger.Error(err)
+			return ParseMongoError(err)
+		}
+		defer session.EndSession(ctx)
+
+		_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sc)
+		})
+
+		return err
+	case DbDriverMysql:
+		return MySqlWithinTransaction(ctx, d.Mysql, fn)
+	}
+
+	return fn(ctx)
+}
+
+// mongoTransactions tells whether the Mongo server runs transactions, only the members of a replica set and the
+// routers of a sharded cluster do. The answer of the server is kept once known
+func (d *Database) mongoTransactions(ctx context.Context) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.mongoTx != nil {
+		return *d.mongoTx, nil
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	if err := d.Mongo.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+
+	supported := hello.SetName != "" || hello.Msg == "isdbgrid"
+	d.mongoTx = &supported
+
+	return supported, nil
+}
+
 func MySqlClient(address string) *sql.DB {
 	db, err := sql.Open("mysql", address)
 	if err != nil {
@@ -23,6 +122,101 @@ func MySqlClient(address string) *sql.DB {
 	return db
 }
 
+type mySqlTxKey struct{}
+
+// MySqlConn runs the queries, it's either the database or a transaction
+type MySqlConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// MySqlConnOf returns the transaction of the context, or the database when the context runs in none
+func MySqlConnOf(ctx context.Context, db *sql.DB) MySqlConn {
+	if tx, ok := ctx.Value(mySqlTxKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return db
+}
+
+// MySqlWithTx runs fn in a transaction, it's committed unless fn fails. When the context already runs in one fn
+// joins it, the transaction is committed or rolled back by the one which began it
+func MySqlWithTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	if tx, ok := ctx.Value(mySqlTxKey{}).(*sql.Tx); ok {
+		return fn(tx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+
+	if err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		logger.Error(err)
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logger.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// MySqlWithinTransaction runs fn in a transaction like MySqlWithTx, the queries made with MySqlConnOf and the context
+// given to fn run in it
+func MySqlWithinTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) error {
+	return MySqlWithTx(ctx, db, func(tx *sql.Tx) error {
+		return fn(context.WithValue(ctx, mySqlTxKey{}, tx))
+	})
+}
+
+// mySqlMaxRows is the largest row count of a LIMIT
+const mySqlMaxRows = "18446744073709551615"
+
+// MySqlLimit returns the LIMIT and OFFSET clause of the page along with its args, a negative limit reads every row.
+// MySQL only accepts an OFFSET after a LIMIT, skipping rows without a limit uses the largest row count.
+func MySqlLimit(skip int64, limit int64) (string, []interface{}) {
+	if limit < 0 && skip <= 0 {
+		return "", nil
+	}
+
+	query := " LIMIT " + mySqlMaxRows
+	args := []interface{}{}
+
+	if limit >= 0 {
+		query = " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	if skip > 0 {
+		query += " OFFSET ?"
+		args = append(args, skip)
+	}
+
+	return query, args
+}
+
+// MySqlExecSchema runs the statements of the schema one by one, the driver doesn't run several in a single call
+func MySqlExecSchema(ctx context.Context, db *sql.DB, schema string) error {
+	for _, stmt := range strings.Split(schema, ";") {
+		if strings.TrimSpace(stmt) == "" {
+			continue
+		}
+
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func MongoClient(address string) (client *mongo.Client, cancel context.CancelFunc) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(address))
@@ -32,3 +226,38 @@ func MongoClient(address string) (client *mongo.Client, cancel context.CancelFun
 
 	return
 }
+
+func MongoAddress(dbEnv envDb) string {
+	return fmt.Sprintf("mongodb://%s:%s@%s:%s", dbEnv.User, dbEnv.Password, dbEnv.Host, dbEnv.Port)
+}
+
+func MySqlAddress(dbEnv envDb) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", dbEnv.User, dbEnv.Password, dbEnv.Host, dbEnv.Port, dbEnv.Db)
+}
+
+// NewDatabase will connect to the database of the given driver using the env config
+func NewDatabase(driver DbDriver) (*Database, error) {
+	env := Env()
+
+	switch driver {
+	case DbDriverMongo:
+		client, cancel := MongoClient(MongoAddress(env.Mongo))
+		cancel()
+
+		return &Database{
+			Driver: driver,
+			Mongo:  client.Database(env.Mongo.Db),
+		}, nil
+	case DbDriverMysql:
+		return &Database{
+			Driver: driver,
+			Mysql:  MySqlClient(MySqlAddress(env.Mysql)),
+		}, nil
+	case DbDriverMemory:
+		return &Database{
+			Driver: driver,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported db driver: %s", driver)
+}
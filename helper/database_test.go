@@ -0,0 +1,212 @@
+package helper_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMySqlExecSchema(t *testing.T) {
+	schema := `CREATE TABLE IF NOT EXISTS a (id INT);
+
+CREATE TABLE IF NOT EXISTS b (id INT);
+`
+
+	t.Run("Success", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS a (id INT)")).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS b (id INT)")).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		assert.Nil(t, helper.MySqlExecSchema(context.TODO(), db, schema))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS a")).WillReturnError(errors.New("some error"))
+
+		assert.NotNil(t, helper.MySqlExecSchema(context.TODO(), db, schema))
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestWithinTransaction(t *testing.T) {
+	t.Run("Success - Mysql", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todo_revisions")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		database := &helper.Database{Driver: helper.DbDriverMysql, Mysql: db}
+
+		err := database.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			if _, err := helper.MySqlConnOf(ctx, db).ExecContext(ctx, "UPDATE todos SET title = ?", "Title 1"); err != nil {
+				return err
+			}
+
+			// the transaction of the context is joined, it's committed once the unit is done
+			return helper.MySqlWithTx(ctx, db, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO todo_revisions (id) VALUES (?)", "1")
+				return err
+			})
+		})
+
+		assert.Nil(t, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failed - Mysql Rolls Back", func(t *testing.T) {
+		db, mock, _ := sqlmock.New()
+		defer db.Close()
+
+		mockError := errors.New("some error")
+
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("UPDATE todos")).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO todo_revisions")).WillReturnError(mockError)
+		mock.ExpectRollback()
+
+		database := &helper.Database{Driver: helper.DbDriverMysql, Mysql: db}
+
+		err := database.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			if _, err := helper.MySqlConnOf(ctx, db).ExecContext(ctx, "UPDATE todos SET title = ?", "Title 1"); err != nil {
+				return err
+			}
+
+			_, err := helper.MySqlConnOf(ctx, db).ExecContext(ctx, "INSERT INTO todo_revisions (id) VALUES (?)", "1")
+			return err
+		})
+
+		assert.Equal(t, mockError, err)
+		assert.Nil(t, mock.ExpectationsWereMet())
+	})
+
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("Success - Mongo Replica Set", func(t *mtest.T) {
+		database := &helper.Database{Driver: helper.DbDriverMongo, Mongo: t.Client.Database("mock-db")}
+
+		t.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "setName", Value: "rs0"}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		err := database.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			assert.NotNil(t, mongo.SessionFromContext(ctx))
+
+			_, err := t.Client.Database("mock-db").Collection("todos").InsertOne(ctx, bson.M{"title": "Title 1"})
+			return err
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "hello", t.GetStartedEvent().CommandName)
+
+		insert := t.GetStartedEvent()
+
+		assert.Equal(t, "insert", insert.CommandName)
+		assert.True(t, insert.Command.Lookup("startTransaction").Boolean())
+		assert.Equal(t, "commitTransaction", t.GetStartedEvent().CommandName)
+	})
+
+	mt.Run("Success - Mongo Standalone", func(t *mtest.T) {
+		database := &helper.Database{Driver: helper.DbDriverMongo, Mongo: t.Client.Database("mock-db")}
+
+		t.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "isWritablePrimary", Value: true}),
+			mtest.CreateSuccessResponse(),
+			mtest.CreateSuccessResponse(),
+		)
+
+		for i := 0; i < 2; i++ {
+			err := database.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+				// a standalone server has no transactions, fn runs without a session
+				assert.Nil(t, mongo.SessionFromContext(ctx))
+
+				_, err := t.Client.Database("mock-db").Collection("todos").InsertOne(ctx, bson.M{"title": "Title 1"})
+				return err
+			})
+
+			assert.Nil(t, err)
+		}
+
+		// the server is asked once
+		assert.Equal(t, "hello", t.GetStartedEvent().CommandName)
+
+		for i := 0; i < 2; i++ {
+			insert := t.GetStartedEvent()
+
+			assert.Equal(t, "insert", insert.CommandName)
+			_, err := insert.Command.LookupErr("startTransaction")
+			assert.NotNil(t, err)
+		}
+	})
+
+	mt.Run("Failed - Mongo Hello", func(t *mtest.T) {
+		database := &helper.Database{Driver: helper.DbDriverMongo, Mongo: t.Client.Database("mock-db")}
+
+		t.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "some error"}))
+
+		called := false
+		err := database.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.NotNil(t, err)
+		assert.False(t, called)
+	})
+
+	t.Run("Success - Memory", func(t *testing.T) {
+		database := &helper.Database{Driver: helper.DbDriverMemory}
+
+		called := false
+		err := database.WithinTransaction(context.TODO(), func(ctx context.Context) error {
+			called = true
+			return nil
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, called)
+	})
+}
+
+func TestMySqlLimit(t *testing.T) {
+	cases := []struct {
+		name  string
+		skip  int64
+		limit int64
+		query string
+		args  []interface{}
+	}{
+		{name: "Every Row", skip: 0, limit: -1, query: "", args: nil},
+		{name: "Limit", skip: 0, limit: 10, query: " LIMIT ?", args: []interface{}{int64(10)}},
+		{name: "Limit And Offset", skip: 20, limit: 10, query: " LIMIT ? OFFSET ?", args: []interface{}{int64(10), int64(20)}},
+		{name: "Offset Without Limit", skip: 20, limit: -1, query: " LIMIT 18446744073709551615 OFFSET ?", args: []interface{}{int64(20)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, args := helper.MySqlLimit(c.skip, c.limit)
+
+			assert.Equal(t, c.query, query)
+			assert.Equal(t, c.args, args)
+		})
+	}
+}
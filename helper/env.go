@@ -4,15 +4,17 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/ariefsn/go-resik/logger"
 	"github.com/joho/godotenv"
 )
 
 type envApp struct {
-	Name string
-	Host string
-	Port string
+	Name        string
+	Host        string
+	Port        string
+	ErrorFormat ErrorFormat
 }
 
 type envDb struct {
@@ -23,11 +25,19 @@ type envDb struct {
 	Db       string
 }
 
+// envTrash: Retention is how long the deleted todos are kept, they're purged every PurgeInterval
+type envTrash struct {
+	Retention     time.Duration
+	PurgeInterval time.Duration
+}
+
 type env struct {
-	App   envApp
-	Debug bool
-	Mongo envDb
-	Mysql envDb
+	App      envApp
+	Debug    bool
+	DbDriver DbDriver
+	Mongo    envDb
+	Mysql    envDb
+	Trash    envTrash
 }
 
 type envValue struct {
@@ -63,6 +73,35 @@ func (e envValue) Bool() bool {
 
 var _env *env
 
+// ParseTrashRetention returns how long the deleted todos are kept, it must be positive as the purge would empty
+// the whole trash otherwise
+func ParseTrashRetention(value string) (time.Duration, error) {
+	v, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid trash retention: %s", value)
+	}
+
+	if v <= 0 {
+		return 0, fmt.Errorf("trash retention must be positive: %s", value)
+	}
+
+	return v, nil
+}
+
+// ParsePurgeInterval returns how often the trash is purged, zero disables the purge
+func ParsePurgeInterval(value string) (time.Duration, error) {
+	v, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid trash purge interval: %s", value)
+	}
+
+	if v < 0 {
+		return 0, fmt.Errorf("trash purge interval must not be negative: %s", value)
+	}
+
+	return v, nil
+}
+
 func fromEnv(key string, fallback ...interface{}) envValue {
 	var fb interface{}
 	if len(fallback) > 0 {
@@ -79,13 +118,31 @@ func InitEnv(envFile ...string) {
 	if err != nil {
 		logger.Warning(err.Error())
 	}
+
+	errorFormat, err := ParseErrorFormat(fromEnv("ERROR_FORMAT", ErrorFormatEnvelope).String())
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	trashRetention, err := ParseTrashRetention(fromEnv("TRASH_RETENTION", "720h").String())
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	purgeInterval, err := ParsePurgeInterval(fromEnv("TRASH_PURGE_INTERVAL", "1h").String())
+	if err != nil {
+		logger.Fatal(err)
+	}
+
 	_env = &env{
 		App: envApp{
-			Name: fromEnv("APP_NAME", "RESIK ARCH").String(),
-			Host: fromEnv("APP_HOST", "0.0.0.0").String(),
-			Port: fromEnv("APP_PORT", "6001").String(),
+			Name:        fromEnv("APP_NAME", "RESIK ARCH").String(),
+			Host:        fromEnv("APP_HOST", "0.0.0.0").String(),
+			Port:        fromEnv("APP_PORT", "6001").String(),
+			ErrorFormat: errorFormat,
 		},
-		Debug: fromEnv("Debug", true).Bool(),
+		Debug:    fromEnv("Debug", true).Bool(),
+		DbDriver: DbDriver(fromEnv("DB_DRIVER", DbDriverMongo).String()),
 		Mongo: envDb{
 			Host:     fromEnv("MONGO_HOST").String(),
 			Port:     fromEnv("MONGO_PORT").String(),
@@ -100,6 +157,10 @@ func InitEnv(envFile ...string) {
 			Password: fromEnv("MYSQL_PASSWORD").String(),
 			Db:       fromEnv("MYSQL_DB").String(),
 		},
+		Trash: envTrash{
+			Retention:     trashRetention,
+			PurgeInterval: purgeInterval,
+		},
 	}
 }
 
@@ -0,0 +1,64 @@
+package helper_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTrashRetention(t *testing.T) {
+	cases := []struct {
+		value  string
+		result time.Duration
+		err    string
+	}{
+		{value: "720h", result: 720 * time.Hour},
+		{value: "1m", result: time.Minute},
+		{value: "0s", err: "trash retention must be positive: 0s"},
+		{value: "-1h", err: "trash retention must be positive: -1h"},
+		{value: "30d", err: "invalid trash retention: 30d"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			res, err := helper.ParseTrashRetention(c.value)
+
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.result, res)
+		})
+	}
+}
+
+func TestParsePurgeInterval(t *testing.T) {
+	cases := []struct {
+		value  string
+		result time.Duration
+		err    string
+	}{
+		{value: "1h", result: time.Hour},
+		{value: "0s", result: 0},
+		{value: "-1h", err: "trash purge interval must not be negative: -1h"},
+		{value: "hourly", err: "invalid trash purge interval: hourly"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.value, func(t *testing.T) {
+			res, err := helper.ParsePurgeInterval(c.value)
+
+			if c.err != "" {
+				assert.EqualError(t, err, c.err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.result, res)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package helper
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/logger"
+	"github.com/gofiber/fiber/v2"
+)
+
+// HttpStatus maps the error to its http status code, errors without a known kind are internal server errors
+func HttpStatus(err error) int {
+	var fiberErr *fiber.Error
+
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+
+	switch domain.ErrorKindOf(err) {
+	case domain.ErrKindNotFound:
+		return http.StatusNotFound
+	case domain.ErrKindValidation:
+		return http.StatusBadRequest
+	case domain.ErrKindConflict:
+		return http.StatusConflict
+	case domain.ErrKindPrecondition:
+		return http.StatusPreconditionFailed
+	case domain.ErrKindUnauthorized:
+		return http.StatusUnauthorized
+	case domain.ErrKindTimeout:
+		return http.StatusGatewayTimeout
+	case domain.ErrKindUnavailable:
+		return http.StatusServiceUnavailable
+	}
+
+	return http.StatusInternalServerError
+}
+
+// ErrorHandler is the fiber error handler, it writes the returned errors in the configured error format
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status := HttpStatus(err)
+
+	if status >= http.StatusInternalServerError {
+		logger.Error(err)
+	}
+
+	if Env().App.ErrorFormat == ErrorFormatProblem {
+		return c.Status(status).JSON(JsonProblem(err, c.OriginalURL()), ContentTypeProblemJson)
+	}
+
+	return c.Status(status).JSON(JsonError(err))
+}
@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETag returns the strong entity tag of the version of a resource
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// WeakETag returns the weak entity tag of the version of a resource, it tags a partial representation such as the
+// one of a projection which the strong tag of the version doesn't identify
+func WeakETag(version int64) string {
+	return "W/" + ETag(version)
+}
+
+// parseETag returns the version of the entity tag, a weak one matches only with the weak comparison
+func parseETag(tag string, weak bool) (int64, bool) {
+	tag = strings.TrimSpace(tag)
+
+	if weak {
+		tag = strings.TrimPrefix(tag, "W/")
+	}
+
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+
+	return version, err == nil && version > 0
+}
+
+// IfMatch returns the version the request expects the resource to have from its If-Match header, zero when it has
+// none or it's * which matches any version. An entity tag which isn't the one of a version never matches.
+func IfMatch(c *fiber.Ctx) (int64, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if strings.Contains(header, ",") {
+		return 0, domain.NewFieldValidationError(domain.FieldError{
+			Field:   fiber.HeaderIfMatch,
+			Rule:    "single",
+			Message: fmt.Sprintf("%s must be a single entity tag", fiber.HeaderIfMatch),
+		})
+	}
+
+	version, ok := parseETag(header, false)
+
+	if !ok {
+		return 0, domain.NewPreconditionError(fmt.Sprintf("%s doesn't match the version", fiber.HeaderIfMatch))
+	}
+
+	return version, nil
+}
+
+// IfNoneMatch reports whether the If-None-Match header of the request matches the version, the weak entity tags
+// match as well
+func IfNoneMatch(c *fiber.Ctx, version int64) bool {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfNoneMatch))
+
+	if header == "*" {
+		return true
+	}
+
+	for _, v := range strings.Split(header, ",") {
+		if tag, ok := parseETag(v, true); ok && tag == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IfNoneMatchAny reports whether the If-None-Match header of the request is `*`, a write with it creates the
+// resource when it's missing
+func IfNoneMatchAny(c *fiber.Ctx) bool {
+	return strings.TrimSpace(c.Get(fiber.HeaderIfNoneMatch)) == "*"
+}
@@ -0,0 +1,201 @@
+package helper_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	assert.Equal(t, `"3"`, helper.ETag(3))
+}
+
+func TestWeakETag(t *testing.T) {
+	assert.Equal(t, `W/"3"`, helper.WeakETag(3))
+}
+
+func TestIfMatch(t *testing.T) {
+	app := fiber.New(fiber.Config{
+		ErrorHandler: helper.ErrorHandler,
+	})
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		version, err := helper.IfMatch(c)
+
+		if err != nil {
+			return err
+		}
+
+		return c.SendString(strconv.FormatInt(version, 10))
+	})
+
+	cases := []struct {
+		name    string
+		header  string
+		status  int
+		version string
+	}{
+		{
+			name:    "Success",
+			header:  `"3"`,
+			status:  http.StatusOK,
+			version: "3",
+		},
+		{
+			name:    "Success - Without Header",
+			status:  http.StatusOK,
+			version: "0",
+		},
+		{
+			name:    "Success - Any",
+			header:  "*",
+			status:  http.StatusOK,
+			version: "0",
+		},
+		{
+			name:   "Failed - Weak",
+			header: `W/"3"`,
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "Failed - Unknown",
+			header: `"abc"`,
+			status: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "Failed - Many",
+			header: `"3", "4"`,
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			if c.header != "" {
+				req.Header.Set(fiber.HeaderIfMatch, c.header)
+			}
+
+			res, _ := app.Test(req)
+
+			assert.Equal(t, c.status, res.StatusCode)
+
+			if c.status == http.StatusOK {
+				body, _ := io.ReadAll(res.Body)
+				assert.Equal(t, c.version, string(body))
+			}
+		})
+	}
+}
+
+func TestIfNoneMatch(t *testing.T) {
+	app := fiber.New()
+
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.FormatBool(helper.IfNoneMatch(c, 3)))
+	})
+
+	cases := []struct {
+		name   string
+		header string
+		result string
+	}{
+		{
+			name:   "Match",
+			header: `"3"`,
+			result: "true",
+		},
+		{
+			name:   "Match - Weak",
+			header: `W/"3"`,
+			result: "true",
+		},
+		{
+			name:   "Match - List",
+			header: `"2", "3"`,
+			result: "true",
+		},
+		{
+			name:   "Match - Any",
+			header: "*",
+			result: "true",
+		},
+		{
+			name:   "Stale",
+			header: `"2"`,
+			result: "false",
+		},
+		{
+			name:   "Without Header",
+			result: "false",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+			if c.header != "" {
+				req.Header.Set(fiber.HeaderIfNoneMatch, c.header)
+			}
+
+			res, _ := app.Test(req)
+
+			body, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, c.result, string(body))
+		})
+	}
+}
+
+func TestIfNoneMatchAny(t *testing.T) {
+	app := fiber.New()
+
+	app.Put("/", func(c *fiber.Ctx) error {
+		return c.SendString(strconv.FormatBool(helper.IfNoneMatchAny(c)))
+	})
+
+	cases := []struct {
+		name   string
+		header string
+		result string
+	}{
+		{
+			name:   "Any",
+			header: " * ",
+			result: "true",
+		},
+		{
+			name:   "Entity Tag",
+			header: `"3"`,
+			result: "false",
+		},
+		{
+			name:   "Without Header",
+			result: "false",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/", nil)
+
+			if c.header != "" {
+				req.Header.Set(fiber.HeaderIfNoneMatch, c.header)
+			}
+
+			res, _ := app.Test(req)
+
+			body, _ := io.ReadAll(res.Body)
+
+			assert.Equal(t, c.result, string(body))
+		})
+	}
+}
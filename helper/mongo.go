@@ -3,26 +3,31 @@ package helper
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
+	"github.com/ariefsn/go-resik/domain"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-type FilterOperator string
+type FilterOperator = domain.FilterOperator
 
 const (
-	FoContains  FilterOperator = "$contains"
-	FoStartWith FilterOperator = "$startWith"
-	FoEndWith   FilterOperator = "$endWith"
-	FoEq        FilterOperator = "$eq"
-	FoNe        FilterOperator = "$ne"
-	FoIn        FilterOperator = "$in"
-	FoNin       FilterOperator = "$nin"
-	FoGt        FilterOperator = "$gt"
-	FoGte       FilterOperator = "$gte"
-	FoLt        FilterOperator = "$lt"
-	FoLte       FilterOperator = "$lte"
+	FoContains  = domain.FoContains
+	FoStartWith = domain.FoStartWith
+	FoEndWith   = domain.FoEndWith
+	FoRegex     = domain.FoRegex
+	FoEq        = domain.FoEq
+	FoNe        = domain.FoNe
+	FoIn        = domain.FoIn
+	FoNin       = domain.FoNin
+	FoGt        = domain.FoGt
+	FoGte       = domain.FoGte
+	FoLt        = domain.FoLt
+	FoLte       = domain.FoLte
+	FoAll       = domain.FoAll
 )
 
 type MongoSortBy string
@@ -38,10 +43,11 @@ type MongoSort struct {
 }
 
 type MongoAggregate struct {
-	Skip  *int64
-	Limit *int64
-	Sort  []MongoSort
-	Match bson.M
+	Skip    *int64
+	Limit   *int64
+	Sort    []MongoSort
+	Match   bson.M
+	Project []string
 }
 
 func NewMongoAggregate() *MongoAggregate {
@@ -77,6 +83,11 @@ func (a *MongoAggregate) BuildPipe() []bson.M {
 		}
 	}
 
+	// build project
+	if len(a.Project) > 0 {
+		pipe = append(pipe, MongoProject(a.Project...))
+	}
+
 	return pipe
 }
 
@@ -84,14 +95,36 @@ func MongoPipe(aggregate MongoAggregate) []bson.M {
 	return aggregate.BuildPipe()
 }
 
+// MongoProjection builds the projection document including the given fields, it's nil without fields
+func MongoProjection(fields ...string) bson.M {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	projection := bson.M{}
+
+	for _, v := range fields {
+		projection[v] = 1
+	}
+
+	return projection
+}
+
+func MongoProject(fields ...string) bson.M {
+	return bson.M{
+		"$project": MongoProjection(fields...),
+	}
+}
+
 func MongoMatch(filter bson.M) bson.M {
 	return bson.M{
 		"$match": filter,
 	}
 }
 
-func MongoSorting(sort ...MongoSort) bson.M {
-	s := bson.M{}
+// MongoSorting builds the $sort document, it's ordered since the keys are applied one after another
+func MongoSorting(sort ...MongoSort) bson.D {
+	s := bson.D{}
 
 	for _, v := range sort {
 		sortBy := 1
@@ -100,7 +133,7 @@ func MongoSorting(sort ...MongoSort) bson.M {
 			sortBy = -1
 		}
 
-		s[v.SortField] = sortBy
+		s = append(s, bson.E{Key: v.SortField, Value: sortBy})
 	}
 
 	return s
@@ -114,25 +147,27 @@ func MongoLimit(limit int64) bson.M {
 	return bson.M{"$limit": limit}
 }
 
+// MongoFilter builds the match document of a single condition. The string operators match the value literally
+// regardless of the case, only FoRegex takes the value as a regular expression.
 func MongoFilter(operator FilterOperator, field string, value interface{}) bson.M {
 	switch operator {
-	case FoEq, FoNe, FoIn, FoNin, FoGt, FoGte, FoLt, FoLte:
+	case FoEq, FoNe, FoIn, FoNin, FoGt, FoGte, FoLt, FoLte, FoAll:
 		return bson.M{
 			field: bson.M{
 				string(operator): value,
 			},
 		}
-	case FoContains, FoStartWith, FoEndWith:
-		var regexPattern string
+	case FoContains, FoStartWith, FoEndWith, FoRegex:
+		regexPattern := regexp.QuoteMeta(fmt.Sprint(value))
 		regexOpt := "i"
 
 		switch operator {
-		case FoContains:
-			regexPattern = fmt.Sprintf(".*%s.*", value)
 		case FoStartWith:
-			regexPattern = fmt.Sprintf("^%s", value)
+			regexPattern = "^" + regexPattern
 		case FoEndWith:
-			regexPattern = fmt.Sprintf("%s$", value)
+			regexPattern = regexPattern + "$"
+		case FoRegex:
+			regexPattern = fmt.Sprint(value)
 		}
 
 		return bson.M{
@@ -168,10 +203,18 @@ func BuildMongoOrders(orders string, separators ...string) []MongoSort {
 	}
 
 	for _, os := range orderSplit {
-		sortSplit := strings.Split(os, separatorOrderBy)
+		sortSplit := strings.Split(strings.TrimSpace(os), separatorOrderBy)
 
 		sortBy := SortByAsc
 
+		// a field without direction is sorted ascending
+		if len(sortSplit) == 1 && sortSplit[0] != "" {
+			ordersSlice = append(ordersSlice, MongoSort{
+				SortField: sortSplit[0],
+				SortBy:    sortBy,
+			})
+		}
+
 		if len(sortSplit) == 2 {
 
 			if sortSplit[1] == "-1" || strings.ToLower(sortSplit[1]) == "desc" {
@@ -188,21 +231,38 @@ func BuildMongoOrders(orders string, separators ...string) []MongoSort {
 	return ordersSlice
 }
 
+// MongoLookupOptions: Pipeline is run on the joined documents when it's set, it reads the variables of Let.
+// MongoDB before 5.0 doesn't take LocalField and ForeignField along a pipeline, it joins the documents by Let instead
 type MongoLookupOptions struct {
 	From         string
 	LocalField   string
 	ForeignField string
 	As           string
+	Let          bson.M
+	Pipeline     []bson.M
 }
 
 func MongoLookup(opt MongoLookupOptions) bson.M {
+	lookup := bson.M{
+		"from": opt.From,
+		"as":   opt.As,
+	}
+
+	if opt.LocalField != "" {
+		lookup["localField"] = opt.LocalField
+		lookup["foreignField"] = opt.ForeignField
+	}
+
+	if opt.Let != nil {
+		lookup["let"] = opt.Let
+	}
+
+	if opt.Pipeline != nil {
+		lookup["pipeline"] = opt.Pipeline
+	}
+
 	return bson.M{
-		"$lookup": bson.M{
-			"from":         opt.From,
-			"localField":   opt.LocalField,
-			"foreignField": opt.ForeignField,
-			"as":           opt.As,
-		},
+		"$lookup": lookup,
 	}
 }
 
@@ -222,6 +282,18 @@ func MongoUnwind(opt MongoUnwindOptions) bson.M {
 	}
 }
 
+func MongoGroup(id interface{}, fields bson.M) bson.M {
+	group := bson.M{"_id": id}
+
+	for k, v := range fields {
+		group[k] = v
+	}
+
+	return bson.M{
+		"$group": group,
+	}
+}
+
 func MongoIn(field string, inValues ...interface{}) bson.M {
 	return bson.M{
 		field: bson.M{
@@ -245,25 +317,33 @@ func MongoUnionWith(collection string, pipelines []bson.M) bson.M {
 	}
 }
 
+// MongoGraphLookupOptions: RestrictSearchWithMatch limits the documents walked through when it's set
 type MongoGraphLookupOptions struct {
-	From             string
-	StartWith        string
-	ConnectFromField string
-	ConnectToField   string
-	DepthField       string
-	As               string
+	From                    string
+	StartWith               string
+	ConnectFromField        string
+	ConnectToField          string
+	DepthField              string
+	As                      string
+	RestrictSearchWithMatch bson.M
 }
 
 func MongoGraphLookup(opt MongoGraphLookupOptions) bson.M {
+	lookup := bson.M{
+		"from":             opt.From,
+		"startWith":        opt.StartWith,
+		"connectFromField": opt.ConnectFromField,
+		"connectToField":   opt.ConnectToField,
+		"depthField":       opt.DepthField,
+		"as":               opt.As,
+	}
+
+	if opt.RestrictSearchWithMatch != nil {
+		lookup["restrictSearchWithMatch"] = opt.RestrictSearchWithMatch
+	}
+
 	return bson.M{
-		"$graphLookup": bson.M{
-			"from":             opt.From,
-			"startWith":        opt.StartWith,
-			"connectFromField": opt.ConnectFromField,
-			"connectToField":   opt.ConnectToField,
-			"depthField":       opt.DepthField,
-			"as":               opt.As,
-		},
+		"$graphLookup": lookup,
 	}
 }
 
@@ -276,14 +356,54 @@ func MongoDateToString(field, format string) bson.M {
 	}
 }
 
+// mongoErrCodeDocumentValidation is returned when a write is rejected by the collection's schema validator
+const mongoErrCodeDocumentValidation = 121
+
+func hasMongoWriteConcernError(err error) bool {
+	var writeErr mongo.WriteException
+	if errors.As(err, &writeErr) && writeErr.WriteConcernError != nil {
+		return true
+	}
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError != nil {
+		return true
+	}
+
+	return false
+}
+
+// ParseMongoError translates the driver errors into the domain errors, the original error is kept as the cause.
+// Errors which can't be translated are returned as is.
 func ParseMongoError(err error) error {
 	if err == nil {
 		return nil
 	}
 
-	if strings.Contains(err.Error(), "no documents in result") {
-		return errors.New("no document found")
+	if errors.Is(err, mongo.ErrNoDocuments) || strings.Contains(err.Error(), "no documents in result") {
+		return domain.NewNotFoundError("no document found", err)
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.NewConflictError("document already exists", err)
+	}
+
+	if mongo.IsTimeout(err) {
+		return domain.NewTimeoutError("database operation timed out", err)
+	}
+
+	if mongo.IsNetworkError(err) {
+		return domain.NewUnavailableError("database is unreachable", err)
+	}
+
+	if hasMongoWriteConcernError(err) {
+		return domain.NewUnavailableError("write concern could not be satisfied", err)
+	}
+
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoErrCodeDocumentValidation) {
+		return domain.NewValidationError("document failed validation", err)
 	}
 
-	return nil
+	return err
 }
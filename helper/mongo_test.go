@@ -0,0 +1,150 @@
+package helper_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParseMongoError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{
+			name: "No Documents",
+			err:  mongo.ErrNoDocuments,
+			kind: domain.ErrNotFound,
+		},
+		{
+			name: "No Documents - Command Error",
+			err:  mongo.CommandError{Message: mongo.ErrNoDocuments.Error()},
+			kind: domain.ErrNotFound,
+		},
+		{
+			name: "Duplicate Key",
+			err: mongo.WriteException{
+				WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}},
+			},
+			kind: domain.ErrConflict,
+		},
+		{
+			name: "Timeout",
+			err:  context.DeadlineExceeded,
+			kind: domain.ErrTimeout,
+		},
+		{
+			name: "Network",
+			err:  mongo.CommandError{Message: "connection reset", Labels: []string{"NetworkError"}},
+			kind: domain.ErrUnavailable,
+		},
+		{
+			name: "Write Concern",
+			err: mongo.WriteException{
+				WriteConcernError: &mongo.WriteConcernError{Code: 64, Message: "waiting for replication timed out"},
+			},
+			kind: domain.ErrUnavailable,
+		},
+		{
+			name: "Document Validation",
+			err: mongo.WriteException{
+				WriteErrors: mongo.WriteErrors{{Code: 121, Message: "Document failed validation"}},
+			},
+			kind: domain.ErrValidation,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := helper.ParseMongoError(c.err)
+
+			assert.ErrorIs(t, err, c.kind)
+			assert.Equal(t, c.err, errors.Unwrap(err))
+		})
+	}
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.Nil(t, helper.ParseMongoError(nil))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		original := errors.New("some error")
+
+		err := helper.ParseMongoError(original)
+
+		assert.Equal(t, original, err)
+		assert.Equal(t, domain.ErrorKind(""), domain.ErrorKindOf(err))
+	})
+}
+
+func TestMongoFilter(t *testing.T) {
+	cases := []struct {
+		name     string
+		operator helper.FilterOperator
+		value    interface{}
+		expected bson.M
+	}{
+		{
+			name:     "Equal",
+			operator: helper.FoEq,
+			value:    "(a+)+$",
+			expected: bson.M{"title": bson.M{"$eq": "(a+)+$"}},
+		},
+		{
+			name:     "Contains",
+			operator: helper.FoContains,
+			value:    "Title 1",
+			expected: bson.M{"title": primitive.Regex{Pattern: `Title 1`, Options: "i"}},
+		},
+		{
+			name:     "Contains - Malicious",
+			operator: helper.FoContains,
+			value:    "(a+)+$",
+			expected: bson.M{"title": primitive.Regex{Pattern: `\(a\+\)\+\$`, Options: "i"}},
+		},
+		{
+			name:     "Contains - Wildcard",
+			operator: helper.FoContains,
+			value:    ".*",
+			expected: bson.M{"title": primitive.Regex{Pattern: `\.\*`, Options: "i"}},
+		},
+		{
+			name:     "Start With - Malicious",
+			operator: helper.FoStartWith,
+			value:    "^[a-z]|",
+			expected: bson.M{"title": primitive.Regex{Pattern: `^\^\[a-z\]\|`, Options: "i"}},
+		},
+		{
+			name:     "End With - Malicious",
+			operator: helper.FoEndWith,
+			value:    "a{1,100}\\",
+			expected: bson.M{"title": primitive.Regex{Pattern: `a\{1,100\}\\$`, Options: "i"}},
+		},
+		{
+			name:     "Regex",
+			operator: helper.FoRegex,
+			value:    "^title [0-9]+$",
+			expected: bson.M{"title": primitive.Regex{Pattern: "^title [0-9]+$", Options: "i"}},
+		},
+		{
+			name:     "Unknown",
+			operator: helper.FilterOperator("$where"),
+			value:    "true",
+			expected: nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, helper.MongoFilter(c.operator, "title", c.value))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"net/url"
+	"strconv"
+
+	"github.com/ariefsn/go-resik/common"
+)
+
+func pageLink(path string, query url.Values, key string, value string) string {
+	values := url.Values{}
+
+	for k, v := range query {
+		values[k] = v
+	}
+
+	values.Set(key, value)
+
+	return path + "?" + values.Encode()
+}
+
+// PageLinks builds the links of a skip/limit page from the request path and query, the other query parameters are kept.
+// A negative limit reads everything so there are no previous, next or last pages.
+func PageLinks(path string, query url.Values, skip, limit, total int64) common.PageLinks {
+	link := func(skip int64) string {
+		return pageLink(path, query, "skip", strconv.FormatInt(skip, 10))
+	}
+
+	links := common.PageLinks{
+		Self:  link(skip),
+		First: link(0),
+	}
+
+	if limit < 1 {
+		return links
+	}
+
+	if skip > 0 {
+		links.Prev = link(max(skip-limit, 0))
+	}
+
+	if skip+limit < total {
+		links.Next = link(skip + limit)
+	}
+
+	if total > 0 {
+		links.Last = link((total - 1) / limit * limit)
+	}
+
+	return links
+}
+
+// CursorLinks builds the links of a keyset page from the request path and query, the empty cursors have no link
+func CursorLinks(path string, query url.Values, next, prev string) common.PageLinks {
+	links := common.PageLinks{
+		Self:  pageLink(path, query, "cursor", query.Get("cursor")),
+		First: pageLink(path, query, "cursor", ""),
+	}
+
+	if prev != "" {
+		links.Prev = pageLink(path, query, "cursor", prev)
+	}
+
+	if next != "" {
+		links.Next = pageLink(path, query, "cursor", next)
+	}
+
+	return links
+}
@@ -0,0 +1,88 @@
+package helper_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/ariefsn/go-resik/common"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageLinks(t *testing.T) {
+	query := url.Values{"limit": {"10"}, "filter[title]": {"a b"}}
+
+	cases := []struct {
+		name     string
+		skip     int64
+		limit    int64
+		total    int64
+		expected common.PageLinks
+	}{
+		{
+			name:  "Middle Page",
+			skip:  15,
+			limit: 10,
+			total: 42,
+			expected: common.PageLinks{
+				Self:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=15",
+				First: "/todos?filter%5Btitle%5D=a+b&limit=10&skip=0",
+				Prev:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=5",
+				Next:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=25",
+				Last:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=40",
+			},
+		},
+		{
+			name:  "Last Page",
+			skip:  5,
+			limit: 10,
+			total: 15,
+			expected: common.PageLinks{
+				Self:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=5",
+				First: "/todos?filter%5Btitle%5D=a+b&limit=10&skip=0",
+				Prev:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=0",
+				Last:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=10",
+			},
+		},
+		{
+			name:  "Empty",
+			skip:  0,
+			limit: 10,
+			total: 0,
+			expected: common.PageLinks{
+				Self:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=0",
+				First: "/todos?filter%5Btitle%5D=a+b&limit=10&skip=0",
+			},
+		},
+		{
+			name:  "Unlimited",
+			skip:  3,
+			limit: -1,
+			total: 42,
+			expected: common.PageLinks{
+				Self:  "/todos?filter%5Btitle%5D=a+b&limit=10&skip=3",
+				First: "/todos?filter%5Btitle%5D=a+b&limit=10&skip=0",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, helper.PageLinks("/todos", query, c.skip, c.limit, c.total))
+		})
+	}
+
+	assert.Equal(t, url.Values{"limit": {"10"}, "filter[title]": {"a b"}}, query)
+}
+
+func TestCursorLinks(t *testing.T) {
+	query := url.Values{"cursor": {"abc"}}
+
+	assert.Equal(t, common.PageLinks{
+		Self:  "/todos?cursor=abc",
+		First: "/todos?cursor=",
+		Prev:  "/todos?cursor=prev",
+	}, helper.CursorLinks("/todos", query, "", "prev"))
+
+	assert.Equal(t, []string{"/todos?cursor=abc", "self", "/todos?cursor=", "first", "/todos?cursor=prev", "prev"}, helper.CursorLinks("/todos", query, "", "prev").ToLinks())
+}
@@ -0,0 +1,227 @@
+package helper
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+)
+
+// sortOrders are the directions of the sort query, the ones BuildMongoOrders reads
+var sortOrders = map[string]domain.SortOrder{
+	"":     domain.SortAsc,
+	"asc":  domain.SortAsc,
+	"1":    domain.SortAsc,
+	"desc": domain.SortDesc,
+	"-1":   domain.SortDesc,
+}
+
+// ParseSort parses the `field*desc,field2` sort query like BuildMongoOrders, only the given fields are accepted. A
+// direction other than asc, desc, 1 or -1 and a term with several directions are rejected instead of being ignored.
+func ParseSort(orders string, fields []string) ([]domain.Sort, error) {
+	result := []domain.Sort{}
+
+	if strings.TrimSpace(orders) == "" {
+		return result, nil
+	}
+
+	for _, v := range strings.Split(orders, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		field, direction, _ := strings.Cut(v, "*")
+
+		if strings.Contains(direction, "*") {
+			return nil, domain.NewFieldValidationError(domain.FieldError{
+				Field:   "sort",
+				Rule:    "sort",
+				Message: fmt.Sprintf("%s is not a valid sort, expected field*direction", v),
+			})
+		}
+
+		if !slices.Contains(fields, field) {
+			return nil, domain.NewFieldValidationError(domain.FieldError{
+				Field:   "sort",
+				Rule:    "oneof",
+				Message: fmt.Sprintf("sort field %s is not supported", field),
+			})
+		}
+
+		order, ok := sortOrders[strings.ToLower(direction)]
+		if !ok {
+			return nil, domain.NewFieldValidationError(domain.FieldError{
+				Field:   "sort",
+				Rule:    "oneof",
+				Message: fmt.Sprintf("sort direction %s is not supported, expected asc or desc", direction),
+			})
+		}
+
+		result = append(result, domain.Sort{
+			Field: field,
+			Order: order,
+		})
+	}
+
+	return result, nil
+}
+
+// ParseFields parses the comma separated `fields` query, only the given fields are accepted. An empty query selects every field.
+func ParseFields(fields string, allowed []string) ([]string, error) {
+	result := []string{}
+
+	for _, v := range strings.Split(fields, ",") {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		if !slices.Contains(allowed, v) {
+			return nil, domain.NewFieldValidationError(domain.FieldError{
+				Field:   "fields",
+				Rule:    "oneof",
+				Message: fmt.Sprintf("field %s is not supported", v),
+			})
+		}
+
+		if !slices.Contains(result, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result, nil
+}
+
+var filterKeyPattern = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+func parseFilterValue(fieldType domain.FieldType, value string) (interface{}, string, bool) {
+	switch fieldType {
+	case domain.FieldTypeBool:
+		v, err := strconv.ParseBool(value)
+		return v, "boolean", err == nil
+	case domain.FieldTypeTime:
+		for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+			if v, err := time.Parse(layout, value); err == nil {
+				return v, "datetime", true
+			}
+		}
+
+		return nil, "datetime", false
+	case domain.FieldTypeTags:
+		return strings.ToLower(value), "", true
+	case domain.FieldTypePriority:
+		v, ok := domain.ParsePriority(strings.ToLower(value))
+		return v, "oneof", ok
+	}
+
+	return value, "", true
+}
+
+// ParseFilter parses the `filter[field][$op]=value` queries into an And group of domain.Filter, `filter[field]=value` is a shorthand of $eq.
+// Only the given fields and the operators supported by their type are accepted, $in, $nin and $all take comma separated values.
+func ParseFilter(queries map[string]string, fields map[string]domain.FieldType) (*domain.Filter, error) {
+	var conditions []*domain.Filter
+
+	keys := []string{}
+	for k := range queries {
+		if k == "filter" || strings.HasPrefix(k, "filter[") {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	errs := []domain.FieldError{}
+
+	for _, k := range keys {
+		matches := filterKeyPattern.FindStringSubmatch(k)
+		if matches == nil {
+			errs = append(errs, domain.FieldError{
+				Field:   k,
+				Rule:    "filter",
+				Message: fmt.Sprintf("%s is not a valid filter, expected filter[field][$operator]", k),
+			})
+			continue
+		}
+
+		field := matches[1]
+		operator := domain.FoEq
+		if matches[2] != "" {
+			operator = domain.FilterOperator(matches[2])
+		}
+
+		fieldType, ok := fields[field]
+		if !ok {
+			errs = append(errs, domain.FieldError{
+				Field:   k,
+				Rule:    "oneof",
+				Message: fmt.Sprintf("filter field %s is not supported", field),
+			})
+			continue
+		}
+
+		if !slices.Contains(domain.FieldOperators[fieldType], operator) {
+			errs = append(errs, domain.FieldError{
+				Field:   k,
+				Rule:    "oneof",
+				Message: fmt.Sprintf("filter operator %s is not supported by %s", operator, field),
+			})
+			continue
+		}
+
+		if operator == domain.FoRegex {
+			if err := domain.ValidateRegex(queries[k]); err != nil {
+				errs = append(errs, domain.FieldError{
+					Field:   k,
+					Rule:    "regex",
+					Message: fmt.Sprintf("%s %s", k, err.Error()),
+				})
+				continue
+			}
+		}
+
+		values := []string{queries[k]}
+		if operator.IsList() {
+			values = strings.Split(queries[k], ",")
+		}
+
+		parsed := []interface{}{}
+
+		for _, v := range values {
+			value, rule, ok := parseFilterValue(fieldType, strings.TrimSpace(v))
+			if !ok {
+				errs = append(errs, domain.FieldError{
+					Field:   k,
+					Rule:    rule,
+					Message: fmt.Sprintf("%s is not a valid %s value", v, fieldType),
+				})
+				break
+			}
+
+			parsed = append(parsed, value)
+		}
+
+		if len(parsed) != len(values) {
+			continue
+		}
+
+		var value interface{} = parsed
+		if !operator.IsList() {
+			value = parsed[0]
+		}
+
+		conditions = append(conditions, domain.Where(field, operator, value))
+	}
+
+	if len(errs) > 0 {
+		return nil, domain.NewFieldValidationError(errs...)
+	}
+
+	return domain.And(conditions...), nil
+}
@@ -0,0 +1,192 @@
+package helper_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildMongoOrders(t *testing.T) {
+	res := helper.BuildMongoOrders("title*desc, isCompleted,createdAt*-1,updatedAt*asc")
+
+	assert.Equal(t, []helper.MongoSort{
+		{SortField: "title", SortBy: helper.SortByDesc},
+		{SortField: "isCompleted", SortBy: helper.SortByAsc},
+		{SortField: "createdAt", SortBy: helper.SortByDesc},
+		{SortField: "updatedAt", SortBy: helper.SortByAsc},
+	}, res)
+
+	assert.Equal(t, []helper.MongoSort{}, helper.BuildMongoOrders(""))
+}
+
+func TestParseSort(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		res, err := helper.ParseSort("title*desc,createdAt", domain.TodoSortFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Sort{
+			{Field: "title", Order: domain.SortDesc},
+			{Field: "createdAt", Order: domain.SortAsc},
+		}, res)
+	})
+
+	t.Run("Success - Empty", func(t *testing.T) {
+		res, err := helper.ParseSort("", domain.TodoSortFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Sort{}, res)
+	})
+
+	t.Run("Success - Directions", func(t *testing.T) {
+		res, err := helper.ParseSort("title*DESC, createdAt*-1,updatedAt*asc,isCompleted*1", domain.TodoSortFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.Sort{
+			{Field: "title", Order: domain.SortDesc},
+			{Field: "createdAt", Order: domain.SortDesc},
+			{Field: "updatedAt", Order: domain.SortAsc},
+			{Field: "isCompleted", Order: domain.SortAsc},
+		}, res)
+	})
+
+	cases := []struct {
+		name    string
+		sort    string
+		rule    string
+		message string
+	}{
+		{name: "Not Allowed", sort: "title,password*desc", rule: "oneof", message: "sort field password is not supported"},
+		{name: "Unknown Direction", sort: "title*bogus", rule: "oneof", message: "sort direction bogus is not supported, expected asc or desc"},
+		{name: "Several Directions", sort: "title*desc*asc", rule: "sort", message: "title*desc*asc is not a valid sort, expected field*direction"},
+	}
+
+	for _, c := range cases {
+		t.Run("Failed - "+c.name, func(t *testing.T) {
+			res, err := helper.ParseSort(c.sort, domain.TodoSortFields)
+
+			var domainErr *domain.Error
+
+			assert.Nil(t, res)
+			assert.ErrorIs(t, err, domain.ErrValidation)
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, []domain.FieldError{{Field: "sort", Rule: c.rule, Message: c.message}}, domainErr.Fields)
+		})
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		res, err := helper.ParseFilter(map[string]string{
+			"filter[createdAt][$gte]":  "2023-10-01T08:00:00Z",
+			"filter[id][$in]":          "1, 2",
+			"filter[isCompleted][$eq]": "true",
+			"filter[title]":            "Title 1",
+			"skip":                     "0",
+		}, domain.TodoFilterFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.And(
+			domain.Where("createdAt", domain.FoGte, time.Date(2023, 10, 1, 8, 0, 0, 0, time.UTC)),
+			domain.Where("id", domain.FoIn, []interface{}{"1", "2"}),
+			domain.Where("isCompleted", domain.FoEq, true),
+			domain.Where("title", domain.FoEq, "Title 1"),
+		), res)
+	})
+
+	t.Run("Success - Tags", func(t *testing.T) {
+		res, err := helper.ParseFilter(map[string]string{
+			"filter[tags]":       "Home",
+			"filter[tags][$all]": "home, Work",
+			"filter[tags][$nin]": "done",
+		}, domain.TodoFilterFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.And(
+			domain.Where("tags", domain.FoEq, "home"),
+			domain.Where("tags", domain.FoAll, []interface{}{"home", "work"}),
+			domain.Where("tags", domain.FoNin, []interface{}{"done"}),
+		), res)
+	})
+
+	t.Run("Success - Priority", func(t *testing.T) {
+		res, err := helper.ParseFilter(map[string]string{
+			"filter[priority][$gte]": "High",
+			"filter[priority][$nin]": "urgent",
+		}, domain.TodoFilterFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.And(
+			domain.Where("priority", domain.FoGte, domain.PriorityHigh),
+			domain.Where("priority", domain.FoNin, []interface{}{domain.PriorityUrgent}),
+		), res)
+	})
+
+	t.Run("Success - Empty", func(t *testing.T) {
+		res, err := helper.ParseFilter(map[string]string{}, domain.TodoFilterFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.And(), res)
+	})
+
+	cases := []struct {
+		name  string
+		key   string
+		value string
+		rule  string
+	}{
+		{name: "Failed - Malformed", key: "filter[title][$eq][x]", value: "a", rule: "filter"},
+		{name: "Failed - Unknown Field", key: "filter[password]", value: "a", rule: "oneof"},
+		{name: "Failed - Unknown Operator", key: "filter[title][$like]", value: "a", rule: "oneof"},
+		{name: "Failed - Unsupported Operator", key: "filter[isCompleted][$contains]", value: "true", rule: "oneof"},
+		{name: "Failed - Unsupported Tags Operator", key: "filter[tags][$contains]", value: "home", rule: "oneof"},
+		{name: "Failed - Invalid Regex", key: "filter[title][$regex]", value: "[a-", rule: "regex"},
+		{name: "Failed - Nested Repetition", key: "filter[title][$regex]", value: "(a+)+$", rule: "regex"},
+		{name: "Failed - Repeated Alternation", key: "filter[title][$regex]", value: "^(a|aa)*b", rule: "regex"},
+		{name: "Failed - Backreference", key: "filter[title][$regex]", value: `(a)\1`, rule: "regex"},
+		{name: "Failed - Long Regex", key: "filter[title][$regex]", value: strings.Repeat("a", domain.MaxRegexLength+1), rule: "regex"},
+		{name: "Failed - Invalid Boolean", key: "filter[isCompleted][$eq]", value: "yes", rule: "boolean"},
+		{name: "Failed - Invalid Datetime", key: "filter[updatedAt][$nin]", value: "2023-10-01,tomorrow", rule: "datetime"},
+		{name: "Failed - Invalid Priority", key: "filter[priority]", value: "asap", rule: "oneof"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := helper.ParseFilter(map[string]string{c.key: c.value}, domain.TodoFilterFields)
+
+			var domainErr *domain.Error
+
+			assert.ErrorIs(t, err, domain.ErrValidation)
+			assert.ErrorAs(t, err, &domainErr)
+			assert.Equal(t, []domain.FieldError{{Field: c.key, Rule: c.rule, Message: domainErr.Fields[0].Message}}, domainErr.Fields)
+		})
+	}
+}
+
+func TestParseFields(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		res, err := helper.ParseFields("title, isCompleted,title,", domain.TodoFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"title", "isCompleted"}, res)
+
+		res, err = helper.ParseFields("", domain.TodoFields)
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{}, res)
+	})
+
+	t.Run("Failed - Unknown Field", func(t *testing.T) {
+		res, err := helper.ParseFields("title,password", domain.TodoFields)
+
+		var domainErr *domain.Error
+
+		assert.Nil(t, res)
+		assert.ErrorAs(t, err, &domainErr)
+		assert.Equal(t, "fields", domainErr.Fields[0].Field)
+		assert.Equal(t, "oneof", domainErr.Fields[0].Rule)
+	})
+}
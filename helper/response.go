@@ -1,9 +1,35 @@
 package helper
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/ariefsn/go-resik/common"
+	"github.com/ariefsn/go-resik/domain"
 )
 
+type ErrorFormat string
+
+const (
+	ErrorFormatEnvelope ErrorFormat = "envelope"
+	ErrorFormatProblem  ErrorFormat = "problem"
+)
+
+// ParseErrorFormat returns the error format of the value, the unknown ones are rejected
+func ParseErrorFormat(value string) (ErrorFormat, error) {
+	switch format := ErrorFormat(value); format {
+	case ErrorFormatEnvelope, ErrorFormatProblem:
+		return format, nil
+	}
+
+	return "", fmt.Errorf("unsupported error format: %s", value)
+}
+
+const ContentTypeProblemJson = "application/problem+json"
+
 func JsonSuccess(data interface{}) common.M {
 	return common.ResponseModel{
 		Status:  true,
@@ -12,10 +38,98 @@ func JsonSuccess(data interface{}) common.M {
 	}.ToM()
 }
 
+func selectM(m common.M, fields []string) common.M {
+	res := common.M{}
+
+	for _, v := range append([]string{"id"}, fields...) {
+		if value, ok := m[v]; ok {
+			res[v] = value
+		}
+	}
+
+	return res
+}
+
+// SelectFields keeps only the id and the given fields of the data, an object or a list of objects. Without fields the data is returned as is.
+func SelectFields(data interface{}, fields []string) interface{} {
+	if len(fields) == 0 {
+		return data
+	}
+
+	jByte, _ := json.Marshal(data)
+
+	list := []common.M{}
+
+	if err := json.Unmarshal(jByte, &list); err == nil {
+		for i, v := range list {
+			list[i] = selectM(v, fields)
+		}
+
+		return list
+	}
+
+	m := common.M{}
+	json.Unmarshal(jByte, &m)
+
+	return selectM(m, fields)
+}
+
+// ErrorCode returns the machine readable code of the error, the domain error kind or the http status text otherwise
+func ErrorCode(err error) string {
+	if kind := domain.ErrorKindOf(err); kind != "" {
+		return string(kind)
+	}
+
+	return strings.ToUpper(strings.ReplaceAll(http.StatusText(HttpStatus(err)), " ", "_"))
+}
+
+// ErrorFields returns the invalid fields of a validation error
+func ErrorFields(err error) []common.ResponseError {
+	var domainErr *domain.Error
+
+	if !errors.As(err, &domainErr) || len(domainErr.Fields) == 0 {
+		return nil
+	}
+
+	fields := []common.ResponseError{}
+
+	for _, v := range domainErr.Fields {
+		fields = append(fields, common.ResponseError{
+			Field:   v.Field,
+			Code:    v.Rule,
+			Message: v.Message,
+		})
+	}
+
+	return fields
+}
+
+// JsonProblem builds the RFC 7807 problem details of the error, instance is the URI of the failed request
+func JsonProblem(err error, instance string) common.M {
+	status := HttpStatus(err)
+
+	return common.ProblemModel{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: instance,
+		Code:     ErrorCode(err),
+		Errors:   ErrorFields(err),
+	}.ToM()
+}
+
+// JsonError builds the error response in the configured error format
 func JsonError(err error) common.M {
+	if Env().App.ErrorFormat == ErrorFormatProblem {
+		return JsonProblem(err, "")
+	}
+
 	return common.ResponseModel{
 		Status:  false,
 		Data:    nil,
 		Message: err.Error(),
+		Code:    ErrorCode(err),
+		Errors:  ErrorFields(err),
 	}.ToM()
 }
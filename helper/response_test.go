@@ -0,0 +1,134 @@
+package helper_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ariefsn/go-resik/common"
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonError(t *testing.T) {
+	cases := []struct {
+		name   string
+		err    error
+		result common.ResponseModel
+	}{
+		{
+			name: "Domain Error",
+			err:  domain.NewNotFoundError("todo not found"),
+			result: common.ResponseModel{
+				Message: "todo not found",
+				Code:    "NOT_FOUND",
+			},
+		},
+		{
+			name: "Validation Error",
+			err: domain.NewFieldValidationError(domain.FieldError{
+				Field:   "title",
+				Rule:    "required",
+				Message: "title is required",
+			}),
+			result: common.ResponseModel{
+				Message: "validation failed: title (required)",
+				Code:    "VALIDATION",
+				Errors: []common.ResponseError{
+					{Field: "title", Code: "required", Message: "title is required"},
+				},
+			},
+		},
+		{
+			name: "Fiber Error",
+			err:  fiber.ErrMethodNotAllowed,
+			result: common.ResponseModel{
+				Message: "Method Not Allowed",
+				Code:    "METHOD_NOT_ALLOWED",
+			},
+		},
+		{
+			name: "Unknown Error",
+			err:  errors.New("some error"),
+			result: common.ResponseModel{
+				Message: "some error",
+				Code:    "INTERNAL_SERVER_ERROR",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, err := helper.FromJson[common.ResponseModel](helper.JsonError(c.err))
+
+			assert.Nil(t, err)
+			assert.Equal(t, c.result, result)
+		})
+	}
+
+	t.Run("Omit Empty", func(t *testing.T) {
+		result := helper.JsonError(errors.New("some error"))
+
+		assert.NotContains(t, result, "errors")
+		assert.Contains(t, result, "code")
+		assert.NotContains(t, helper.JsonSuccess("OK"), "code")
+	})
+}
+
+func TestJsonProblem(t *testing.T) {
+	err := domain.NewFieldValidationError(domain.FieldError{
+		Field:   "title",
+		Rule:    "required",
+		Message: "title is required",
+	})
+
+	result, _ := helper.FromJson[common.ProblemModel](helper.JsonProblem(err, "/v1/todos"))
+
+	assert.Equal(t, common.ProblemModel{
+		Type:     "about:blank",
+		Title:    "Bad Request",
+		Status:   400,
+		Detail:   "validation failed: title (required)",
+		Instance: "/v1/todos",
+		Code:     "VALIDATION",
+		Errors: []common.ResponseError{
+			{Field: "title", Code: "required", Message: "title is required"},
+		},
+	}, result)
+
+	t.Run("Problem Format", func(t *testing.T) {
+		helper.Env().App.ErrorFormat = helper.ErrorFormatProblem
+		defer func() {
+			helper.Env().App.ErrorFormat = helper.ErrorFormatEnvelope
+		}()
+
+		result := helper.JsonError(errors.New("some error"))
+
+		assert.Equal(t, float64(500), result["status"])
+		assert.Equal(t, "some error", result["detail"])
+		assert.NotContains(t, result, "message")
+	})
+}
+
+func TestParseErrorFormat(t *testing.T) {
+	for _, v := range []helper.ErrorFormat{helper.ErrorFormatEnvelope, helper.ErrorFormatProblem} {
+		format, err := helper.ParseErrorFormat(string(v))
+
+		assert.Nil(t, err)
+		assert.Equal(t, v, format)
+	}
+
+	format, err := helper.ParseErrorFormat("problems")
+
+	assert.EqualError(t, err, "unsupported error format: problems")
+	assert.Empty(t, format)
+}
+
+func TestSelectFields(t *testing.T) {
+	data := domain.Todo{ID: "1", Title: "Title 1", Description: "Description 1"}
+
+	assert.Equal(t, data, helper.SelectFields(data, nil))
+	assert.Equal(t, common.M{"id": "1", "title": "Title 1"}, helper.SelectFields(&data, []string{"title", "createdAt"}))
+	assert.Equal(t, []common.M{{"id": "1", "isCompleted": false}}, helper.SelectFields([]domain.Todo{data}, []string{"isCompleted"}))
+}
@@ -0,0 +1,101 @@
+package helper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/go-playground/validator/v10"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// report the json names, those are the ones the clients know about
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+
+		if name == "-" {
+			return ""
+		}
+
+		return name
+	})
+
+	v.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+
+	// a tag is stored trimmed and lower cased, the commas separate the tags in the filters
+	v.RegisterValidation("tag", func(fl validator.FieldLevel) bool {
+		tag := strings.TrimSpace(fl.Field().String())
+
+		return tag != "" && utf8.RuneCountInString(tag) <= 50 && !strings.Contains(tag, ",")
+	})
+
+	return v
+}
+
+func validationMessage(fe validator.FieldError) string {
+	isString := fe.Kind() == reflect.String
+
+	switch fe.Tag() {
+	case "required", "notblank":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "min":
+		if isString {
+			return fmt.Sprintf("%s must be at least %s characters long", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be %s or greater", fe.Field(), fe.Param())
+	case "max":
+		if isString {
+			return fmt.Sprintf("%s must be at most %s characters long", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be %s or less", fe.Field(), fe.Param())
+	case "len":
+		return fmt.Sprintf("%s must be exactly %s characters long", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", fe.Field(), strings.Join(strings.Fields(fe.Param()), ", "))
+	case "datetime":
+		return fmt.Sprintf("%s must be a date time with the format %s", fe.Field(), fe.Param())
+	case "tag":
+		return fmt.Sprintf("%s must be a non blank tag of at most 50 characters without commas", fe.Field())
+	case "email", "url", "uuid", "hexadecimal", "mongodb":
+		return fmt.Sprintf("%s must be a valid %s", fe.Field(), fe.Tag())
+	}
+
+	return fmt.Sprintf("%s failed on the %s rule", fe.Field(), fe.Tag())
+}
+
+// Validate runs the `validate` struct tags of the given value.
+// An invalid value is reported as a domain validation error listing every offending field.
+func Validate(v interface{}) error {
+	err := validate.Struct(v)
+
+	if err == nil {
+		return nil
+	}
+
+	var validationErrors validator.ValidationErrors
+
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	fields := []domain.FieldError{}
+
+	for _, fe := range validationErrors {
+		fields = append(fields, domain.FieldError{
+			Field:   fe.Field(),
+			Rule:    fe.Tag(),
+			Message: validationMessage(fe),
+		})
+	}
+
+	return domain.NewFieldValidationError(fields...)
+}
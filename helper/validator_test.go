@@ -0,0 +1,104 @@
+package helper_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ariefsn/go-resik/domain"
+	"github.com/ariefsn/go-resik/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		err := helper.Validate(&domain.TodoDto{
+			Title:       "Title 1",
+			Description: "Description 1",
+		})
+
+		assert.Nil(t, err)
+	})
+
+	t.Run("Failed - Required", func(t *testing.T) {
+		err := helper.Validate(&domain.TodoDto{
+			Title: "   ",
+		})
+
+		var domainErr *domain.Error
+
+		assert.ErrorIs(t, err, domain.ErrValidation)
+		assert.True(t, errors.As(err, &domainErr))
+		assert.Equal(t, "validation failed: title (notblank), description (required)", err.Error())
+		assert.Equal(t, []domain.FieldError{
+			{Field: "title", Rule: "notblank", Message: "title is required"},
+			{Field: "description", Rule: "required", Message: "description is required"},
+		}, domainErr.Fields)
+	})
+
+	t.Run("Failed - Length", func(t *testing.T) {
+		err := helper.Validate(&domain.TodoDto{
+			Title:       strings.Repeat("a", 256),
+			Description: "Description 1",
+		})
+
+		var domainErr *domain.Error
+
+		assert.True(t, errors.As(err, &domainErr))
+		assert.Equal(t, []domain.FieldError{
+			{Field: "title", Rule: "max", Message: "title must be at most 255 characters long"},
+		}, domainErr.Fields)
+	})
+
+	t.Run("Failed - Tags", func(t *testing.T) {
+		err := helper.Validate(&domain.TodoDto{
+			Title:       "Title 1",
+			Description: "Description 1",
+			Tags:        []string{"home", " ", "a,b", strings.Repeat("a", 51)},
+		})
+
+		var domainErr *domain.Error
+
+		assert.True(t, errors.As(err, &domainErr))
+		assert.Equal(t, []domain.FieldError{
+			{Field: "tags[1]", Rule: "tag", Message: "tags[1] must be a non blank tag of at most 50 characters without commas"},
+			{Field: "tags[2]", Rule: "tag", Message: "tags[2] must be a non blank tag of at most 50 characters without commas"},
+			{Field: "tags[3]", Rule: "tag", Message: "tags[3] must be a non blank tag of at most 50 characters without commas"},
+		}, domainErr.Fields)
+	})
+
+	t.Run("Failed - Priority", func(t *testing.T) {
+		err := helper.Validate(&domain.TodoDto{
+			Title:       "Title 1",
+			Description: "Description 1",
+			Priority:    "asap",
+		})
+
+		var domainErr *domain.Error
+
+		assert.True(t, errors.As(err, &domainErr))
+		assert.Equal(t, []domain.FieldError{
+			{Field: "priority", Rule: "oneof", Message: "priority must be one of [low, medium, high, urgent]"},
+		}, domainErr.Fields)
+	})
+
+	t.Run("Failed - Enum And Format", func(t *testing.T) {
+		payload := struct {
+			Status string `json:"status" validate:"oneof=open done"`
+			Email  string `json:"email" validate:"omitempty,email"`
+		}{
+			Status: "unknown",
+			Email:  "not-an-email",
+		}
+
+		err := helper.Validate(&payload)
+
+		var domainErr *domain.Error
+
+		assert.True(t, errors.As(err, &domainErr))
+		assert.Equal(t, []domain.FieldError{
+			{Field: "status", Rule: "oneof", Message: "status must be one of [open, done]"},
+			{Field: "email", Rule: "email", Message: "email must be a valid email"},
+		}, domainErr.Fields)
+	})
+}
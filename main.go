@@ -1,12 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/ariefsn/go-resik/app/todo/delivery/api"
-	"github.com/ariefsn/go-resik/app/todo/repository/mongo"
+	"github.com/ariefsn/go-resik/app/todo/repository"
 	"github.com/ariefsn/go-resik/app/todo/service"
+	todoListApi "github.com/ariefsn/go-resik/app/todo_list/delivery/api"
+	todoListRepository "github.com/ariefsn/go-resik/app/todo_list/repository"
+	todoListService "github.com/ariefsn/go-resik/app/todo_list/service"
+	todoRevisionRepository "github.com/ariefsn/go-resik/app/todo_revision/repository"
 	"github.com/ariefsn/go-resik/common"
+	"github.com/ariefsn/go-resik/domain"
 	"github.com/ariefsn/go-resik/helper"
 	"github.com/ariefsn/go-resik/logger"
 	"github.com/gofiber/fiber/v2"
@@ -18,25 +25,84 @@ func init() {
 	logger.InitLogger()
 }
 
+// purgeTrash purges the todos past the trash retention on every tick, a zero interval disables it
+func purgeTrash(ctx context.Context, todoSvc domain.TodoService, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := todoSvc.Purge(ctx)
+			if err != nil {
+				logger.Error(err)
+				continue
+			}
+
+			logger.Info("[TRASH]", common.M{
+				"purged": purged,
+			})
+		}
+	}
+}
+
 func main() {
 	env := helper.Env()
 
 	// Setup db
-	dbEnv := env.Mongo
-	dbAddress := fmt.Sprintf("mongodb://%s:%s@%s:%s", dbEnv.User, dbEnv.Password, dbEnv.Host, dbEnv.Port)
-	client, _ := helper.MongoClient(dbAddress)
-	db := client.Database(dbEnv.Db)
+	db, err := helper.NewDatabase(env.DbDriver)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	defer db.Close()
 
 	// Setup Repositories
-	todoRepo := mongo.NewMongoTodoRepository(db)
+	todoRepo, err := repository.NewTodoRepository(db)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	listRepo, err := todoListRepository.NewTodoListRepository(db, todoRepo)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	revisionRepo, err := todoRevisionRepository.NewTodoRevisionRepository(db)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	if err := repository.EnsureTodoSchema(context.Background(), db); err != nil {
+		logger.Fatal(err)
+	}
+
+	if err := todoListRepository.EnsureTodoListSchema(context.Background(), db); err != nil {
+		logger.Fatal(err)
+	}
+
+	if err := todoRevisionRepository.EnsureTodoRevisionSchema(context.Background(), db); err != nil {
+		logger.Fatal(err)
+	}
 
 	// Setup Services
-	todoSvc := service.NewTodoService(todoRepo)
+	todoSvc := service.NewTodoService(todoRepo, listRepo, revisionRepo, db, env.Trash.Retention)
+	listSvc := todoListService.NewTodoListService(listRepo, todoRepo, revisionRepo, db)
+
+	go purgeTrash(context.Background(), todoSvc, env.Trash.PurgeInterval)
 
 	// Setup Apis
 	todoApi := api.NewTodoApi(todoSvc)
+	listApi := todoListApi.NewTodoListApi(listSvc)
 
-	app := fiber.New()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: helper.ErrorHandler,
+	})
 
 	app.Use(fiberLogger.New(fiberLogger.Config{
 		Format: "[${time}] ${status} - ${latency} ${method} ${path}\n",
@@ -46,8 +112,9 @@ func main() {
 		return c.JSON(helper.JsonSuccess("OK"))
 	})
 
-	v1 := app.Group("/v1")
+	v1 := app.Group("/v1", helper.Actor)
 	v1.Mount("/todos", todoApi)
+	v1.Mount("/lists", listApi)
 
 	app.Use(func(c *fiber.Ctx) error {
 		logger.Info("[OUTBOND]", common.M{